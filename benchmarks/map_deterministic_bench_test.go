@@ -0,0 +1,129 @@
+package benchmarks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+// Deterministic map encoding benchmarks. The runtime does not expose its
+// sort step, so each benchmark pairs the deterministic encoders with an
+// append-only baseline over the same map: "Unsorted" writes entries in
+// map iteration order and "Presorted" writes them from a key list sorted
+// up front. The gap between those and the deterministic sub-benchmarks
+// is the cost of collecting and sorting the keys.
+
+func strStrMap(n int) map[string]string {
+	m := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		m[fmt.Sprintf("key-%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	return m
+}
+
+// sortedKeys returns m's keys in deterministic order: shorter keys
+// first, then bytewise, matching the order of their text encodings.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(len(a), len(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return keys
+}
+
+func BenchmarkMapDeterministic_StrStr(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		m := strStrMap(n)
+		keys := sortedKeys(m)
+		pairs := make([]cbor.RawPair, 0, n)
+		for k, v := range m {
+			pairs = append(pairs, cbor.RawPair{Key: cbor.AppendString(nil, k), Value: cbor.AppendString(nil, v)})
+		}
+		b.Run(fmt.Sprintf("%d/Unsorted", n), func(b *testing.B) {
+			var out []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				out = cbor.AppendMapStrStr(out[:0], m)
+			}
+		})
+		b.Run(fmt.Sprintf("%d/Presorted", n), func(b *testing.B) {
+			var out []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				out = cbor.AppendMapStrStrDeterministicKeys(out[:0], m, keys)
+			}
+		})
+		b.Run(fmt.Sprintf("%d/StrStrDeterministic", n), func(b *testing.B) {
+			var out []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				out = cbor.AppendMapStrStrDeterministic(out[:0], m)
+			}
+		})
+		b.Run(fmt.Sprintf("%d/MapDeterministic", n), func(b *testing.B) {
+			var out []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var err error
+				out, err = cbor.AppendMapDeterministic(out[:0], m, cbor.EncKeyString, cbor.EncValString)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%d/RawMapDeterministic", n), func(b *testing.B) {
+			var out []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				out = cbor.AppendRawMapDeterministic(out[:0], pairs)
+			}
+		})
+	}
+}
+
+func BenchmarkMapDeterministic_StrInt64(b *testing.B) {
+	const n = 100
+	m := make(map[string]int64, n)
+	for i := 0; i < n; i++ {
+		m[fmt.Sprintf("key-%d", i)] = int64(i) << 20
+	}
+	keys := sortedKeys(m)
+	b.Run(fmt.Sprintf("%d/Unsorted", n), func(b *testing.B) {
+		var out []byte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			out = cbor.AppendMapHeader(out[:0], uint32(len(m)))
+			for k, v := range m {
+				out = cbor.AppendString(out, k)
+				out = cbor.AppendInt64(out, v)
+			}
+		}
+	})
+	b.Run(fmt.Sprintf("%d/Presorted", n), func(b *testing.B) {
+		var out []byte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			out = cbor.AppendMapHeader(out[:0], uint32(len(keys)))
+			for _, k := range keys {
+				out = cbor.AppendString(out, k)
+				out = cbor.AppendInt64(out, m[k])
+			}
+		}
+	})
+	b.Run(fmt.Sprintf("%d/MapDeterministic", n), func(b *testing.B) {
+		var out []byte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			out = cbor.AppendMapDeterministicStrInt64(out[:0], m)
+		}
+	})
+}
@@ -155,6 +155,42 @@ func BenchmarkCBORRuntime_Containers_Encode(b *testing.B) {
 	_ = out
 }
 
+// BenchmarkCBORRuntime_Containers_EncodeValueSlice and
+// BenchmarkCBORRuntime_Containers_EncodePointerSlice compare a []T field
+// against a []*T field holding the same elements. The generated
+// x.Items[i].MarshalCBOR(b) takes the address of an element that already
+// lives in the slice's backing array, so neither form allocates.
+func BenchmarkCBORRuntime_Containers_EncodeValueSlice(b *testing.B) {
+	items := make([]structs.Scalars, 1000)
+	for i := range items {
+		items[i] = structs.Scalars{S: "a", I: i}
+	}
+	benchmarkContainersEncode(b, structs.Containers{Items: items})
+}
+
+func BenchmarkCBORRuntime_Containers_EncodePointerSlice(b *testing.B) {
+	ptrs := make([]*structs.Scalars, 1000)
+	for i := range ptrs {
+		ptrs[i] = &structs.Scalars{S: "a", I: i}
+	}
+	benchmarkContainersEncode(b, structs.Containers{Ptrs: ptrs})
+}
+
+func benchmarkContainersEncode(b *testing.B, c structs.Containers) {
+	out, err := c.MarshalCBOR(nil)
+	if err != nil {
+		b.Fatalf("MarshalCBOR: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out, err = c.MarshalCBOR(out[:0])
+		if err != nil {
+			b.Fatalf("MarshalCBOR: %v", err)
+		}
+	}
+}
+
 func BenchmarkCBORRuntime_Containers_DecodeSafe(b *testing.B) {
 	c := structs.Containers{Items: []structs.Scalars{{S: "a", I: 1}}}
 	enc, err := c.MarshalCBOR(nil)
@@ -1,7 +1,9 @@
 package benchmarks
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	cbor "github.com/synadia-labs/cbor.go/runtime"
 	msgp "github.com/tinylib/msgp/msgp"
@@ -75,3 +77,129 @@ func BenchmarkMsgp_AppendBytes(b *testing.B) {
 	}
 	_ = out
 }
+
+// uint32 through AppendInterface versus the typed AppendUint32. The
+// values are kept above 255 so that converting them to any allocates,
+// as it does for most real IDs and counters.
+
+func BenchmarkCBOR_AppendInterfaceUint32(b *testing.B) {
+	var out []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var err error
+		out, err = cbor.AppendInterface(out[:0], uint32(i)|0x10000)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	_ = out
+}
+
+func BenchmarkCBOR_AppendUint32(b *testing.B) {
+	var out []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = cbor.AppendUint32(out[:0], uint32(i)|0x10000)
+	}
+	_ = out
+}
+
+// time.Duration through AppendInterface versus the typed AppendDuration.
+// Durations are almost never below 256ns, so boxing them allocates.
+
+func BenchmarkAppendInterface_Duration(b *testing.B) {
+	var out []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var err error
+		out, err = cbor.AppendInterface(out[:0], time.Duration(i)*time.Millisecond)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	_ = out
+}
+
+func BenchmarkAppendDuration(b *testing.B) {
+	var out []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = cbor.AppendDuration(out[:0], time.Duration(i)*time.Millisecond)
+	}
+	_ = out
+}
+
+// ValidateWellFormedBytes over a 4096-element array of integers, mixing
+// one-byte and multi-byte encodings as telemetry samples do.
+
+func BenchmarkValidateWellFormed_IntArray(b *testing.B) {
+	const n = 4096
+	msg := cbor.AppendArrayHeader(nil, n)
+	for i := 0; i < n; i++ {
+		if i%4 == 0 {
+			msg = cbor.AppendInt64(msg, int64(i)*1000)
+		} else {
+			msg = cbor.AppendInt64(msg, int64(i%20)-10)
+		}
+	}
+	b.SetBytes(int64(len(msg)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cbor.ValidateWellFormedBytes(msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// ReadInterface over nested maps of short string keys, with and without
+// ZeroCopyKeys.
+
+func BenchmarkReadInterface_NestedMaps(b *testing.B) {
+	leaf := map[string]any{"id": 1, "name": "x", "ok": true, "seq": 42}
+	mid := map[string]any{}
+	for i := 0; i < 8; i++ {
+		mid[fmt.Sprintf("leaf-%d", i)] = leaf
+	}
+	top := map[string]any{}
+	for i := 0; i < 8; i++ {
+		top[fmt.Sprintf("mid-%d", i)] = mid
+	}
+	msg, err := cbor.AppendInterface(nil, top)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, zc := range []bool{false, true} {
+		opts := cbor.ReadInterfaceOptions{ZeroCopyKeys: zc}
+		b.Run(fmt.Sprintf("ZeroCopyKeys=%v", zc), func(b *testing.B) {
+			b.SetBytes(int64(len(msg)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := cbor.ReadInterfaceWithOptions(msg, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// ReadMapHeaderBytes at the boundaries of each header form: inline sizes
+// (0-23), then one, two and four bytes of length.
+
+func BenchmarkReadMapHeader(b *testing.B) {
+	for _, sz := range []uint32{0, 1, 23, 24, 255, 65535, 4294967295} {
+		msg := cbor.AppendMapHeader(nil, sz)
+		b.Run(fmt.Sprint(sz), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if n, _, err := cbor.ReadMapHeaderBytes(msg); err != nil || n != sz {
+					b.Fatalf("ReadMapHeaderBytes = %d, %v", n, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// cddlRule renders a CDDL (RFC 8610) rule describing the map encoding of
+// ss, e.g.
+//
+//	Person = {
+//	  "name": tstr,
+//	  ? "age": int
+//	}
+//
+// Structs encoded as arrays get an array rule with the same entries.
+// types holds the Go type of each field in ss.Fields, in order.
+func cddlRule(ss structSpec, types []ast.Expr) string {
+	var sb strings.Builder
+	opener, closer := "{", "}"
+	if ss.ToArray {
+		opener, closer = "[", "]"
+	}
+	sb.WriteString(ss.Name)
+	sb.WriteString(" = " + opener)
+	for i, fs := range ss.Fields {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n  ")
+		if fs.OmitEmpty {
+			sb.WriteString("? ")
+		}
+		if fs.IntKey != "" {
+			sb.WriteString(fs.IntKey)
+		} else {
+			sb.WriteString(strconv.Quote(fs.CBORName))
+		}
+		sb.WriteString(": ")
+		sb.WriteString(cddlFieldType(fs, types[i]))
+	}
+	if len(ss.Flatten) > 0 || ss.Fallthrough != "" {
+		// Flattened and fallthrough fields contribute entries unknown to
+		// the generator.
+		if len(ss.Fields) > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n  * any => any")
+	}
+	if len(ss.Fields) > 0 || len(ss.Flatten) > 0 || ss.Fallthrough != "" {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(closer)
+	return sb.String()
+}
+
+// cddlFieldType returns the CDDL type of a field, taking tag options
+// that change the wire representation into account.
+func cddlFieldType(fs fieldSpec, typ ast.Expr) string {
+	t := cddlValueType(fs, typ)
+	if fs.Null && t != "any" && !strings.HasSuffix(t, " / nil") {
+		t += " / nil"
+	}
+	return t
+}
+
+// cddlValueType is cddlFieldType without the null allowed by
+// cbor:",null".
+func cddlValueType(fs fieldSpec, typ ast.Expr) string {
+	switch {
+	case fs.RawCBOR, fs.Ref:
+		return "any"
+	case fs.IsUUID:
+		return "#6.37(bstr)"
+	case fs.AsString, fs.Hex:
+		return "tstr"
+	case fs.RFC3339:
+		return "tdate"
+	case fs.EpochMs:
+		return "int"
+	case fs.AsUint, fs.Unsigned:
+		return "uint"
+	case len(fs.OneOf) > 0:
+		return strings.Join(fs.OneOf, " / ") + " / nil"
+	case fs.Compact:
+		switch types.ExprString(typ) {
+		case "[]uint16":
+			return "#6.65(bstr)"
+		case "[]uint32":
+			return "#6.66(bstr)"
+		case "[]uint64":
+			return "#6.67(bstr)"
+		}
+	}
+	return cddlType(typ)
+}
+
+// cddlType maps a Go type expression onto a CDDL type. Named types are
+// referenced by their Go name, matching the rule emitted for generated
+// structs; types without a better description map to any.
+func cddlType(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "tstr"
+		case "bool":
+			return "bool"
+		case "int", "int8", "int16", "int32", "int64", "rune":
+			return "int"
+		case "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+			return "uint"
+		case "float32":
+			return "float32"
+		case "float64":
+			return "float64"
+		case "any":
+			return "any"
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return cddlType(t.X) + " / nil"
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" && t.Len == nil {
+			return "bstr"
+		}
+		return "[* " + cddlElemType(t.Elt) + "]"
+	case *ast.MapType:
+		return "{* " + cddlElemType(t.Key) + " => " + cddlElemType(t.Value) + "}"
+	case *ast.SelectorExpr:
+		pkg, _ := t.X.(*ast.Ident)
+		if pkg == nil {
+			return "any"
+		}
+		switch pkg.Name + "." + t.Sel.Name {
+		case "time.Time":
+			return "time"
+		case "time.Duration":
+			return "int"
+		case "net.IP", "net.HardwareAddr":
+			return "#6.260(bstr)"
+		case "url.URL":
+			return "uri"
+		case "json.Number":
+			return "number"
+		}
+		return "any"
+	}
+	return "any"
+}
+
+// cddlElemType is cddlType for element positions, where a choice such
+// as "T / nil" must be parenthesised.
+func cddlElemType(typ ast.Expr) string {
+	s := cddlType(typ)
+	if strings.Contains(s, " / ") {
+		return "(" + s + ")"
+	}
+	return s
+}
@@ -2,14 +2,19 @@ package core
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -21,11 +26,45 @@ import (
 // generatedStructs tracks struct types for which cborgen is generating
 // MarshalCBOR/Decode* methods in the current run. It is used to decide
 // when DecodeTrusted can be used for nested fields instead of falling
-// back to the generic UnmarshalCBOR path.
+// back to the generic UnmarshalCBOR path. Structs tagged
+// cbor:",writeonly" are left out, as they have no Decode* methods.
 var generatedStructs = map[string]struct{}{}
 
+// importedGenerated holds the types from other packages that
+// Options.KnownGenerated declares to have generated Decode* methods,
+// qualified as the current file refers to them (e.g. "meta.Stream").
+// It is rebuilt for each file by registerImportedGenerated.
+var importedGenerated = map[string]struct{}{}
+
+// runtimeImportName is the name the current file imports the cbor
+// runtime under, or "" when it does not import it. It is set for each
+// file by registerRuntimeImport.
+var runtimeImportName string
+
+// sizedStructs tracks the subset of generatedStructs that have a
+// generated Msgsize method, so fields of those types can contribute
+// to the enclosing struct's Msgsize.
+var sizedStructs = map[string]struct{}{}
+
+// refStructs tracks the collected structs whose generated methods take
+// a shared reference table: those with cbor:",ref" fields and those that
+// nest such a struct by value or pointer, so that one table spans the
+// whole document.
+var refStructs = map[string]struct{}{}
+
+// cborSizers tracks named types that declare a CBORSize() int method,
+// which fields of those types use as their Msgsize contribution.
+var cborSizers = map[string]struct{}{}
+
+// collectedStructs holds the declarations registered by collectStructs,
+// so markSizedStructs can decide which of them get a Msgsize method
+// before any file is generated.
+var collectedStructs = map[string]*ast.StructType{}
+
 const runtimeAlias = "cbor"
 
+const runtimeImportPath = "github.com/synadia-labs/cbor.go/runtime"
+
 var templateFuncs = template.FuncMap{
 	"rt": runtimeName,
 }
@@ -42,20 +81,433 @@ type Options struct {
 	// named struct types. Names must match Go type names
 	// exactly (no package qualification).
 	Structs []string
+	// NoLint adds a file-level //nolint:all directive so linters
+	// that don't honour the "Code generated" header skip the output.
+	NoLint bool
+	// CDDL emits a CBORSchema() method on each generated struct that
+	// returns a CDDL (RFC 8610) rule describing its encoding.
+	CDDL bool
+	// Context makes the generated DecodeSafe and DecodeTrusted methods
+	// take a context.Context, which is checked on entry and passed to
+	// nested decodes, and adds an UnmarshalCBORContext method.
+	Context bool
+	// KnownGenerated maps an import path to the names of types in that
+	// package that cborgen generated code for, with the same Context
+	// setting. Fields of those types decode with DecodeTrusted in the
+	// trusted decoder instead of UnmarshalCBOR.
+	KnownGenerated map[string][]string
+	// NoGoGenerate leaves out the //go:generate directive that each
+	// generated file otherwise carries to rerun cborgen with these
+	// options.
+	NoGoGenerate bool
+	// GoGenerateDir makes the directive regenerate the whole directory
+	// of the input file, as directory mode does, rather than only the
+	// input file.
+	GoGenerateDir bool
 }
 
 // Run generates CBOR code for a single Go source file.
 // It emits per-struct encode/decode implementations into outputPath.
 func Run(inputPath, outputPath string, opts Options) error {
+	src, err := Generate(inputPath, outputPath, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(outputPath, src, 0o666)
+}
+
+// Generate returns the code Run would write to outputPath for
+// inputPath, without touching the filesystem.
+func Generate(inputPath, outputPath string, opts Options) ([]byte, error) {
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, inputPath, nil, parser.ParseComments)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pkg := file.Name.Name
 
-	return generateStructCode(fset, file, outputPath, pkg, opts)
+	// Register every struct first so fields can refer to types declared
+	// later in the file, including self- and mutually-recursive ones.
+	collectStructs(file, opts)
+	collectSizers(file)
+	markSizedStructs()
+	markRefStructs()
+	if err := registerImportedGenerated(file, opts); err != nil {
+		return nil, err
+	}
+	registerRuntimeImport(file)
+	var directive string
+	if !opts.NoGoGenerate {
+		directive = goGenerateDirective(inputPath, outputPath, opts)
+	}
+	return generateStructCode(fset, file, outputPath, directive, pkg, opts)
+}
+
+// goGenerateDirective returns the command of a //go:generate directive
+// that regenerates outputPath from inputPath with opts, or the whole
+// directory with opts.GoGenerateDir. go generate runs it in the
+// directory of the generated file, so paths are given relative to that
+// directory.
+func goGenerateDirective(inputPath, outputPath string, opts Options) string {
+	dir := filepath.Dir(outputPath)
+	args := []string{"go run github.com/synadia-labs/cbor.go/cborgen"}
+	if opts.GoGenerateDir {
+		args = append(args, "-i", ".")
+	} else {
+		input, err := filepath.Rel(dir, inputPath)
+		if err != nil {
+			input = inputPath
+		}
+		args = append(args, "-i", filepath.ToSlash(input), "-o", filepath.Base(outputPath))
+	}
+	for _, name := range opts.Structs {
+		args = append(args, "-s", name)
+	}
+	if opts.NoLint {
+		args = append(args, "--nolint")
+	}
+	if opts.CDDL {
+		args = append(args, "--cddl")
+	}
+	if opts.Context {
+		args = append(args, "--context")
+	}
+	importPaths := make([]string, 0, len(opts.KnownGenerated))
+	for importPath := range opts.KnownGenerated {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+	for _, importPath := range importPaths {
+		args = append(args, "--known-generated-pkg="+importPath+"/"+strings.Join(opts.KnownGenerated[importPath], ","))
+	}
+	return strings.Join(args, " ")
+}
+
+// Collect records the struct types that Run will generate for each of
+// the given files without writing any output. Calling it for every file
+// of a package before running the generator lets fields refer to types
+// declared in sibling files (e.g. mutually recursive A and B) and still
+// use their DecodeTrusted fast paths regardless of file order. Files
+// that fail to parse are skipped; Run reports the error for them.
+//
+// The files must belong to one package: type names are not qualified,
+// so Collect first discards whatever an earlier call recorded.
+func Collect(inputPaths []string, opts Options) {
+	clear(generatedStructs)
+	clear(sizedStructs)
+	clear(cborSizers)
+	clear(collectedStructs)
+	clear(refStructs)
+	fset := token.NewFileSet()
+	for _, path := range inputPaths {
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		collectStructs(file, opts)
+		collectSizers(file)
+	}
+	markSizedStructs()
+	markRefStructs()
+}
+
+// registerImportedGenerated fills importedGenerated with the types
+// listed in opts.KnownGenerated, qualified by the name their package is
+// imported under in file. Packages that file does not import, or
+// imports as _ or ., contribute nothing. An import without an explicit
+// name must have a default name that importName can tell.
+func registerImportedGenerated(file *ast.File, opts Options) error {
+	clear(importedGenerated)
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		names, ok := opts.KnownGenerated[importPath]
+		if !ok {
+			continue
+		}
+		var local string
+		if imp.Name != nil {
+			local = imp.Name.Name
+		} else if local, ok = importName(importPath); !ok {
+			return fmt.Errorf("import %q needs an explicit name for --known-generated-pkg", importPath)
+		}
+		if local == "_" || local == "." {
+			continue
+		}
+		for _, name := range names {
+			importedGenerated[local+"."+name] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// importName returns the name a package is conventionally imported
+// under by default: the last element of importPath without a major
+// version, so both ".../foo/v2" and "gopkg.in/foo.v3" give "foo". It
+// reports false when that is not a valid identifier, e.g. for "go-foo".
+func importName(importPath string) (string, bool) {
+	name := path.Base(importPath)
+	if isMajorVersion(name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	} else if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	return name, token.IsIdentifier(name)
+}
+
+// isMajorVersion reports whether s is a major version path element such
+// as "v2".
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// registerRuntimeImport sets runtimeImportName from the imports of file.
+func registerRuntimeImport(file *ast.File) {
+	runtimeImportName = ""
+	for _, imp := range file.Imports {
+		if importPath, err := strconv.Unquote(imp.Path.Value); err != nil || importPath != runtimeImportPath {
+			continue
+		}
+		runtimeImportName = runtimeAlias
+		if imp.Name != nil {
+			runtimeImportName = imp.Name.Name
+		}
+		return
+	}
+}
+
+// isRuntimeSelector reports whether typ is the cbor runtime's exported
+// name, as in cbor.Marshaler, under whatever name the current file
+// imports the runtime.
+func isRuntimeSelector(typ ast.Expr, name string) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name || runtimeImportName == "" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == runtimeImportName
+}
+
+// collectStructs adds to generatedStructs each struct type in file that
+// generateStructCode emits methods for: allowed by opts.Structs, not
+// tagged cbor:",ignore" or cbor:",writeonly", and with at least one
+// exported, non-ignored field. Writeonly structs are still recorded in
+// collectedStructs.
+func collectStructs(file *ast.File, opts Options) {
+	allowed := allowedStructs(opts)
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if len(allowed) > 0 {
+				if _, ok := allowed[ts.Name.Name]; !ok {
+					continue
+				}
+			}
+			if structOptions(st).Has("ignore") {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if len(field.Names) == 0 || !ast.IsExported(field.Names[0].Name) || isSyncLock(field.Type) {
+					continue
+				}
+				if !resolveFieldSpec(field.Names[0].Name, field.Tag).Ignore {
+					if !structOptions(st).Has("writeonly") {
+						generatedStructs[ts.Name.Name] = struct{}{}
+					}
+					collectedStructs[ts.Name.Name] = st
+					break
+				}
+			}
+		}
+	}
+}
+
+// collectSizers adds to cborSizers each type in file with a method
+// declared as CBORSize() int, on either a value or pointer receiver.
+func collectSizers(file *ast.File) {
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 || fd.Name.Name != "CBORSize" {
+			continue
+		}
+		if fd.Type.Params.NumFields() != 0 || fd.Type.Results.NumFields() != 1 {
+			continue
+		}
+		if res, ok := fd.Type.Results.List[0].Type.(*ast.Ident); !ok || res.Name != "int" {
+			continue
+		}
+		recv := fd.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if ident, ok := recv.(*ast.Ident); ok {
+			cborSizers[ident.Name] = struct{}{}
+		}
+	}
+}
+
+// markSizedStructs adds to sizedStructs each collected struct that
+// generateStructCode will give a Msgsize method, i.e. one with at least
+// one field whose size it can express. Since that depends on which
+// nested structs are sized, it repeats until nothing changes.
+func markSizedStructs() {
+	for changed := true; changed; {
+		changed = false
+		for name, st := range collectedStructs {
+			// A struct holding a lock gets a pointer-receiver Msgsize,
+			// which PtrMsgsize and SliceMsgsize cannot call.
+			if _, ok := sizedStructs[name]; ok || structOptions(st).Has("readonly") || hasSyncLock(st) {
+				continue
+			}
+			if hasSizedField(st) {
+				sizedStructs[name] = struct{}{}
+				changed = true
+			}
+		}
+	}
+}
+
+// markRefStructs adds to refStructs each collected struct with a
+// cbor:",ref" field or a field whose type refThreadedType accepts. Like
+// markSizedStructs, it repeats until nothing changes.
+func markRefStructs() {
+	for changed := true; changed; {
+		changed = false
+		for name, st := range collectedStructs {
+			if _, ok := refStructs[name]; ok || !hasRefField(st) {
+				continue
+			}
+			refStructs[name] = struct{}{}
+			changed = true
+		}
+	}
+}
+
+// hasRefField reports whether st has a field that makes it a member of
+// refStructs.
+func hasRefField(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 || !ast.IsExported(field.Names[0].Name) {
+			continue
+		}
+		fs := resolveFieldSpec(field.Names[0].Name, field.Tag)
+		if fs.Ignore {
+			continue
+		}
+		if _, ok := refThreadedType(field.Type); ok || fs.Ref {
+			return true
+		}
+	}
+	return false
+}
+
+// refThreadedType returns T when typ is T or *T for a struct in
+// refStructs that is generated with both encoders and decoders, so a
+// field of that type is passed the enclosing table.
+func refThreadedType(typ ast.Expr) (string, bool) {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	if _, ok := refStructs[ident.Name]; !ok {
+		return "", false
+	}
+	if opts := structOptions(collectedStructs[ident.Name]); opts.Has("readonly") || opts.Has("writeonly") {
+		return "", false
+	}
+	return ident.Name, true
+}
+
+// applyRefsField encodes and decodes a field whose type refThreadedType
+// accepts through the type's *Refs methods, passing on the enclosing
+// reference table.
+func applyRefsField(fs *fieldSpec, typ ast.Expr, ctx bool) error {
+	name, _ := refThreadedType(typ)
+	_, ptr := typ.(*ast.StarExpr)
+	if ptr {
+		fs.EncodeExpr = runtimeName("AppendPtrRefs") + "(b, refs, x." + fs.GoName + ")"
+	} else {
+		fs.EncodeExpr = "x." + fs.GoName + ".MarshalCBORRefs(b, refs)"
+	}
+	fs.EncodeExprReturnsError = true
+	fs.EncodeBlock = ""
+	data := decodeCaseTemplateData{Field: fs.GoName, VarType: name, Pointer: ptr, Context: ctx}
+	var safe, trusted bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&safe, "decodeCaseRefsField", data); err != nil {
+		return err
+	}
+	data.Trusted = true
+	if err := decodeCaseTemplate.ExecuteTemplate(&trusted, "decodeCaseRefsField", data); err != nil {
+		return err
+	}
+	fs.DecodeCaseSafe = strings.TrimRight(safe.String(), "\n")
+	fs.DecodeCaseTrust = strings.TrimRight(trusted.String(), "\n")
+	return nil
+}
+
+// hasSizedField mirrors the Msgsize accumulation in generateStructCode.
+func hasSizedField(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 || !ast.IsExported(field.Names[0].Name) || isSyncLock(field.Type) {
+			continue
+		}
+		fs := resolveFieldSpec(field.Names[0].Name, field.Tag)
+		switch {
+		case fs.Ignore:
+			continue
+		case fs.Ref, fs.Flatten, fs.Fallthrough, fs.IsUUID && isUUIDArray(field.Type):
+			return true
+		}
+		if _, ok := fieldSizeExpr(fs.CBORName, fs.GoName, field.Type); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedStructs returns the opts.Structs allowlist as a set, or nil
+// when every struct is allowed.
+func allowedStructs(opts Options) map[string]struct{} {
+	if len(opts.Structs) == 0 {
+		return nil
+	}
+	allowed := make(map[string]struct{}, len(opts.Structs))
+	for _, name := range opts.Structs {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		allowed[name] = struct{}{}
+	}
+	return allowed
 }
 
 type fieldSpec struct {
@@ -71,15 +523,134 @@ type fieldSpec struct {
 	EncodeBlock            string
 	EncodeBlockUsesError   bool
 	Ignore                 bool
+	// AsUint forces signed integer fields onto the CBOR unsigned
+	// integer wire type (cbor:",uint").
+	AsUint bool
+	// Unsigned is AsUint with encoding failing on negative values
+	// (cbor:",unsigned").
+	Unsigned bool
+	// AsString encodes time.Duration fields as their String() form
+	// and integer fields as base-10 text (cbor:",string").
+	AsString bool
+	// RawCBOR embeds a []byte field holding a pre-encoded CBOR item
+	// verbatim (cbor:",rawcbor").
+	RawCBOR bool
+	// Flatten splices a []byte field holding pre-encoded map entries
+	// into the enclosing map (cbor:",flatten").
+	Flatten bool
+	// Fallthrough collects map entries with unknown keys into a
+	// cbor.Raw field when decoding and writes them back when encoding
+	// (cbor:",fallthrough").
+	Fallthrough bool
+	// Hex encodes a []byte field as a text string of lowercase hex
+	// digits (cbor:",hex").
+	Hex bool
+	// Compact encodes a []uint16, []uint32 or []uint64 field as an
+	// RFC 8746 typed array (cbor:",compact").
+	Compact bool
+	// IsUUID encodes a [16]byte field as a tag 37 UUID (cbor:",uuid").
+	IsUUID bool
+	// Epoch, RFC3339 and EpochMs pick the encoding of a time.Time
+	// field: tag 1 epoch seconds (the default, cbor:",epoch"), a tag 0
+	// RFC 3339 string (cbor:",rfc3339") or an untagged integer count of
+	// milliseconds (cbor:",epochms").
+	Epoch   bool
+	RFC3339 bool
+	EpochMs bool
+	// Ref encodes a *T or []*T field with the value-sharing tags 28 and
+	// 29, so repeated pointers are written once (cbor:",ref").
+	Ref bool
+	// OneOf lists the struct types an interface field may hold
+	// (cbor:",oneof=A,B"). They are encoded with a type switch and
+	// told apart on decode by map keys only one of them has.
+	OneOf []string
+	// Validate lists the constraint options checked by the generated
+	// Validate method, e.g. "min=0" or "required".
+	Validate []string
+	// Positional is set for fields of a ToArray struct, whose encode
+	// blocks write the value without a map key.
+	Positional bool
+	// Null always writes the field, as null when it holds the zero
+	// value (cbor:",null", alias cbor:",skipnull").
+	Null bool
+	// Version is the schema version that introduced the field
+	// (cbor:",version=N"), or "" when it has always been present.
+	Version string
+	// Deprecated is the message of cbor:",deprecated=msg", emitted as a
+	// "// Deprecated:" comment on the field's generated cases.
+	Deprecated string
+	// Key is the explicit integer key given by cbor:",key=N", or "".
+	Key string
+	// IntKey is the integer map key of a field in a cbor:",keytype=int"
+	// struct: Key when set, otherwise the field's position. It is ""
+	// for fields keyed by name.
+	IntKey string
+	// KeyExpr appends the field's map key, e.g. cbor.AppendString(b, "name").
+	KeyExpr string
 }
 
 type structSpec struct {
 	Name           string
 	Fields         []fieldSpec
-	MsgSizeExpr    string
+	MsgSizeParts   []string
 	HasOmit        bool
 	EncodeNeedsErr bool
 	NonOmitCount   int
+	// Flow encodes every field, ignoring omitempty. It is set by a
+	// blank field tagged cbor:",flow" (e.g. _ struct{} `cbor:",flow"`).
+	Flow bool
+	// CDDL is the rule returned by the generated CBORSchema method,
+	// or empty when --cddl is not set.
+	CDDL string
+	// Flatten lists the Go names of cbor:",flatten" fields, whose
+	// contents are appended after the keyed fields.
+	Flatten []string
+	// Fallthrough is the Go name of the cbor:",fallthrough" field, or
+	// empty when the struct drops unknown keys.
+	Fallthrough string
+	// HasRefs is set when a field uses cbor:",ref" or nests a struct
+	// that has one. The generated methods then take a reference table
+	// (MarshalCBORRefs, DecodeSafeRefs, ...) and pass it to those
+	// fields, so one table spans the whole document.
+	HasRefs bool
+	// ToArray encodes the struct as an array of its field values in
+	// declaration order. It is set by a blank field tagged
+	// cbor:",toarray" (or its alias cbor:",mapstruct").
+	ToArray bool
+	// NoCase matches map keys to field names case-insensitively when
+	// decoding. It is set by a blank field tagged cbor:",nocase".
+	NoCase bool
+	// Validations holds the checks of the generated Validate method,
+	// which is emitted when any field declares a constraint.
+	Validations []validationCheck
+	// IntKeys keys the encoded map by integers instead of field names.
+	// It is set by a blank field tagged cbor:",keytype=int".
+	IntKeys bool
+	// Versioned is set when a field declares cbor:",version=N"; the
+	// struct then gets an EncodeVersion method that MarshalCBOR calls
+	// with the latest version.
+	Versioned bool
+	// MethodChecks lists the fields encoded and decoded through their
+	// type's own MarshalCBOR/UnmarshalCBOR methods. The generated file
+	// asserts those methods exist so a missing one fails the build.
+	MethodChecks []methodCheck
+	// ReadOnly omits MarshalCBOR and Msgsize for structs that are only
+	// ever decoded. It is set by a blank field tagged cbor:",readonly".
+	ReadOnly bool
+	// WriteOnly omits DecodeSafe, DecodeTrusted and UnmarshalCBOR for
+	// structs that are only ever encoded. It is set by a blank field
+	// tagged cbor:",writeonly".
+	WriteOnly bool
+	// HasLock is set when the struct holds a sync.Mutex or sync.RWMutex,
+	// so Msgsize and CBORSchema take a pointer receiver.
+	HasLock bool
+}
+
+// methodCheck is a compile-time assertion that Type (without any
+// pointer) implements cbor.Marshaler and cbor.Unmarshaler for Field.
+type methodCheck struct {
+	Field string
+	Type  string
 }
 
 // generateStructCode finds struct types in the given file and generates
@@ -89,21 +660,14 @@ type structSpec struct {
 //   - if cbor tag present: it wins
 //   - if cbor tag absent, json tag is used
 //   - if both absent, Go field name is used
-func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg string, opts Options) error {
+func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, goGenerate, pkg string, opts Options) ([]byte, error) {
 	var structs []structSpec
 	useOmit := false
 
-	var allowed map[string]struct{}
-	if len(opts.Structs) > 0 {
-		allowed = make(map[string]struct{}, len(opts.Structs))
-		for _, name := range opts.Structs {
-			name = strings.TrimSpace(name)
-			if name == "" {
-				continue
-			}
-			allowed[name] = struct{}{}
-		}
-	}
+	allowed := allowedStructs(opts)
+
+	ifaces := interfaceTypes(file)
+	fileStructs := structTypes(file)
 
 	for _, decl := range file.Decls {
 		gd, ok := decl.(*ast.GenDecl)
@@ -126,9 +690,45 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 					continue
 				}
 			}
-			ss := structSpec{Name: ts.Name.Name}
+			stOpts := structOptions(st)
+			// A blank field tagged cbor:",ignore" opts the whole
+			// struct out of generation.
+			if stOpts.Has("ignore") {
+				continue
+			}
+			ss := structSpec{
+				Name:      ts.Name.Name,
+				Flow:      stOpts.Has("flow"),
+				ToArray:   stOpts.Has("toarray") || stOpts.Has("mapstruct"),
+				NoCase:    stOpts.Has("nocase"),
+				ReadOnly:  stOpts.Has("readonly"),
+				WriteOnly: stOpts.Has("writeonly"),
+			}
+			if ss.ReadOnly && ss.WriteOnly {
+				return nil, fmt.Errorf("%s: readonly cannot be combined with writeonly", ss.Name)
+			}
+			if kt, ok := stOpts.Value("keytype"); ok {
+				if kt != "int" {
+					return nil, fmt.Errorf("%s: unsupported keytype %q", ss.Name, kt)
+				}
+				if ss.ToArray || ss.NoCase {
+					return nil, fmt.Errorf("%s: keytype=int cannot be combined with toarray or nocase", ss.Name)
+				}
+				ss.IntKeys = true
+			}
+			// pos numbers the encoded fields for keytype=int; intKeys
+			// maps each assigned key to its field to catch duplicates.
+			pos := 0
+			intKeys := make(map[string]string)
 			var sizeExprParts []string
+			var fieldTypes []ast.Expr
 			for _, field := range st.Fields.List {
+				// Skip locks, which carry no data even when exported.
+				// Msgsize then takes a pointer so the lock is not copied.
+				if isSyncLock(field.Type) {
+					ss.HasLock = true
+					continue
+				}
 				// Skip anonymous fields for now.
 				if len(field.Names) == 0 {
 					continue
@@ -142,8 +742,52 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 				if fs.Ignore {
 					continue
 				}
+				if fs.Flatten && ss.ToArray {
+					return nil, fmt.Errorf("%s.%s: option \"flatten\" cannot be used in a toarray struct", ss.Name, fs.GoName)
+				}
+				if fs.Flatten && ss.IntKeys {
+					return nil, fmt.Errorf("%s.%s: option \"flatten\" cannot be used in a keytype=int struct", ss.Name, fs.GoName)
+				}
+				if fs.Flatten {
+					if !isByteSlice(field.Type) {
+						return nil, fmt.Errorf("%s.%s: option \"flatten\" requires a []byte field, got %s", ss.Name, fs.GoName, types.ExprString(field.Type))
+					}
+					ss.Flatten = append(ss.Flatten, fs.GoName)
+					sizeExprParts = append(sizeExprParts, "len(x."+fs.GoName+")")
+					continue
+				}
+				if fs.Fallthrough {
+					if ss.ToArray {
+						return nil, fmt.Errorf("%s.%s: option \"fallthrough\" cannot be used in a toarray struct", ss.Name, fs.GoName)
+					}
+					if ss.Fallthrough != "" {
+						return nil, fmt.Errorf("%s.%s: option \"fallthrough\" is already set on %s", ss.Name, fs.GoName, ss.Fallthrough)
+					}
+					if !isRuntimeSelector(field.Type, "Raw") {
+						return nil, fmt.Errorf("%s.%s: option \"fallthrough\" requires a cbor.Raw field, got %s", ss.Name, fs.GoName, types.ExprString(field.Type))
+					}
+					ss.Fallthrough = fs.GoName
+					sizeExprParts = append(sizeExprParts, "len(x."+fs.GoName+")")
+					continue
+				}
+				if fs.Null && fs.OmitEmpty {
+					return nil, fmt.Errorf("%s.%s: options \"null\" and \"omitempty\" cannot be combined", ss.Name, fs.GoName)
+				}
+				if ss.Flow || ss.ToArray {
+					fs.OmitEmpty = false
+				}
+				fs.Positional = ss.ToArray
+				if err := applyIntKey(&fs, ss.IntKeys, pos, intKeys); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+				}
+				pos++
+				iface := interfaceFieldType(field.Type, ifaces)
 				if fs.OmitEmpty {
-					if cond, ok := omitEmptyCondExpr(name, field.Type); ok {
+					omitType := field.Type
+					if iface != nil {
+						omitType = iface
+					}
+					if cond, ok := omitEmptyCondExpr(name, omitType); ok {
 						fs.OmitEmptyCond = cond
 						useOmit = true
 						ss.HasOmit = true
@@ -151,31 +795,43 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 						fs.OmitEmpty = false
 					}
 				}
+				if fs.Version != "" {
+					if err := applyVersionOption(&fs, ss.ToArray); err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+					}
+					ss.Versioned = true
+					ss.HasOmit = true
+					useOmit = true
+				}
 				if !fs.OmitEmpty {
 					ss.NonOmitCount++
 				}
 				// Accumulate contribution to Msgsize expression where supported.
-				if szExpr, ok := fieldSizeExpr(fs.CBORName, fs.GoName, field.Type); ok {
+				if fs.Ref {
+					// Referenced values may form cycles, so only the key counts.
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q)", runtimeName("StringPrefixSize"), fs.CBORName))
+				} else if fs.IsUUID && isUUIDArray(field.Type) {
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s", runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("UUIDSize")))
+				} else if ident, ok := field.Type.(*ast.Ident); ok && fs.AsString && intStringBits(ident.Name, &decodeCaseTemplateData{}) {
+					// Up to 20 characters, e.g. "-9223372036854775808".
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s + 20", runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("StringPrefixSize")))
+				} else if fs.RFC3339 && isTimeTime(field.Type) {
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s", runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("RFC3339TimeSize")))
+				} else if fs.EpochMs && isTimeTime(field.Type) {
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s", runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("Int64Size")))
+				} else if fs.Hex && isByteSlice(field.Type) {
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s + 2*len(x.%s)", runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("StringPrefixSize"), fs.GoName))
+				} else if w := typedArrayWidth(field.Type); fs.Compact && w > 0 {
+					sizeExprParts = append(sizeExprParts, fmt.Sprintf("%s + len(%q) + %s + %d*len(x.%s)", runtimeName("StringPrefixSize"), fs.CBORName, runtimeName("TypedArrayPrefixSize"), w, fs.GoName))
+				} else if szExpr, ok := fieldSizeExpr(fs.CBORName, fs.GoName, field.Type); ok {
 					sizeExprParts = append(sizeExprParts, szExpr)
 				}
 				if ec, ok := encodeCaseExpr(fs.GoName, field.Type); ok {
 					fs.EncodeCase = ec
 				}
 				fs.EncodeExpr, fs.EncodeExprReturnsError = encodeExprForField(fs.GoName, field.Type)
-				fs.EncodeBlock, fs.EncodeBlockUsesError = encodeBlockForField(ss.Name, fs.GoName, fs.CBORName, field.Type)
-				switch {
-				case fs.EncodeBlock != "":
-					if fs.EncodeBlockUsesError {
-						ss.EncodeNeedsErr = true
-					}
-				case fs.EncodeExpr != "":
-					if fs.EncodeExprReturnsError {
-						ss.EncodeNeedsErr = true
-					}
-				default:
-					ss.EncodeNeedsErr = true
-				}
-				if dc, ok := decodeCaseExprSafe(ss.Name, fs.GoName, field.Type); ok {
+				fs.EncodeBlock, fs.EncodeBlockUsesError = encodeBlockForField(ss.Name, fs.GoName, blockKeyName(fs), field.Type)
+				if dc, ok := decodeCaseExprSafe(ss.Name, fs.GoName, field.Type, opts.Context); ok {
 					fs.DecodeCaseSafe = dc
 				} else {
 					// Fallback: skip the value for unsupported types using template.
@@ -185,7 +841,7 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 					}
 				}
 
-				if dc, ok := decodeCaseExprTrusted(ss.Name, fs.GoName, field.Type); ok {
+				if dc, ok := decodeCaseExprTrusted(ss.Name, fs.GoName, field.Type, opts.Context); ok {
 					fs.DecodeCaseTrust = dc
 				} else {
 					var skipBuf bytes.Buffer
@@ -193,113 +849,1077 @@ func generateStructCode(fset *token.FileSet, file *ast.File, outputPath, pkg str
 						fs.DecodeCaseTrust = strings.TrimRight(skipBuf.String(), "\n")
 					}
 				}
+				if _, ok := refThreadedType(field.Type); ok && !fs.Ref {
+					if err := applyRefsField(&fs, field.Type, opts.Context); err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+					}
+				}
+				if err := applyFieldOptions(&fs, field.Type); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+				}
+				if len(fs.OneOf) > 0 {
+					if err := applyOneOfOption(&fs, field.Type, ifaces, fileStructs, opts.Context); err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+					}
+				} else if iface != nil {
+					if err := applyInterfaceField(&fs, iface, opts.Context); err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+					}
+				} else if isMarshalerSlice(field.Type, ifaces) {
+					if err := applyInterfaceSliceField(&fs); err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+					}
+				}
+				if fs.Null {
+					condType := field.Type
+					if iface != nil {
+						condType = iface
+					}
+					if err := applyNullOption(&fs, field.Type, condType); err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+					}
+				}
+				checks, err := validationChecks(fs, field.Type)
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", ss.Name, fs.GoName, err)
+				}
+				ss.Validations = append(ss.Validations, checks...)
+				_, threaded := refThreadedType(field.Type)
+				if fs.Ref || threaded {
+					ss.HasRefs = true
+				} else if name, ok := methodType(field.Type); ok && iface == nil && fs.EncodeBlock == "" {
+					ss.MethodChecks = append(ss.MethodChecks, methodCheck{Field: fs.GoName, Type: name})
+				}
+				switch {
+				case fs.EncodeBlock != "":
+					if fs.EncodeBlockUsesError {
+						ss.EncodeNeedsErr = true
+					}
+				case fs.EncodeExpr != "":
+					if fs.EncodeExprReturnsError {
+						ss.EncodeNeedsErr = true
+					}
+				default:
+					ss.EncodeNeedsErr = true
+				}
 				ss.Fields = append(ss.Fields, fs)
+				if iface != nil {
+					fieldTypes = append(fieldTypes, iface)
+				} else {
+					fieldTypes = append(fieldTypes, field.Type)
+				}
+			}
+			if len(ss.Fields) > 0 || len(ss.Flatten) > 0 || ss.Fallthrough != "" {
+				if opts.CDDL {
+					ss.CDDL = cddlRule(ss, fieldTypes)
+				}
+				if !ss.WriteOnly {
+					generatedStructs[ss.Name] = struct{}{}
+				}
+				if len(sizeExprParts) > 0 && !ss.ReadOnly {
+					// Per-field key/value contributions, added to the map
+					// header size with cbor.AddSize so the total cannot
+					// overflow.
+					ss.MsgSizeParts = sizeExprParts
+					if !ss.HasLock {
+						sizedStructs[ss.Name] = struct{}{}
+					}
+				}
+				structs = append(structs, ss)
+			}
+		}
+	}
+
+	data := struct {
+		Package    string
+		UseOmit    bool
+		NoLint     bool
+		Context    bool
+		GoGenerate string
+		Structs    []structSpec
+	}{
+		Package:    pkg,
+		UseOmit:    useOmit,
+		NoLint:     opts.NoLint,
+		Context:    opts.Context,
+		GoGenerate: goGenerate,
+		Structs:    structs,
+	}
+
+	var buf bytes.Buffer
+	if err := marshalTemplate.ExecuteTemplate(&buf, "marshal.go.tpl", data); err != nil {
+		return nil, err
+	}
+
+	src, err := imports.Process(outputPath, buf.Bytes(), nil)
+	if err != nil {
+		// Fall back to go/format if goimports fails.
+		if formatted, ferr := format.Source(buf.Bytes()); ferr == nil {
+			src = formatted
+		} else {
+			src = buf.Bytes()
+		}
+	}
+	return src, nil
+}
+
+// resolveFieldSpec applies tag resolution rules:
+// - cbor tag primary
+// - if no cbor tag, use json tag
+// - if both absent, use Go field name
+func resolveFieldSpec(goName string, tag *ast.BasicLit) fieldSpec {
+	fs := fieldSpec{GoName: goName, CBORName: goName}
+	if tag == nil {
+		return fs
+	}
+	raw := tag.Value
+	if len(raw) >= 2 && (raw[0] == '`' && raw[len(raw)-1] == '`') {
+		raw = raw[1 : len(raw)-1]
+	}
+	st := reflect.StructTag(raw)
+	v, ok := parseTag(st.Get("cbor"))
+	fromCBOR := ok
+	if !ok {
+		v, ok = parseTag(st.Get("json"))
+	}
+	if !ok {
+		return fs
+	}
+	if v == "-" {
+		fs.Ignore = true
+		return fs
+	}
+	name, opts := splitNameOptions(v)
+	if name != "" {
+		fs.CBORName = name
+	}
+	fs.OmitEmpty = opts.Has("omitempty")
+	fs.AsUint = opts.Has("uint")
+	fs.Unsigned = opts.Has("unsigned")
+	// encoding/json gives ",string" a different meaning, so only honour
+	// it on cbor tags.
+	fs.AsString = fromCBOR && opts.Has("string")
+	fs.RawCBOR = opts.Has("rawcbor")
+	fs.Flatten = opts.Has("flatten")
+	fs.Fallthrough = opts.Has("fallthrough")
+	fs.IsUUID = opts.Has("uuid")
+	fs.Epoch = opts.Has("epoch")
+	fs.RFC3339 = opts.Has("rfc3339")
+	fs.EpochMs = opts.Has("epochms")
+	fs.Hex = opts.Has("hex")
+	fs.Compact = opts.Has("compact")
+	fs.Ref = opts.Has("ref")
+	fs.Null = opts.Has("null") || opts.Has("skipnull")
+	fs.Version, _ = opts.Value("version")
+	fs.Key, _ = opts.Value("key")
+	if list, ok := opts.List("oneof"); ok {
+		fs.OneOf = list
+	}
+	if msg, ok := opts.Value("deprecated"); ok {
+		fs.Deprecated = msg
+	} else if opts.Has("deprecated") {
+		fs.Deprecated = "kept for backward compatibility only"
+	}
+	fs.Validate = validationRules(opts)
+	return fs
+}
+
+// blockKeyName returns the map key written by fs's encode block, or ""
+// for positional fields, whose blocks write only the value, for null
+// fields, whose key is written by the encodeNullable wrapper, and for
+// integer-keyed fields, whose key the marshal template writes.
+func blockKeyName(fs fieldSpec) string {
+	if fs.Positional || fs.Null || fs.IntKey != "" {
+		return ""
+	}
+	return fs.CBORName
+}
+
+// structOptions returns the struct-level options declared on a blank
+// field's cbor tag, e.g. _ struct{} `cbor:",flow"`.
+func structOptions(st *ast.StructType) tagOptions {
+	var opts tagOptions
+	for _, field := range st.Fields.List {
+		if len(field.Names) != 1 || field.Names[0].Name != "_" || field.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		_, o := splitNameOptions(reflect.StructTag(raw).Get("cbor"))
+		opts = append(opts, o...)
+	}
+	return opts
+}
+
+// parseTag returns the raw tag string and whether it was present.
+func parseTag(v string) (string, bool) {
+	if v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+// tagOptions holds the comma-separated options that follow the name
+// in a cbor/json struct tag (e.g. "omitempty", "uint").
+type tagOptions []string
+
+// Has reports whether the option list contains opt.
+func (o tagOptions) Has(opt string) bool {
+	for _, p := range o {
+		if p == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the values of a name=a,b,c option and whether it is
+// present. The list runs to the end of the tag, so the option must come
+// last: "oneof=A,B" yields ["A", "B"].
+func (o tagOptions) List(name string) ([]string, bool) {
+	for i, p := range o {
+		if k, v, ok := strings.Cut(p, "="); ok && k == name {
+			out := []string{v}
+			return append(out, o[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
+// Value returns the value of a name=value option, e.g. "3" for
+// "version=3", and whether the option is present.
+func (o tagOptions) Value(name string) (string, bool) {
+	for _, p := range o {
+		if k, v, ok := strings.Cut(p, "="); ok && k == name {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// splitNameOptions splits a tag like "name,omitempty" into name and options.
+// An empty name (e.g. ",uint") means the Go field name is kept.
+func splitNameOptions(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], tagOptions(parts[1:])
+}
+
+// applyFieldOptions rewrites the encode/decode snippets of fs for tag
+// options that change a field's wire representation. It returns an
+// error when an option cannot be applied to the field's Go type.
+func applyFieldOptions(fs *fieldSpec, typ ast.Expr) error {
+	if fs.AsUint || fs.Unsigned {
+		if err := applyUintOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.AsString {
+		if err := applyStringOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.RawCBOR {
+		if err := applyRawCBOROption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.IsUUID {
+		if err := applyUUIDOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.Epoch || fs.RFC3339 || fs.EpochMs {
+		if err := applyTimeFormatOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.Hex {
+		if err := applyHexOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.Compact {
+		if err := applyCompactOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	if fs.Ref {
+		if err := applyRefOption(fs, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyIntKey sets the map key of fs. In a keytype=int struct that is
+// the integer from cbor:",key=N", or the field's position pos when no
+// key is given; other structs key fields by name and reject key=N. used
+// records the keys assigned so far.
+func applyIntKey(fs *fieldSpec, intKeys bool, pos int, used map[string]string) error {
+	if !intKeys {
+		if fs.Key != "" {
+			return errors.New("option \"key\" requires a keytype=int struct")
+		}
+		fs.KeyExpr = runtimeName("AppendString") + "(b, \"" + fs.CBORName + "\")"
+		return nil
+	}
+	fs.IntKey = strconv.Itoa(pos)
+	if fs.Key != "" {
+		// Keys stay within uint32 so their encoding is no longer than
+		// the string key Msgsize budgets for.
+		n, err := strconv.ParseUint(fs.Key, 10, 32)
+		if err != nil {
+			return fmt.Errorf("option \"key\" requires an integer from 0 to 4294967295, got %q", fs.Key)
+		}
+		fs.IntKey = strconv.FormatUint(n, 10)
+	}
+	if other, ok := used[fs.IntKey]; ok {
+		return fmt.Errorf("key %s is already used by %s", fs.IntKey, other)
+	}
+	used[fs.IntKey] = fs.GoName
+	fs.KeyExpr = runtimeName("AppendUint64") + "(b, " + fs.IntKey + ")"
+	return nil
+}
+
+// applyVersionOption makes a field added in schema version N
+// (cbor:",version=N") conditional on the version passed to the
+// generated EncodeVersion method, on top of any omitempty check.
+func applyVersionOption(fs *fieldSpec, toArray bool) error {
+	if toArray {
+		return errors.New("option \"version\" cannot be used in a toarray struct")
+	}
+	n, err := strconv.Atoi(fs.Version)
+	if err != nil || n < 1 {
+		return fmt.Errorf("option \"version\" requires a positive integer, got %q", fs.Version)
+	}
+	cond := "v >= " + fs.Version
+	if fs.OmitEmpty {
+		cond += " && " + fs.OmitEmptyCond
+	}
+	fs.OmitEmpty, fs.OmitEmptyCond = true, cond
+	return nil
+}
+
+// applyNullOption wraps the encoder of fs so a zero value is written as
+// null under the field's key instead of being encoded as is, and the
+// decoders so null reads back as the zero value. condType is the type
+// used for the zero check, which differs from typ for interface fields.
+func applyNullOption(fs *fieldSpec, typ, condType ast.Expr) error {
+	cond, ok := omitEmptyCondExpr(fs.GoName, condType)
+	if !ok {
+		return fmt.Errorf("option \"null\" is not supported for %s", types.ExprString(typ))
+	}
+	body, usesErr := fs.EncodeBlock, fs.EncodeBlockUsesError
+	if body == "" {
+		expr, returnsErr := fs.EncodeExpr, fs.EncodeExprReturnsError
+		if expr == "" {
+			expr, returnsErr = runtimeName("AppendInterface")+"(b, x."+fs.GoName+")", true
+		}
+		if returnsErr {
+			body = "b, err = " + expr + "\n\tif err != nil { return b, err }"
+		} else {
+			body = "b = " + expr
+		}
+		usesErr = returnsErr
+	}
+	key := fs.CBORName
+	if fs.Positional || fs.IntKey != "" {
+		key = ""
+	}
+	var enc bytes.Buffer
+	if err := encodeBlockTemplate.ExecuteTemplate(&enc, "encodeNullable", encodeBlockTemplateData{KeyName: key, Cond: cond, Body: strings.TrimSpace(body)}); err != nil {
+		return err
+	}
+	var safe, trusted bytes.Buffer
+	data := decodeCaseTemplateData{Field: fs.GoName, VarType: types.ExprString(typ), Body: strings.TrimSpace(fs.DecodeCaseSafe)}
+	if err := decodeCaseTemplate.ExecuteTemplate(&safe, "decodeCaseNullable", data); err != nil {
+		return err
+	}
+	data.Body = strings.TrimSpace(fs.DecodeCaseTrust)
+	if err := decodeCaseTemplate.ExecuteTemplate(&trusted, "decodeCaseNullable", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr, fs.EncodeExprReturnsError = "", false
+	fs.EncodeBlock, fs.EncodeBlockUsesError = strings.TrimRight(enc.String(), "\n"), usesErr
+	fs.DecodeCaseSafe = strings.TrimRight(safe.String(), "\n")
+	fs.DecodeCaseTrust = strings.TrimRight(trusted.String(), "\n")
+	return nil
+}
+
+// applyRefOption encodes a *T or []*T field through the shared
+// reference table declared by the generated methods (cbor:",ref"), so a
+// pointer repeated across the struct's ref fields is written once as
+// tag(28) and afterwards as tag(29) holding its reference ID.
+func applyRefOption(fs *fieldSpec, typ ast.Expr) error {
+	data := decodeCaseTemplateData{Field: fs.GoName}
+	name := "decodeCaseRef"
+	appendFunc := "AppendRef"
+	if arr, ok := typ.(*ast.ArrayType); ok && arr.Len == nil {
+		name = "decodeCaseRefSlice"
+		appendFunc = "AppendRefSlice"
+		typ = arr.Elt
+	}
+	star, ok := typ.(*ast.StarExpr)
+	if !ok {
+		return fmt.Errorf("option \"ref\" requires a *T or []*T field")
+	}
+	ident, ok := star.X.(*ast.Ident)
+	if !ok {
+		return fmt.Errorf("option \"ref\" requires a named element type, got %s", types.ExprString(star))
+	}
+	data.VarType = ident.Name
+
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = runtimeName(appendFunc) + "(b, refs, x." + fs.GoName + ")"
+	fs.EncodeExprReturnsError = true
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// applyUUIDOption encodes a [16]byte field as a tag 37 UUID
+// (cbor:",uuid") using AppendUUID and ReadUUIDBytes.
+func applyUUIDOption(fs *fieldSpec, typ ast.Expr) error {
+	if !isUUIDArray(typ) {
+		return fmt.Errorf("option \"uuid\" requires a [16]byte field, got %s", types.ExprString(typ))
+	}
+
+	data := decodeCaseTemplateData{Field: fs.GoName, VarType: "[16]byte", ReadFunc: runtimeName("ReadUUIDBytes")}
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseBasic", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = runtimeName("AppendUUID") + "(b, x." + fs.GoName + ")"
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// applyTimeFormatOption sets the wire form of a time.Time field:
+// cbor:",epoch" keeps the default tag 1 epoch time, cbor:",rfc3339"
+// writes a tag 0 RFC 3339 string and cbor:",epochms" an untagged
+// integer count of milliseconds since the epoch. Each decoder accepts
+// only its own form.
+func applyTimeFormatOption(fs *fieldSpec, typ ast.Expr) error {
+	n := 0
+	for _, set := range []bool{fs.Epoch, fs.RFC3339, fs.EpochMs} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return errors.New("options \"epoch\", \"rfc3339\" and \"epochms\" are mutually exclusive")
+	}
+	if !isTimeTime(typ) {
+		return fmt.Errorf("time format options require a time.Time field, got %s", types.ExprString(typ))
+	}
+
+	appendFunc, readFunc := "AppendTime", "ReadTimeBytes"
+	switch {
+	case fs.RFC3339:
+		appendFunc, readFunc = "AppendRFC3339Time", "ReadRFC3339TimeBytes"
+	case fs.EpochMs:
+		appendFunc, readFunc = "AppendUnixMilli", "ReadUnixMilliBytes"
+	}
+	data := decodeCaseTemplateData{Field: fs.GoName, VarType: "time.Time", ReadFunc: runtimeName(readFunc)}
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseBasic", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = runtimeName(appendFunc) + "(b, x." + fs.GoName + ")"
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// applyHexOption encodes a []byte field as a text string of lowercase
+// hex digits and decodes it with cbor.ReadHexStringBytes (cbor:",hex").
+func applyHexOption(fs *fieldSpec, typ ast.Expr) error {
+	if !isByteSlice(typ) {
+		return fmt.Errorf("option \"hex\" requires a []byte field, got %s", types.ExprString(typ))
+	}
+
+	data := decodeCaseTemplateData{Field: fs.GoName, VarType: "[]byte", ReadFunc: runtimeName("ReadHexStringBytes")}
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseBasic", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = runtimeName("AppendHexString") + "(b, x." + fs.GoName + ")"
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// applyCompactOption encodes a []uint16, []uint32 or []uint64 field as
+// an RFC 8746 typed array of big-endian values (cbor:",compact"). The
+// decoders also accept the little-endian typed array and a plain array.
+func applyCompactOption(fs *fieldSpec, typ ast.Expr) error {
+	w := typedArrayWidth(typ)
+	if w == 0 {
+		return fmt.Errorf("option \"compact\" requires a []uint16, []uint32 or []uint64 field, got %s", types.ExprString(typ))
+	}
+	elem := "uint" + strconv.Itoa(8*w)
+	suffix := "Uint" + strconv.Itoa(8*w)
+
+	data := decodeCaseTemplateData{Field: fs.GoName, VarType: "[]" + elem, ReadFunc: runtimeName("ReadTypedArray" + suffix + "Bytes")}
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseBasic", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = runtimeName("AppendTypedArray"+suffix) + "(b, x." + fs.GoName + ")"
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
+
+// typedArrayWidth returns the element width in bytes of a []uint16,
+// []uint32 or []uint64 type, or 0 for any other type.
+func typedArrayWidth(typ ast.Expr) int {
+	arr, ok := typ.(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return 0
+	}
+	ident, ok := arr.Elt.(*ast.Ident)
+	if !ok {
+		return 0
+	}
+	switch ident.Name {
+	case "uint16":
+		return 2
+	case "uint32":
+		return 4
+	case "uint64":
+		return 8
+	}
+	return 0
+}
+
+// isUUIDArray reports whether typ is [16]byte.
+func isUUIDArray(typ ast.Expr) bool {
+	arr, ok := typ.(*ast.ArrayType)
+	if !ok {
+		return false
+	}
+	n, ok := arr.Len.(*ast.BasicLit)
+	if !ok || n.Kind != token.INT || n.Value != "16" {
+		return false
+	}
+	ident, ok := arr.Elt.(*ast.Ident)
+	return ok && (ident.Name == "byte" || ident.Name == "uint8")
+}
+
+// applyRawCBOROption treats a []byte field as an already-encoded CBOR
+// item (cbor:",rawcbor"). Encoding appends it verbatim instead of
+// wrapping it in a byte string; decoding captures the next item's raw
+// bytes, copied in the Safe path and aliased in the Trusted path.
+func applyRawCBOROption(fs *fieldSpec, typ ast.Expr) error {
+	if !isByteSlice(typ) {
+		return fmt.Errorf("option \"rawcbor\" requires a []byte field, got %s", types.ExprString(typ))
+	}
+
+	data := decodeCaseTemplateData{Field: fs.GoName}
+	var safe, trusted bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&safe, "decodeCaseRawCBOR", data); err != nil {
+		return err
+	}
+	if err := decodeCaseTemplate.ExecuteTemplate(&trusted, "decodeCaseRawCBORTrusted", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = runtimeName("AppendRaw") + "(b, x." + fs.GoName + ")"
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(safe.String(), "\n")
+	fs.DecodeCaseTrust = strings.TrimRight(trusted.String(), "\n")
+	return nil
+}
+
+// isByteSlice reports whether typ is []byte.
+func isByteSlice(typ ast.Expr) bool {
+	arr, ok := typ.(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return false
+	}
+	ident, ok := arr.Elt.(*ast.Ident)
+	return ok && ident.Name == "byte"
+}
+
+// applyStringOption encodes a time.Duration field as a text string in
+// time.Duration.String form (e.g. "1h30m0s") and decodes it with
+// time.ParseDuration, and an integer field as its base-10 text form
+// decoded with strconv.ParseInt or ParseUint (cbor:",string").
+func applyStringOption(fs *fieldSpec, typ ast.Expr) error {
+	data := decodeCaseTemplateData{Field: fs.GoName}
+	safeName, trustedName := "decodeCaseDurationString", "decodeCaseDurationStringTrusted"
+	encodeExpr := runtimeName("AppendString") + "(b, x." + fs.GoName + ".String())"
+	if !isTimeDuration(typ) {
+		ident, ok := typ.(*ast.Ident)
+		if !ok || !intStringBits(ident.Name, &data) {
+			return fmt.Errorf("option \"string\" requires a time.Duration or integer field, got %s", types.ExprString(typ))
+		}
+		data.VarType = ident.Name
+		safeName, trustedName = "decodeCaseIntString", "decodeCaseIntStringTrusted"
+		if data.Signed {
+			encodeExpr = runtimeName("AppendString") + "(b, strconv.FormatInt(int64(x." + fs.GoName + "), 10))"
+		} else {
+			encodeExpr = runtimeName("AppendString") + "(b, strconv.FormatUint(uint64(x." + fs.GoName + "), 10))"
+		}
+	}
+
+	var safe, trusted bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&safe, safeName, data); err != nil {
+		return err
+	}
+	if err := decodeCaseTemplate.ExecuteTemplate(&trusted, trustedName, data); err != nil {
+		return err
+	}
+	fs.EncodeExpr = encodeExpr
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	fs.DecodeCaseSafe = strings.TrimRight(safe.String(), "\n")
+	fs.DecodeCaseTrust = strings.TrimRight(trusted.String(), "\n")
+	return nil
+}
+
+// intStringBits sets data.Signed and data.Bits, the strconv bit size
+// (0 for int and uint), for an integer type name. It reports false for
+// other types.
+func intStringBits(name string, data *decodeCaseTemplateData) bool {
+	switch name {
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		data.Signed = true
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+	default:
+		return false
+	}
+	switch name {
+	case "int8", "uint8", "byte":
+		data.Bits = 8
+	case "int16", "uint16":
+		data.Bits = 16
+	case "int32", "rune", "uint32":
+		data.Bits = 32
+	case "int64", "uint64":
+		data.Bits = 64
+	}
+	return true
+}
+
+// hasSyncLock reports whether st has a field that isSyncLock accepts.
+func hasSyncLock(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if isSyncLock(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsLock reports whether name is a collected struct with a field
+// that isSyncLock accepts.
+func holdsLock(name string) bool {
+	st, ok := collectedStructs[name]
+	return ok && hasSyncLock(st)
+}
+
+// isSyncLock reports whether typ is sync.Mutex or sync.RWMutex. Fields
+// of those types are never encoded, whether exported or not.
+func isSyncLock(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "sync" && (sel.Sel.Name == "Mutex" || sel.Sel.Name == "RWMutex")
+}
+
+// isTimeTime reports whether typ is the selector time.Time.
+func isTimeTime(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && sel.Sel.Name == "Time"
+}
+
+// isTimeDuration reports whether typ is the selector time.Duration.
+func isTimeDuration(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && sel.Sel.Name == "Duration"
+}
+
+// isURL reports whether typ is the selector url.URL.
+func isURL(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "url" && sel.Sel.Name == "URL"
+}
+
+// applyUintOption encodes a signed integer field as a CBOR unsigned
+// integer (cbor:",uint"). Decoding reads a uint64 and rejects values
+// that do not fit back into the field's Go type. Unsigned fields are
+// already encoded this way and are left untouched. With cbor:",unsigned"
+// encoding also fails with ErrNegativeUnsigned instead of writing a
+// negative value as a large unsigned one.
+func applyUintOption(fs *fieldSpec, typ ast.Expr) error {
+	option := "uint"
+	if fs.Unsigned {
+		option = "unsigned"
+	}
+	data := decodeCaseTemplateData{Field: fs.GoName}
+	if ident, ok := typ.(*ast.Ident); ok {
+		data.VarType = ident.Name
+		switch ident.Name {
+		case "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+			return nil
+		case "int":
+			data.MaxValue, data.Bits = "math.MaxInt", 64
+		case "int64":
+			data.MaxValue, data.Bits = "math.MaxInt64", 64
+		case "int32", "rune":
+			data.MaxValue, data.Bits = "math.MaxInt32", 32
+		case "int16":
+			data.MaxValue, data.Bits = "math.MaxInt16", 16
+		case "int8":
+			data.MaxValue, data.Bits = "math.MaxInt8", 8
+		}
+	}
+	if data.MaxValue == "" {
+		return fmt.Errorf("option %q requires an integer field, got %s", option, types.ExprString(typ))
+	}
+
+	var buf bytes.Buffer
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseUintCast", data); err != nil {
+		return err
+	}
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	if fs.Unsigned {
+		var enc bytes.Buffer
+		data := encodeBlockTemplateData{FieldRef: "x." + fs.GoName, KeyName: blockKeyName(*fs)}
+		if err := encodeBlockTemplate.ExecuteTemplate(&enc, "encodeUnsigned", data); err != nil {
+			return err
+		}
+		fs.EncodeExpr, fs.EncodeExprReturnsError = "", false
+		fs.EncodeBlock, fs.EncodeBlockUsesError = strings.TrimRight(enc.String(), "\n"), false
+		return nil
+	}
+	fs.EncodeExpr = runtimeName("AppendUint64") + "(b, uint64(x." + fs.GoName + "))"
+	fs.EncodeExprReturnsError = false
+	fs.EncodeBlock = ""
+	return nil
+}
+
+// interfaceTypes returns the interface types declared at the top level
+// of file, keyed by type name.
+func interfaceTypes(file *ast.File) map[string]*ast.InterfaceType {
+	out := make(map[string]*ast.InterfaceType)
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
 			}
-			if len(ss.Fields) > 0 {
-				generatedStructs[ss.Name] = struct{}{}
-				if len(sizeExprParts) > 0 {
-					// Map header plus per-field key/value contributions.
-					ss.MsgSizeExpr = runtimeName("MapHeaderSize") + " + " + strings.Join(sizeExprParts, " + ")
-				}
-				structs = append(structs, ss)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				out[ts.Name.Name] = it
 			}
 		}
 	}
+	return out
+}
 
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return err
+// structTypes returns the struct types declared at the top level of
+// file, keyed by type name.
+func structTypes(file *ast.File) map[string]*ast.StructType {
+	out := make(map[string]*ast.StructType)
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				out[ts.Name.Name] = st
+			}
+		}
 	}
+	return out
+}
 
-	out, err := os.Create(outputPath)
-	if err != nil {
-		return err
+// interfaceFieldType returns the non-empty interface type of a field
+// declared either inline or as a named interface in the same file. It
+// returns nil for other types, including any and interface{}, which
+// keep using AppendInterface.
+func interfaceFieldType(typ ast.Expr, ifaces map[string]*ast.InterfaceType) *ast.InterfaceType {
+	var it *ast.InterfaceType
+	switch t := typ.(type) {
+	case *ast.InterfaceType:
+		it = t
+	case *ast.Ident:
+		it = ifaces[t.Name]
 	}
-	defer out.Close()
+	if it == nil || it.Methods == nil || len(it.Methods.List) == 0 {
+		return nil
+	}
+	return it
+}
 
-	data := struct {
-		Package string
-		UseOmit bool
-		Structs []structSpec
-	}{
-		Package: pkg,
-		UseOmit: useOmit,
-		Structs: structs,
+// applyInterfaceField encodes and decodes an interface-typed field
+// through its own MarshalCBOR/UnmarshalCBOR methods. Either method may
+// be declared directly or by embedding cbor.Marshaler/cbor.Unmarshaler.
+// Without MarshalCBOR the field falls back to AppendInterface; without
+// UnmarshalCBOR the value is skipped on decode. With ctx the decode
+// goes through cbor.UnmarshalContext.
+func applyInterfaceField(fs *fieldSpec, it *ast.InterfaceType, ctx bool) error {
+	canMarshal, canUnmarshal := interfaceMethods(it)
+
+	fs.EncodeExpr, fs.EncodeExprReturnsError, fs.EncodeBlock = "", false, ""
+	if canMarshal {
+		var buf bytes.Buffer
+		data := encodeBlockTemplateData{FieldRef: "x." + fs.GoName, KeyName: blockKeyName(*fs)}
+		if err := encodeBlockTemplate.ExecuteTemplate(&buf, "encodeInterfaceMarshaler", data); err != nil {
+			return err
+		}
+		fs.EncodeBlock = strings.TrimRight(buf.String(), "\n")
+		fs.EncodeBlockUsesError = true
 	}
 
+	tmplName := "decodeCaseSkip"
+	if canUnmarshal {
+		tmplName = "decodeCaseInterfaceUnmarshal"
+	}
 	var buf bytes.Buffer
-	if err := marshalTemplate.ExecuteTemplate(&buf, "marshal.go.tpl", data); err != nil {
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, tmplName, decodeCaseTemplateData{Field: fs.GoName, Context: ctx}); err != nil {
 		return err
 	}
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
+}
 
-	src, err := imports.Process(outputPath, buf.Bytes(), nil)
-	if err != nil {
-		// Fall back to go/format if goimports fails.
-		if formatted, ferr := format.Source(buf.Bytes()); ferr == nil {
-			src = formatted
-		} else {
-			src = buf.Bytes()
+// interfaceMethods reports whether the method set of it has
+// MarshalCBOR and UnmarshalCBOR, declared directly with the signatures
+// of cbor.Marshaler/cbor.Unmarshaler or by embedding those interfaces.
+// Embedded interfaces from other packages, such as json.Marshaler, do
+// not count.
+func interfaceMethods(it *ast.InterfaceType) (canMarshal, canUnmarshal bool) {
+	for _, m := range it.Methods.List {
+		if len(m.Names) == 0 {
+			switch {
+			case isRuntimeSelector(m.Type, "Marshaler"):
+				canMarshal = true
+			case isRuntimeSelector(m.Type, "Unmarshaler"):
+				canUnmarshal = true
+			}
+			continue
+		}
+		ft, ok := m.Type.(*ast.FuncType)
+		if !ok || !isCBORMethodType(ft) {
+			continue
+		}
+		for _, n := range m.Names {
+			switch n.Name {
+			case "MarshalCBOR":
+				canMarshal = true
+			case "UnmarshalCBOR":
+				canUnmarshal = true
+			}
 		}
 	}
-
-	_, err = out.Write(src)
-	return err
+	return canMarshal, canUnmarshal
 }
 
-// resolveFieldSpec applies tag resolution rules:
-// - cbor tag primary
-// - if no cbor tag, use json tag
-// - if both absent, use Go field name
-func resolveFieldSpec(goName string, tag *ast.BasicLit) fieldSpec {
-	fs := fieldSpec{GoName: goName, CBORName: goName}
-	if tag == nil {
-		return fs
+// isCBORMethodType reports whether ft is func([]byte) ([]byte, error),
+// the signature of MarshalCBOR and UnmarshalCBOR.
+func isCBORMethodType(ft *ast.FuncType) bool {
+	params := listTypes(ft.Params)
+	results := listTypes(ft.Results)
+	if len(params) != 1 || len(results) != 2 {
+		return false
 	}
-	raw := tag.Value
-	if len(raw) >= 2 && (raw[0] == '`' && raw[len(raw)-1] == '`') {
-		raw = raw[1 : len(raw)-1]
+	errIdent, ok := results[1].(*ast.Ident)
+	return isByteSlice(params[0]) && isByteSlice(results[0]) && ok && errIdent.Name == "error"
+}
+
+// listTypes lists the type of each parameter or result in fl, once
+// per name, so (a, b []byte) yields two entries.
+func listTypes(fl *ast.FieldList) []ast.Expr {
+	if fl == nil {
+		return nil
 	}
-	st := reflect.StructTag(raw)
-	if v, ok := parseTag(st.Get("cbor")); ok {
-		if v == "-" {
-			fs.Ignore = true
-			return fs
+	var out []ast.Expr
+	for _, f := range fl.List {
+		for n := max(len(f.Names), 1); n > 0; n-- {
+			out = append(out, f.Type)
 		}
-		fs.CBORName, fs.OmitEmpty = splitNameOptions(v)
-		return fs
 	}
-	if v, ok := parseTag(st.Get("json")); ok {
-		if v == "-" {
-			fs.Ignore = true
-			return fs
-		}
-		fs.CBORName, fs.OmitEmpty = splitNameOptions(v)
-		return fs
+	return out
+}
+
+// isMarshalerSlice reports whether typ is a slice whose elements are an
+// interface with MarshalCBOR: the runtime's Marshaler, or a non-empty
+// interface declared inline or in the same file.
+func isMarshalerSlice(typ ast.Expr, ifaces map[string]*ast.InterfaceType) bool {
+	arr, ok := typ.(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return false
 	}
-	return fs
+	if _, ok := arr.Elt.(*ast.SelectorExpr); ok {
+		return isRuntimeSelector(arr.Elt, "Marshaler")
+	}
+	it := interfaceFieldType(arr.Elt, ifaces)
+	if it == nil {
+		return false
+	}
+	canMarshal, _ := interfaceMethods(it)
+	return canMarshal
 }
 
-// parseTag returns the raw tag string and whether it was present.
-func parseTag(v string) (string, bool) {
-	if v == "" {
-		return "", false
+// applyInterfaceSliceField encodes a slice of interface values with
+// MarshalCBOR by calling the method on each element, writing null for
+// nil ones. There is no concrete element type to decode into, so the
+// decoders skip the array.
+func applyInterfaceSliceField(fs *fieldSpec) error {
+	var buf bytes.Buffer
+	data := encodeBlockTemplateData{FieldRef: "x." + fs.GoName, KeyName: blockKeyName(*fs)}
+	if err := encodeBlockTemplate.ExecuteTemplate(&buf, "encodeSliceInterfaceMarshaler", data); err != nil {
+		return err
 	}
-	return v, true
+	fs.EncodeExpr, fs.EncodeExprReturnsError = "", false
+	fs.EncodeBlock = strings.TrimRight(buf.String(), "\n")
+	fs.EncodeBlockUsesError = true
+
+	buf.Reset()
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseSkip", decodeCaseTemplateData{}); err != nil {
+		return err
+	}
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	fs.DecodeCaseTrust = fs.DecodeCaseSafe
+	return nil
 }
 
-// splitNameOptions splits a tag like "name,omitempty" into name and omitEmpty flag.
-func splitNameOptions(tag string) (string, bool) {
-	parts := strings.Split(tag, ",")
-	name := parts[0]
-	omit := false
-	for _, p := range parts[1:] {
-		if p == "omitempty" {
-			omit = true
+// oneofVariant is one struct type of a cbor:",oneof" field together
+// with the map keys that identify it on decode.
+type oneofVariant struct {
+	Type string
+	Keys []string
+}
+
+// applyOneOfOption encodes an interface field holding one of a closed
+// set of struct types with a type switch over those types, and decodes
+// it by looking for a map key that only one of them has
+// (cbor:",oneof=A,B"). The types must be map-encoded structs declared in
+// the same file and not writeonly; decoding stores a pointer to the
+// matching type.
+func applyOneOfOption(fs *fieldSpec, typ ast.Expr, ifaces map[string]*ast.InterfaceType, fileStructs map[string]*ast.StructType, ctx bool) error {
+	isIface := false
+	switch t := typ.(type) {
+	case *ast.InterfaceType:
+		isIface = true
+	case *ast.Ident:
+		_, isIface = ifaces[t.Name]
+		isIface = isIface || t.Name == "any"
+	}
+	if !isIface {
+		return fmt.Errorf("option \"oneof\" requires an interface field, got %s", types.ExprString(typ))
+	}
+
+	// Collect each variant's keys, then keep those no other variant has.
+	keys := make([][]string, len(fs.OneOf))
+	count := make(map[string]int)
+	for i, name := range fs.OneOf {
+		st, ok := fileStructs[name]
+		if !ok {
+			return fmt.Errorf("option \"oneof\": %q is not a struct type declared in this file", name)
+		}
+		stOpts := structOptions(st)
+		if stOpts.Has("toarray") || stOpts.Has("mapstruct") || stOpts.Has("ignore") {
+			return fmt.Errorf("option \"oneof\": %s is not encoded as a map", name)
+		}
+		if _, ok := stOpts.Value("keytype"); ok {
+			return fmt.Errorf("option \"oneof\": %s is not keyed by field names", name)
+		}
+		if stOpts.Has("writeonly") {
+			return fmt.Errorf("option \"oneof\": %s is writeonly and cannot be decoded", name)
+		}
+		seen := make(map[string]bool)
+		for _, f := range st.Fields.List {
+			if len(f.Names) == 0 || !ast.IsExported(f.Names[0].Name) || isSyncLock(f.Type) {
+				continue
+			}
+			vfs := resolveFieldSpec(f.Names[0].Name, f.Tag)
+			if vfs.Ignore || vfs.Flatten || vfs.Fallthrough || seen[vfs.CBORName] {
+				continue
+			}
+			seen[vfs.CBORName] = true
+			keys[i] = append(keys[i], vfs.CBORName)
+			count[vfs.CBORName]++
 		}
 	}
-	if name == "" {
-		name = "-"
+	variants := make([]oneofVariant, len(fs.OneOf))
+	for i, name := range fs.OneOf {
+		variants[i].Type = name
+		for _, k := range keys[i] {
+			if count[k] == 1 {
+				variants[i].Keys = append(variants[i].Keys, k)
+			}
+		}
+		if len(variants[i].Keys) == 0 {
+			return fmt.Errorf("option \"oneof\": %s has no key that the other types lack", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	data := encodeBlockTemplateData{FieldRef: "x." + fs.GoName, KeyName: blockKeyName(*fs), Variants: fs.OneOf}
+	if err := encodeBlockTemplate.ExecuteTemplate(&buf, "encodeOneOf", data); err != nil {
+		return err
+	}
+	fs.EncodeExpr, fs.EncodeExprReturnsError = "", false
+	fs.EncodeBlock = strings.TrimRight(buf.String(), "\n")
+	fs.EncodeBlockUsesError = true
+
+	dc := decodeCaseTemplateData{Field: fs.GoName, Context: ctx, Variants: variants}
+	buf.Reset()
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseOneOf", dc); err != nil {
+		return err
+	}
+	fs.DecodeCaseSafe = strings.TrimRight(buf.String(), "\n")
+	dc.Trusted = true
+	buf.Reset()
+	if err := decodeCaseTemplate.ExecuteTemplate(&buf, "decodeCaseOneOf", dc); err != nil {
+		return err
 	}
-	return name, omit
+	fs.DecodeCaseTrust = strings.TrimRight(buf.String(), "\n")
+	return nil
 }
 
 type omitEmptyCondTemplateData struct {
@@ -311,21 +1931,48 @@ type omitEmptyCondTemplateData struct {
 var omitEmptyCondTemplate = template.Must(template.New("omit_empty_cond").Funcs(templateFuncs).ParseFS(tmplfs.FS, "zero_check.go.tpl"))
 
 type decodeCaseTemplateData struct {
-	Field    string
-	VarType  string
-	ReadFunc string
+	Field       string
+	VarType     string
+	ReadFunc    string
+	MaxValue    string
+	Bits        int
+	KeyType     string
+	KeyReadFunc string
+	// Signed selects strconv.ParseInt over ParseUint for integers
+	// decoded from text (cbor:",string").
+	Signed bool
+	// Context passes the enclosing decoder's ctx to nested decodes.
+	Context bool
+	// Body is the decode snippet wrapped by decodeCaseNullable.
+	Body string
+	// Pointer makes decodeCaseURLField store a *url.URL, with null as
+	// nil.
+	Pointer bool
+	// Variants and Trusted drive decodeCaseOneOf.
+	Variants []oneofVariant
+	Trusted  bool
+	// Lock makes the struct slice cases decode each element in place,
+	// since VarType holds a sync lock that must not be copied.
+	Lock bool
 }
 
 var decodeCaseTemplate = template.Must(template.New("decode_case").Funcs(templateFuncs).ParseFS(tmplfs.FS, "decode_case.go.tpl"))
 
 type encodeBlockTemplateData struct {
-	StructName string
-	GoField    string
-	CBORName   string
-	FieldRef   string
-	KeyName    string
-	ElemVar    string
-	AppendFunc string
+	StructName    string
+	GoField       string
+	CBORName      string
+	FieldRef      string
+	KeyName       string
+	ElemVar       string
+	AppendFunc    string
+	KeyAppendFunc string
+	// Cond and Body are the zero check and value encoder wrapped by
+	// encodeNullable.
+	Cond string
+	Body string
+	// Variants lists the types switched over by encodeOneOf.
+	Variants []string
 }
 
 var encodeBlockTemplate = template.Must(template.New("encode_block").Funcs(templateFuncs).ParseFS(tmplfs.FS, "encode_block.go.tpl"))
@@ -342,6 +1989,16 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 
 	switch t := typ.(type) {
 	case *ast.Ident:
+		// A type's own CBORSize takes precedence over the generated
+		// Msgsize, e.g. for variable-length contents.
+		if _, ok := cborSizers[t.Name]; ok {
+			val = fieldRef + ".CBORSize()"
+			break
+		}
+		if isSizedStruct(t) {
+			val = fieldRef + ".Msgsize()"
+			break
+		}
 		switch t.Name {
 		case "string":
 			val = rt("StringPrefixSize") + " + len(" + fieldRef + ")"
@@ -375,18 +2032,44 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 			return "", false
 		}
 	case *ast.SelectorExpr:
-		// Support common time-based primitives.
+		// Support common time-based and network address primitives.
 		switch t.Sel.Name {
 		case "Time":
 			val = rt("TimeSize")
 		case "Duration":
 			val = rt("DurationSize")
+		case "IP":
+			val = rt("IPSize")
+		case "HardwareAddr":
+			val = rt("HardwareAddrSize")
 		default:
 			return "", false
 		}
+	case *ast.StarExpr:
+		if isTimeDuration(t.X) {
+			val = rt("DurationSize")
+			break
+		}
+		// *T for a generated struct T; nil encodes as a single byte.
+		if !isSizedStruct(t.X) {
+			return "", false
+		}
+		val = rt("PtrMsgsize") + "(" + fieldRef + ")"
 	case *ast.ArrayType:
+		if t.Len != nil {
+			return "", false
+		}
+		// []T and []*T for a generated struct T: sum element sizes.
+		if isSizedStruct(t.Elt) {
+			val = rt("SliceMsgsize") + "(" + fieldRef + ")"
+			break
+		}
+		if star, ok := t.Elt.(*ast.StarExpr); ok && isSizedStruct(star.X) {
+			val = rt("PtrSliceMsgsize") + "(" + fieldRef + ")"
+			break
+		}
 		ident, ok := t.Elt.(*ast.Ident)
-		if !ok || t.Len != nil {
+		if !ok {
 			return "", false
 		}
 		// []byte: use bytes prefix + len(slice)
@@ -397,7 +2080,7 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 			var elem string
 			switch ident.Name {
 			case "string":
-				// Prefix per element; data length is accounted for elsewhere at runtime.
+				// Prefix per element; StringsLen below adds the data.
 				elem = rt("StringPrefixSize")
 			case "bool":
 				elem = rt("BoolSize")
@@ -430,18 +2113,30 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 				elem = "0"
 			}
 			val = rt("ArrayHeaderSize") + " + len(" + fieldRef + ")*" + elem
+			if ident.Name == "string" {
+				val += " + " + rt("StringsLen") + "(" + fieldRef + ")"
+			}
 		}
 	case *ast.MapType:
-		// map[string]T: approximate as header plus per-entry constant.
+		// map[string]T and map[intN]T: approximate as header plus
+		// per-entry constant, plus the length of string keys and values.
 		keyIdent, okKey := t.Key.(*ast.Ident)
 		valIdent, okVal := t.Value.(*ast.Ident)
-		if !okKey || !okVal || keyIdent.Name != "string" {
+		if !okKey || !okVal {
 			return "", false
 		}
+		keySize := rt("StringPrefixSize")
+		if keyIdent.Name != "string" {
+			_, _, size, ok := intMapKey(keyIdent.Name)
+			if !ok {
+				return "", false
+			}
+			keySize = size
+		}
 		var elem string
 		switch valIdent.Name {
 		case "string":
-			// Prefix for the value; data length is accounted for elsewhere at runtime.
+			// Prefix for the value; MapValuesLen below adds the data.
 			elem = rt("StringPrefixSize")
 		case "bool":
 			elem = rt("BoolSize")
@@ -473,7 +2168,13 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 			// Fallback: only header + key prefix per entry.
 			elem = "0"
 		}
-		val = rt("MapHeaderSize") + " + len(" + fieldRef + ")*(" + rt("StringPrefixSize") + " + " + elem + ")"
+		val = rt("MapHeaderSize") + " + len(" + fieldRef + ")*(" + keySize + " + " + elem + ")"
+		if keyIdent.Name == "string" {
+			val += " + " + rt("MapKeysLen") + "(" + fieldRef + ")"
+		}
+		if valIdent.Name == "string" {
+			val += " + " + rt("MapValuesLen") + "(" + fieldRef + ")"
+		}
 	default:
 		return "", false
 	}
@@ -484,6 +2185,174 @@ func fieldSizeExpr(cborName, goName string, typ ast.Expr) (expr string, ok bool)
 	return key + " + " + val, true
 }
 
+// intMapKey returns the runtime append, read and size names for the
+// narrow integer map key types. Keys travel as CBOR uint/negint; the
+// sized Read*Bytes helpers bounds-check them before casting, so an
+// out-of-range key fails with UintOverflow or IntOverflow.
+func intMapKey(name string) (appendFunc, readFunc, size string, ok bool) {
+	rt := runtimeName
+	switch name {
+	case "uint32":
+		return rt("AppendUint32"), rt("ReadUint32Bytes"), rt("Uint32Size"), true
+	case "uint16":
+		return rt("AppendUint16"), rt("ReadUint16Bytes"), rt("Uint16Size"), true
+	case "uint8", "byte":
+		return rt("AppendUint8"), rt("ReadUint8Bytes"), rt("Uint8Size"), true
+	case "int32", "rune":
+		return rt("AppendInt32"), rt("ReadInt32Bytes"), rt("Int32Size"), true
+	case "int16":
+		return rt("AppendInt16"), rt("ReadInt16Bytes"), rt("Int16Size"), true
+	case "int8":
+		return rt("AppendInt8"), rt("ReadInt8Bytes"), rt("Int8Size"), true
+	}
+	return "", "", "", false
+}
+
+// netAddrDecodeCase fills data for net.IP and net.HardwareAddr fields,
+// which are carried as tag 260 byte strings. It reports false for other
+// net types.
+func netAddrDecodeCase(name string, data *decodeCaseTemplateData) bool {
+	switch name {
+	case "IP":
+		data.VarType = "net.IP"
+		data.ReadFunc = runtimeName("ReadIPBytes")
+	case "HardwareAddr":
+		data.VarType = "net.HardwareAddr"
+		data.ReadFunc = runtimeName("ReadHardwareAddrBytes")
+	default:
+		return false
+	}
+	return true
+}
+
+// scalarAppendFunc returns the runtime Append* helper for a scalar Go
+// type name, or "" if the type is not a scalar.
+func scalarAppendFunc(name string) string {
+	rt := runtimeName
+	switch name {
+	case "string":
+		return rt("AppendString")
+	case "bool":
+		return rt("AppendBool")
+	case "int":
+		return rt("AppendInt")
+	case "int8":
+		return rt("AppendInt8")
+	case "int16":
+		return rt("AppendInt16")
+	case "int32", "rune":
+		return rt("AppendInt32")
+	case "int64":
+		return rt("AppendInt64")
+	case "uint":
+		return rt("AppendUint")
+	case "uint8", "byte":
+		return rt("AppendUint8")
+	case "uint16":
+		return rt("AppendUint16")
+	case "uint32":
+		return rt("AppendUint32")
+	case "uint64":
+		return rt("AppendUint64")
+	case "float32":
+		return rt("AppendFloat32")
+	case "float64":
+		return rt("AppendFloat64")
+	}
+	return ""
+}
+
+// scalarReadFunc returns the Go temporary type and runtime Read*Bytes
+// helper for a scalar Go type name, or "" if the type is not a scalar.
+func scalarReadFunc(name string) (varType, readFunc string) {
+	rt := runtimeName
+	switch name {
+	case "string":
+		return "string", rt("ReadStringBytes")
+	case "bool":
+		return "bool", rt("ReadBoolBytes")
+	case "int":
+		return "int", rt("ReadIntBytes")
+	case "int8":
+		return "int8", rt("ReadInt8Bytes")
+	case "int16":
+		return "int16", rt("ReadInt16Bytes")
+	case "int32", "rune":
+		return "int32", rt("ReadInt32Bytes")
+	case "int64":
+		return "int64", rt("ReadInt64Bytes")
+	case "uint":
+		return "uint", rt("ReadUintBytes")
+	case "uint8", "byte":
+		return "uint8", rt("ReadUint8Bytes")
+	case "uint16":
+		return "uint16", rt("ReadUint16Bytes")
+	case "uint32":
+		return "uint32", rt("ReadUint32Bytes")
+	case "uint64":
+		return "uint64", rt("ReadUint64Bytes")
+	case "float32":
+		return "float32", rt("ReadFloat32Bytes")
+	case "float64":
+		return "float64", rt("ReadFloat64Bytes")
+	}
+	return "", ""
+}
+
+// isSizedStruct reports whether typ names a struct with a generated
+// Msgsize method.
+func isSizedStruct(typ ast.Expr) bool {
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = sizedStructs[ident.Name]
+	return ok
+}
+
+// selectorMethodType returns "pkg.T" for a type from another package
+// that is known to have MarshalCBOR/UnmarshalCBOR methods, and so is
+// encoded and decoded through them: one Options.KnownGenerated declares,
+// or the runtime's Raw or Number. Other types from other packages keep
+// the AppendInterface fallback, which covers those the runtime handles.
+func selectorMethodType(sel *ast.SelectorExpr) (string, bool) {
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	name := pkg.Name + "." + sel.Sel.Name
+	if _, ok := importedGenerated[name]; ok {
+		return name, true
+	}
+	if isRuntimeSelector(sel, "Raw") || isRuntimeSelector(sel, "Number") {
+		return name, true
+	}
+	return "", false
+}
+
+// methodType returns the named type, without any pointer, of a field
+// that the generated code encodes and decodes through the type's own
+// MarshalCBOR/UnmarshalCBOR methods: a local type that is neither a
+// scalar nor a generated struct, or one accepted by selectorMethodType.
+func methodType(typ ast.Expr) (string, bool) {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if scalarAppendFunc(t.Name) != "" || t.Name == "any" || t.Name == "error" {
+			return "", false
+		}
+		if _, ok := generatedStructs[t.Name]; ok {
+			return "", false
+		}
+		return t.Name, true
+	case *ast.SelectorExpr:
+		return selectorMethodType(t)
+	}
+	return "", false
+}
+
 // encodeBlockForField builds a multi-statement encode block for
 // selected map and slice shapes that are hot in JetStream meta
 // snapshot structs. It returns an empty string when no special
@@ -503,6 +2372,10 @@ func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) (str
 	tmplName := ""
 
 	switch t := typ.(type) {
+	case *ast.StarExpr:
+		if isTimeDuration(t.X) {
+			tmplName = "encodePtrDuration"
+		}
 	case *ast.MapType:
 		keyIdent, okKey := t.Key.(*ast.Ident)
 		if !okKey {
@@ -521,6 +2394,20 @@ func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) (str
 			}
 		}
 
+		// map[intN]S for scalar S, and map[intN]*T where *T has MarshalCBOR.
+		if keyAppend, _, _, ok := intMapKey(keyIdent.Name); ok {
+			data.KeyAppendFunc = keyAppend
+			if starVal, ok := t.Value.(*ast.StarExpr); ok {
+				if ident, ok := starVal.X.(*ast.Ident); ok && ast.IsExported(ident.Name) {
+					tmplName = "encodeMapIntKeyPtrMarshaler"
+				}
+			} else if valIdent, ok := t.Value.(*ast.Ident); ok {
+				if data.AppendFunc = scalarAppendFunc(valIdent.Name); data.AppendFunc != "" {
+					tmplName = "encodeMapIntKeyScalar"
+				}
+			}
+		}
+
 		// map[string]T shapes.
 		if tmplName == "" && keyIdent.Name == "string" {
 			// map[string]S for scalar S, map[string]string, and map[string]T where T has MarshalCBOR.
@@ -640,6 +2527,7 @@ func encodeBlockForField(structName, goName, cborName string, typ ast.Expr) (str
 	usesErr := false
 	switch tmplName {
 	case "encodeMapUint64PtrMarshaler",
+		"encodeMapIntKeyPtrMarshaler",
 		"encodeMapStrValueMarshaler",
 		"encodeMapStrPtrMarshaler",
 		"encodeSlicePtrMarshaler",
@@ -717,6 +2605,8 @@ func omitEmptyCondExpr(goName string, typ ast.Expr) (expr string, ok bool) {
 			data.Kind = "time"
 		case "Duration":
 			data.Kind = "numeric"
+		case "IP", "HardwareAddr":
+			data.Kind = "slice"
 		default:
 			return "", false
 		}
@@ -748,8 +2638,9 @@ func omitEmptyCondExpr(goName string, typ ast.Expr) (expr string, ok bool) {
 
 // decodeCaseExprSafe builds the decode body for the Safe path.
 // It uses the validated, allocating helpers like ReadStringBytes.
-func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool) {
-	data := decodeCaseTemplateData{Field: goName}
+// With ctx, nested decodes receive the decoder's context.
+func decodeCaseExprSafe(structName, goName string, typ ast.Expr, ctx bool) (string, bool) {
+	data := decodeCaseTemplateData{Field: goName, Context: ctx}
 	tmplName := ""
 	rt := runtimeName
 
@@ -807,6 +2698,11 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 			tmplName = "decodeCaseBasic"
 		}
 	case *ast.SelectorExpr:
+		if name, ok := selectorMethodType(t); ok {
+			data.VarType = name
+			tmplName = "decodeCaseUnmarshalField"
+			break
+		}
 		if pkg, ok := t.X.(*ast.Ident); ok {
 			switch pkg.Name {
 			case "time":
@@ -821,6 +2717,16 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 					return "", false
 				}
 				tmplName = "decodeCaseBasic"
+			case "net":
+				if !netAddrDecodeCase(t.Sel.Name, &data) {
+					return "", false
+				}
+				tmplName = "decodeCaseNetAddr"
+			case "url":
+				if t.Sel.Name != "URL" {
+					return "", false
+				}
+				tmplName = "decodeCaseURLField"
 			case "json":
 				if t.Sel.Name != "Number" {
 					return "", false
@@ -828,13 +2734,6 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 				data.VarType = "json.Number"
 				data.ReadFunc = rt("ReadJSONNumberBytes")
 				tmplName = "decodeCaseBasic"
-			case "cbor":
-				if t.Sel.Name == "Raw" || t.Sel.Name == "Number" {
-					data.VarType = ""
-					tmplName = "decodeCaseUnmarshalField"
-					break
-				}
-				return "", false
 			default:
 				return "", false
 			}
@@ -898,6 +2797,7 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 				data.ReadFunc = rt("ReadFloat64Bytes")
 			default:
 				data.VarType = ident.Name
+				data.Lock = holdsLock(ident.Name)
 				tmplName = "decodeCaseSliceStruct"
 			}
 			if tmplName == "" {
@@ -935,6 +2835,25 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 			}
 			return "", false
 		}
+		// map[intN]S and map[intN]*T with bounds-checked keys
+		if _, keyRead, _, ok := intMapKey(keyIdent.Name); ok {
+			data.KeyType = keyIdent.Name
+			data.KeyReadFunc = keyRead
+			if star, okVal := t.Value.(*ast.StarExpr); okVal {
+				if ident, ok2 := star.X.(*ast.Ident); ok2 {
+					data.VarType = ident.Name
+					tmplName = "decodeCaseMapIntKeyPtr"
+					break
+				}
+			}
+			if valIdent, okVal := t.Value.(*ast.Ident); okVal {
+				if data.VarType, data.ReadFunc = scalarReadFunc(valIdent.Name); data.ReadFunc != "" {
+					tmplName = "decodeCaseMapIntKeyBasic"
+					break
+				}
+			}
+			return "", false
+		}
 		// map[string]T containers
 		if keyIdent.Name != "string" {
 			return "", false
@@ -1003,6 +2922,22 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 		}
 		return "", false
 	case *ast.StarExpr:
+		if isTimeDuration(t.X) {
+			tmplName = "decodeCasePtrDuration"
+			break
+		}
+		if isURL(t.X) {
+			data.Pointer = true
+			tmplName = "decodeCaseURLField"
+			break
+		}
+		if sel, ok := t.X.(*ast.SelectorExpr); ok {
+			if name, ok := selectorMethodType(sel); ok {
+				data.VarType = name
+				tmplName = "decodeCasePtrUnmarshalField"
+				break
+			}
+		}
 		// Pointer to user-defined type with UnmarshalCBOR.
 		if ident, ok := t.X.(*ast.Ident); ok {
 			data.VarType = ident.Name
@@ -1028,8 +2963,8 @@ func decodeCaseExprSafe(structName, goName string, typ ast.Expr) (string, bool)
 // decodeCaseExprTrusted builds the decode body for the Trusted path.
 // For strings it uses zero-copy ReadStringZC + UnsafeString; other
 // scalar types share the same helpers as the Safe path.
-func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, bool) {
-	data := decodeCaseTemplateData{Field: goName}
+func decodeCaseExprTrusted(structName, goName string, typ ast.Expr, ctx bool) (string, bool) {
+	data := decodeCaseTemplateData{Field: goName, Context: ctx}
 	tmplName := ""
 	rt := runtimeName
 
@@ -1056,6 +2991,30 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 			break
 		}
 
+		// map[intN]S and map[intN]*T with bounds-checked keys (Trusted path)
+		if _, keyRead, _, ok := intMapKey(keyIdent.Name); ok {
+			data.KeyType = keyIdent.Name
+			data.KeyReadFunc = keyRead
+			if star, okVal := t.Value.(*ast.StarExpr); okVal {
+				if ident, ok2 := star.X.(*ast.Ident); ok2 {
+					data.VarType = ident.Name
+					if _, ok := generatedStructs[ident.Name]; ok {
+						tmplName = "decodeCaseMapIntKeyPtrTrusted"
+					} else {
+						tmplName = "decodeCaseMapIntKeyPtr"
+					}
+					break
+				}
+			}
+			if valIdent, okVal := t.Value.(*ast.Ident); okVal {
+				if data.VarType, data.ReadFunc = scalarReadFunc(valIdent.Name); data.ReadFunc != "" {
+					tmplName = "decodeCaseMapIntKeyBasic"
+					break
+				}
+			}
+			return "", false
+		}
+
 		// map[string]T containers for scalar or struct T (Trusted path)
 		if keyIdent.Name != "string" {
 			return "", false
@@ -1189,6 +3148,15 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 			tmplName = "decodeCaseBasic"
 		}
 	case *ast.SelectorExpr:
+		if name, ok := selectorMethodType(t); ok {
+			data.VarType = name
+			if _, ok := importedGenerated[name]; ok {
+				tmplName = "decodeCaseTrustedField"
+			} else {
+				tmplName = "decodeCaseUnmarshalField"
+			}
+			break
+		}
 		if pkg, ok := t.X.(*ast.Ident); ok {
 			switch pkg.Name {
 			case "time":
@@ -1205,6 +3173,16 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				if tmplName == "" {
 					tmplName = "decodeCaseBasic"
 				}
+			case "net":
+				if !netAddrDecodeCase(t.Sel.Name, &data) {
+					return "", false
+				}
+				tmplName = "decodeCaseNetAddr"
+			case "url":
+				if t.Sel.Name != "URL" {
+					return "", false
+				}
+				tmplName = "decodeCaseURLField"
 			case "json":
 				if t.Sel.Name != "Number" {
 					return "", false
@@ -1214,14 +3192,6 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				if tmplName == "" {
 					tmplName = "decodeCaseBasic"
 				}
-			case "cbor":
-				if t.Sel.Name == "Raw" || t.Sel.Name == "Number" {
-					if tmplName == "" {
-						tmplName = "decodeCaseUnmarshalField"
-					}
-					break
-				}
-				return "", false
 			default:
 				return "", false
 			}
@@ -1284,6 +3254,7 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 				data.ReadFunc = rt("ReadFloat64Bytes")
 			default:
 				data.VarType = ident.Name
+				data.Lock = holdsLock(ident.Name)
 				if _, ok := generatedStructs[ident.Name]; ok {
 					tmplName = "decodeCaseSliceStructTrusted"
 				} else {
@@ -1308,6 +3279,26 @@ func decodeCaseExprTrusted(structName, goName string, typ ast.Expr) (string, boo
 		}
 		return "", false
 	case *ast.StarExpr:
+		if isTimeDuration(t.X) {
+			tmplName = "decodeCasePtrDuration"
+			break
+		}
+		if isURL(t.X) {
+			data.Pointer = true
+			tmplName = "decodeCaseURLField"
+			break
+		}
+		if sel, ok := t.X.(*ast.SelectorExpr); ok {
+			if name, ok := selectorMethodType(sel); ok {
+				data.VarType = name
+				if _, ok := importedGenerated[name]; ok {
+					tmplName = "decodeCasePtrTrustedField"
+				} else {
+					tmplName = "decodeCasePtrUnmarshalField"
+				}
+				break
+			}
+		}
 		// Pointer to user-defined type. If the underlying type is a
 		// generated struct, prefer DecodeTrusted; otherwise fall back
 		// to the UnmarshalCBOR-based pointer path.
@@ -1414,14 +3405,24 @@ func encodeExprForField(goName string, typ ast.Expr) (expr string, returnsErr bo
 		}
 
 	case *ast.StarExpr:
-		// *T where T is exported; assume *T implements Marshaler.
+		// *T where T is exported or from another package; assume *T
+		// implements Marshaler.
 		if ident, ok := t.X.(*ast.Ident); ok && ast.IsExported(ident.Name) {
 			return rt("AppendPtrMarshaler") + "(b, " + field + ")", true
 		}
+		if isURL(t.X) {
+			return rt("AppendURL") + "(b, " + field + ")", false
+		}
+		if sel, ok := t.X.(*ast.SelectorExpr); ok {
+			if _, ok := selectorMethodType(sel); ok {
+				return rt("AppendPtrMarshaler") + "(b, " + field + ")", true
+			}
+		}
 
 	case *ast.SelectorExpr:
 		// Handle common selector-based types, such as time.Time,
-		// time.Duration, and json.RawMessage, with direct calls.
+		// time.Duration, net.IP, url.URL and json.RawMessage, with
+		// direct calls.
 		if pkg, ok := t.X.(*ast.Ident); ok {
 			switch pkg.Name {
 			case "time":
@@ -1431,12 +3432,28 @@ func encodeExprForField(goName string, typ ast.Expr) (expr string, returnsErr bo
 				case "Duration":
 					return rt("AppendDuration") + "(b, " + field + ")", false
 				}
+			case "net":
+				switch t.Sel.Name {
+				case "IP":
+					return rt("AppendIP") + "(b, " + field + ")", false
+				case "HardwareAddr":
+					return rt("AppendHardwareAddr") + "(b, " + field + ")", false
+				}
+			case "url":
+				if t.Sel.Name == "URL" {
+					return rt("AppendURL") + "(b, &" + field + ")", false
+				}
 			case "json":
 				if t.Sel.Name == "RawMessage" {
 					return rt("AppendBytes") + "(b, []byte(" + field + "))", false
 				}
 			}
 		}
+		// Types from other packages without a built-in encoding are
+		// assumed to implement Marshaler, like local named types.
+		if _, ok := selectorMethodType(t); ok {
+			return field + ".MarshalCBOR(b)", true
+		}
 	}
 
 	// Fallback: let AppendInterface handle this field.
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// validationCheck is one constraint checked by a generated Validate
+// method: Cond holds when the field violates Rule, the tag option that
+// declared it (e.g. "min=0").
+type validationCheck struct {
+	Field string
+	Cond  string
+	Rule  string
+}
+
+// validationRules returns the constraint options in a field tag:
+// min=N and max=N on numeric fields, minlen=N and maxlen=N on strings,
+// slices and maps, and required on fields with an empty value.
+func validationRules(opts tagOptions) []string {
+	var rules []string
+	for _, o := range opts {
+		name, _, _ := strings.Cut(o, "=")
+		switch name {
+		case "min", "max", "minlen", "maxlen", "required":
+			rules = append(rules, o)
+		}
+	}
+	return rules
+}
+
+// validationChecks turns the rules declared on fs into the conditions
+// its Validate method tests. It returns an error when a rule does not
+// apply to the field's Go type or has a malformed bound.
+func validationChecks(fs fieldSpec, typ ast.Expr) ([]validationCheck, error) {
+	var checks []validationCheck
+	field := "x." + fs.GoName
+	for _, rule := range fs.Validate {
+		name, value, _ := strings.Cut(rule, "=")
+		var cond string
+		switch name {
+		case "min", "max":
+			if err := checkNumericBound(typ, value); err != nil {
+				return nil, fmt.Errorf("%s option: %w", name, err)
+			}
+			op := " < "
+			if name == "max" {
+				op = " > "
+			}
+			cond = field + op + value
+		case "minlen", "maxlen":
+			if !hasLen(typ) {
+				return nil, fmt.Errorf("%s option requires a string, slice or map field", name)
+			}
+			if n, err := strconv.Atoi(value); err != nil || n < 0 {
+				return nil, fmt.Errorf("%s option requires a non-negative integer, got %q", name, value)
+			}
+			op := " < "
+			if name == "maxlen" {
+				op = " > "
+			}
+			cond = "len(" + field + ")" + op + value
+		case "required":
+			nonEmpty, ok := omitEmptyCondExpr(fs.GoName, typ)
+			if !ok {
+				return nil, fmt.Errorf("required option is not supported for this field type")
+			}
+			cond = "!(" + nonEmpty + ")"
+		}
+		checks = append(checks, validationCheck{Field: fs.CBORName, Cond: cond, Rule: rule})
+	}
+	return checks, nil
+}
+
+// checkNumericBound reports whether value is a valid constant bound for
+// a field of the integer or float type typ.
+func checkNumericBound(typ ast.Expr, value string) error {
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return fmt.Errorf("requires an integer or float field")
+	}
+	var err error
+	switch ident.Name {
+	case "int", "int64":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "int8":
+		_, err = strconv.ParseInt(value, 10, 8)
+	case "int16":
+		_, err = strconv.ParseInt(value, 10, 16)
+	case "int32", "rune":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "uint", "uint64":
+		_, err = strconv.ParseUint(value, 10, 64)
+	case "uint8", "byte":
+		_, err = strconv.ParseUint(value, 10, 8)
+	case "uint16":
+		_, err = strconv.ParseUint(value, 10, 16)
+	case "uint32":
+		_, err = strconv.ParseUint(value, 10, 32)
+	case "float32", "float64":
+		_, err = strconv.ParseFloat(value, 64)
+	default:
+		return fmt.Errorf("requires an integer or float field")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid bound %q for %s field", value, ident.Name)
+	}
+	return nil
+}
+
+// hasLen reports whether typ is a string, slice or map, whose length a
+// minlen or maxlen rule can bound.
+func hasLen(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name == "string"
+	case *ast.ArrayType:
+		return t.Len == nil
+	case *ast.MapType:
+		return true
+	}
+	return false
+}
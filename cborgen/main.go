@@ -17,6 +17,13 @@ import (
 //   - input: Go file or directory
 //   - output: override for the generated file (file mode only)
 //   - verbose: turn on diagnostic logging
+//   - nolint: mark generated files with a file-level //nolint:all
+//   - ignore-errors: keep going after a file fails in directory mode
+//   - cddl: emit a CBORSchema() method returning a CDDL rule
+//   - context: make the generated decoders take a context.Context
+//   - verify: check that generated files are up to date instead of writing them
+//   - known-generated-pkg: types in other packages that also have generated code
+//   - no-go-generate: leave out the //go:generate directive that reruns cborgen
 //
 // In directory mode, each source file gets its own
 // "*_cbor.go" companion file (recursive) and the --output flag is rejected.
@@ -25,6 +32,15 @@ type CLI struct {
 	Output  string   `short:"o" help:"Output file (file input only; defaults to {input}_cbor.go)"`
 	Structs []string `short:"s" help:"Only generate for these struct types (may be repeated)"`
 	Verbose bool     `short:"v" help:"Enable verbose diagnostics"`
+	NoLint  bool     `name:"nolint" help:"Emit a //nolint:all directive in generated files"`
+
+	IgnoreErrors bool `name:"ignore-errors" help:"In directory mode, report per-file failures and continue with the remaining files"`
+	CDDL         bool `name:"cddl" help:"Emit a CBORSchema() method returning a CDDL description of each struct"`
+	Context      bool `name:"context" help:"Generate DecodeSafe/DecodeTrusted methods that take a context.Context and check it before nested struct decodes"`
+	Verify       bool `name:"verify" help:"Regenerate in memory and fail with a diff if any generated file is out of date"`
+
+	GoGenerate        bool     `name:"go-generate" negatable:"" default:"true" help:"Add a //go:generate directive to generated files that reruns cborgen with the same flags"`
+	KnownGeneratedPkg []string `name:"known-generated-pkg" sep:"none" placeholder:"PATH/TYPE,..." help:"Types in another package that cborgen generated code for, as import/path/Type1,Type2; fields of those types use DecodeTrusted (may be repeated)"`
 }
 
 func main() {
@@ -50,11 +66,21 @@ func run(cli *CLI) error {
 		return fmt.Errorf("stat input: %w", err)
 	}
 
+	known, err := parseKnownGenerated(cli.KnownGeneratedPkg)
+	if err != nil {
+		return err
+	}
+
+	opts := core.Options{Verbose: cli.Verbose, Structs: cli.Structs, NoLint: cli.NoLint, CDDL: cli.CDDL, Context: cli.Context, KnownGenerated: known, NoGoGenerate: !cli.GoGenerate}
+
 	if info.IsDir() {
 		if cli.Output != "" {
 			return errors.New("--output is not allowed when input is a directory")
 		}
-		return runForDir(input, cli.Verbose, cli.Structs)
+		if cli.Verify {
+			return verifyDir(input, opts)
+		}
+		return runForDir(input, opts, cli.IgnoreErrors)
 	}
 
 	// Single-file mode.
@@ -62,12 +88,63 @@ func run(cli *CLI) error {
 	if strings.TrimSpace(out) == "" {
 		out = defaultOutputPath(input)
 	}
-	return generateForFile(input, out, cli.Verbose, cli.Structs)
+	if cli.Verify {
+		return verifyFiles([]string{input}, []string{out}, opts, false)
+	}
+	return generateForFile(input, out, opts)
 }
 
 // runForDir walks a directory tree and generates a companion
-// "*_cbor.go" file for each eligible Go source file.
-func runForDir(dir string, verbose bool, structs []string) error {
+// "*_cbor.go" file for each eligible Go source file. All files are
+// collected before any is generated so that struct types can refer to
+// each other across files regardless of walk order. With ignoreErrors,
+// generation failures are reported on stderr and the remaining files
+// are still generated; the returned error then summarizes how many
+// files failed.
+func runForDir(dir string, opts core.Options, ignoreErrors bool) error {
+	paths, err := sourceFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, pkgPaths := range byDir(paths) {
+		core.Collect(pkgPaths, opts)
+		for i, path := range pkgPaths {
+			outPath := defaultOutputPath(path)
+			if err := generateForFile(path, outPath, dirFileOptions(opts, i)); err != nil {
+				if !ignoreErrors {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "cborgen: %s: %v\n", path, err)
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("generation failed for %d file(s)", failed)
+	}
+	return nil
+}
+
+// dirFileOptions returns the options for the i-th file of a directory
+// in directory mode. Only the first file's go:generate directive is
+// kept, and it regenerates the whole directory so that types can still
+// refer to each other across files.
+func dirFileOptions(opts core.Options, i int) core.Options {
+	if i > 0 {
+		opts.NoGoGenerate = true
+	} else {
+		opts.GoGenerateDir = true
+	}
+	return opts
+}
+
+// sourceFiles returns the Go source files under dir that cborgen
+// generates companions for, skipping tests and generated files.
+func sourceFiles(dir string) ([]string, error) {
+	var paths []string
 	if err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("walk %q: %w", path, err)
@@ -94,17 +171,55 @@ func runForDir(dir string, verbose bool, structs []string) error {
 			return nil
 		}
 
-		outPath := defaultOutputPath(path)
-		if err := generateForFile(path, outPath, verbose, structs); err != nil {
-			return err
-		}
-
+		paths = append(paths, path)
 		return nil
 	}); err != nil {
-		return err
+		return nil, err
+	}
+	return paths, nil
+}
+
+// byDir splits paths into groups sharing a directory, i.e. a package,
+// in order of first appearance, so each package is collected on its own.
+func byDir(paths []string) [][]string {
+	var groups [][]string
+	index := make(map[string]int)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		i, ok := index[dir]
+		if !ok {
+			i = len(groups)
+			index[dir] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], path)
 	}
+	return groups
+}
 
-	return nil
+// parseKnownGenerated turns --known-generated-pkg values of the form
+// "import/path/Type1,Type2" into a map from import path to type names.
+// Repeating a path adds to its type list.
+func parseKnownGenerated(specs []string) (map[string][]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	known := make(map[string][]string, len(specs))
+	for _, spec := range specs {
+		i := strings.LastIndexByte(spec, '/')
+		if i <= 0 || i == len(spec)-1 {
+			return nil, fmt.Errorf("--known-generated-pkg %q: want import/path/Type1,Type2", spec)
+		}
+		importPath := spec[:i]
+		for _, name := range strings.Split(spec[i+1:], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return nil, fmt.Errorf("--known-generated-pkg %q: empty type name", spec)
+			}
+			known[importPath] = append(known[importPath], name)
+		}
+	}
+	return known, nil
 }
 
 // defaultOutputPath derives the "*_cbor.go" filename for
@@ -120,6 +235,6 @@ func defaultOutputPath(inputPath string) string {
 	return filepath.Join(dir, name)
 }
 
-func generateForFile(inputPath, outputPath string, verbose bool, structs []string) error {
-	return core.Run(inputPath, outputPath, core.Options{Verbose: verbose, Structs: structs})
+func generateForFile(inputPath, outputPath string, opts core.Options) error {
+	return core.Run(inputPath, outputPath, opts)
 }
@@ -2,16 +2,45 @@
 Decode case snippets for UnmarshalCBOR switch bodies.
 
 Templates:
-  decodeCaseBasic       - scalar types (string, bool, numbers)
-  decodeCaseBytes       - []byte
-  decodeCaseSliceBasic  - []T for basic scalar T
-  decodeCaseMapStrBasic - map[string]T for basic scalar T
-  decodeCaseSkip        - fallback: skip unknown/unsupported field
+  decodeCaseBasic                 - scalar types (string, bool, numbers)
+  decodeCaseBytes                 - []byte
+  decodeCaseSliceBasic            - []T for basic scalar T
+  decodeCaseMapStrBasic           - map[string]T for basic scalar T
+  decodeCaseMapIntKeyBasic        - map[K]T for narrow integer K and basic scalar T
+  decodeCaseMapIntKeyPtr          - map[K]*T for narrow integer K, value uses UnmarshalCBOR
+  decodeCaseUintCast              - signed T read from a CBOR uint (cbor:",uint")
+  decodeCaseInterfaceUnmarshal    - interface field whose method set has UnmarshalCBOR
+  decodeCaseOneOf                 - interface field holding one of .Variants (cbor:",oneof")
+  decodeCaseNetAddr               - net.IP / net.HardwareAddr as tag 260, or null
+  decodeCaseURLField              - url.URL / *url.URL as a tag 32 URI (null for a nil pointer)
+  decodeCaseRawCBOR               - []byte holding the next raw item, copied (cbor:",rawcbor")
+  decodeCaseRawCBORTrusted        - as above, aliasing the input
+  decodeCaseRef                   - *T through the shared reference table (cbor:",ref")
+  decodeCaseRefSlice              - []*T through the shared reference table (cbor:",ref")
+  decodeCaseRefsField             - T or *T generated with a reference table, passed the caller's
+  decodeCasePtrDuration           - *time.Duration, or null for nil
+  decodeCaseDurationString        - time.Duration read from a string (cbor:",string")
+  decodeCaseDurationStringTrusted - as above, parsing a zero-copy string
+  decodeCaseIntString             - integer read from its base-10 text form (cbor:",string")
+  decodeCaseIntStringTrusted      - as above, parsing a zero-copy string
+  decodeCaseNullable              - null as the zero value, otherwise .Body (cbor:",null")
+  decodeCaseSkip                  - fallback: skip unknown/unsupported field
 
 Inputs:
-  .Field    - Go field name on receiver (exported)
-  .VarType  - Go type for temporary (e.g. "int64")
-  .ReadFunc - runtime ReadXxxBytes function to call
+  .Field       - Go field name on receiver (exported)
+  .VarType     - Go type for temporary (e.g. "int64")
+  .ReadFunc    - runtime ReadXxxBytes function to call
+  .MaxValue    - upper bound constant for range-checked casts
+  .Bits        - bit size reported in overflow errors
+  .KeyType     - Go type of integer map keys (e.g. "uint32")
+  .KeyReadFunc - bounds-checked runtime ReadXxxBytes function for map keys
+  .Signed      - parse text integers with strconv.ParseInt, not ParseUint
+  .Context     - pass the decoder's ctx to nested decodes (--context)
+  .Body        - decode snippet wrapped by decodeCaseNullable
+  .Pointer     - decodeCaseURLField stores a *url.URL
+  .Variants    - oneof types and the map keys that identify each
+  .Trusted     - decode oneof variants, or decodeCaseRefsField, with DecodeTrusted
+  .Lock        - struct slice elements hold a sync lock; decode them in place
 */}}
 
 {{define "decodeCaseBasic"}}
@@ -21,6 +50,14 @@ Inputs:
 		x.{{.Field}} = tmp
 {{end}}
 
+{{define "decodeCaseUintCast"}}
+		var tmp uint64
+		tmp, v, err = {{rt "ReadUint64Bytes"}}(v)
+		if err != nil { return b, err }
+		if tmp > {{.MaxValue}} { return b, {{rt "UintOverflow"}}{Value: tmp, FailedBitsize: {{.Bits}}} }
+		x.{{.Field}} = {{.VarType}}(tmp)
+{{end}}
+
 {{define "decodeCaseBytes"}}
 		var tmp []byte
 		tmp, v, err = {{rt "ReadBytesBytes"}}(v, nil)
@@ -68,6 +105,56 @@ Inputs:
 		}
 {{end}}
 
+{{define "decodeCaseMapIntKeyBasic"}}
+		var sz uint32
+		sz, v, err = {{rt "ReadMapHeaderBytes"}}(v)
+		if err != nil { return b, err }
+		if x.{{.Field}} == nil && sz > 0 {
+			x.{{.Field}} = make(map[{{.KeyType}}]{{.VarType}}, sz)
+		} else if x.{{.Field}} != nil {
+			clear(x.{{.Field}})
+		}
+		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
+			var key {{.KeyType}}
+			key, v, err = {{.KeyReadFunc}}(v)
+			if err != nil { return b, err }
+			var tmp {{.VarType}}
+			tmp, v, err = {{.ReadFunc}}(v)
+			if err != nil { return b, err }
+			x.{{.Field}}[key] = tmp
+		}
+{{end}}
+
+{{define "decodeCaseMapIntKeyPtr"}}
+		var sz uint32
+		sz, v, err = {{rt "ReadMapHeaderBytes"}}(v)
+		if err != nil { return b, err }
+		if x.{{.Field}} == nil && sz > 0 {
+			x.{{.Field}} = make(map[{{.KeyType}}]*{{.VarType}}, sz)
+		} else if x.{{.Field}} != nil {
+			clear(x.{{.Field}})
+		}
+		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
+			var key {{.KeyType}}
+			key, v, err = {{.KeyReadFunc}}(v)
+			if err != nil { return b, err }
+			if {{rt "IsNil"}}(v) {
+				v, err = {{rt "ReadNilBytes"}}(v)
+				if err != nil { return b, err }
+				x.{{.Field}}[key] = nil
+				continue
+			}
+			tmp := new({{.VarType}})
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, tmp, v)
+			{{- else }}
+			v, err = tmp.UnmarshalCBOR(v)
+			{{- end }}
+			if err != nil { return b, err }
+			x.{{.Field}}[key] = tmp
+		}
+{{end}}
+
 {{define "decodeCaseMapUint64Ptr"}}
 		var sz uint32
 		sz, v, err = {{rt "ReadMapHeaderBytes"}}(v)
@@ -91,7 +178,11 @@ Inputs:
 				continue
 			}
 			tmp := new({{.VarType}})
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, tmp, v)
+			{{- else }}
 			v, err = tmp.UnmarshalCBOR(v)
+			{{- end }}
 			if err != nil { return b, err }
 			x.{{.Field}}[key] = tmp
 		}
@@ -126,15 +217,31 @@ Inputs:
 		} else {
 			x.{{.Field}} = make([]{{.VarType}}, sz)
 		}
+		{{- if .Lock }}
+		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
+			x.{{.Field}}[i{{.Field}}] = {{.VarType}}{}
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, &x.{{.Field}}[i{{.Field}}], v)
+			{{- else }}
+			v, err = x.{{.Field}}[i{{.Field}}].UnmarshalCBOR(v)
+			{{- end }}
+			if err != nil { return b, err }
+		}
+		{{- else }}
 		if sz > 0 {
 			_ = x.{{.Field}}[sz-1]
 		}
 		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
 			var tmp {{.VarType}}
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, &tmp, v)
+			{{- else }}
 			v, err = (&tmp).UnmarshalCBOR(v)
+			{{- end }}
 			if err != nil { return b, err }
 			x.{{.Field}}[i{{.Field}}] = tmp
 		}
+		{{- end }}
 {{end}}
 
 {{define "decodeCaseSliceStructTrusted"}}
@@ -146,15 +253,23 @@ Inputs:
 		} else {
 			x.{{.Field}} = make([]{{.VarType}}, sz)
 		}
+		{{- if .Lock }}
+		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
+			x.{{.Field}}[i{{.Field}}] = {{.VarType}}{}
+			v, err = x.{{.Field}}[i{{.Field}}].DecodeTrusted({{if .Context}}ctx, {{end}}v)
+			if err != nil { return b, err }
+		}
+		{{- else }}
 		if sz > 0 {
 			_ = x.{{.Field}}[sz-1]
 		}
 		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
 			var tmp {{.VarType}}
-			v, err = (&tmp).DecodeTrusted(v)
+			v, err = (&tmp).DecodeTrusted({{if .Context}}ctx, {{end}}v)
 			if err != nil { return b, err }
 			x.{{.Field}}[i{{.Field}}] = tmp
 		}
+		{{- end }}
 {{end}}
 
 {{define "decodeCaseSlicePtrStruct"}}
@@ -171,7 +286,11 @@ Inputs:
 		}
 		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
 			if x.{{.Field}}[i{{.Field}}] == nil { x.{{.Field}}[i{{.Field}}] = new({{.VarType}}) }
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, x.{{.Field}}[i{{.Field}}], v)
+			{{- else }}
 			v, err = x.{{.Field}}[i{{.Field}}].UnmarshalCBOR(v)
+			{{- end }}
 			if err != nil { return b, err }
 		}
 {{end}}
@@ -190,7 +309,7 @@ Inputs:
 		}
 		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
 			if x.{{.Field}}[i{{.Field}}] == nil { x.{{.Field}}[i{{.Field}}] = new({{.VarType}}) }
-			v, err = x.{{.Field}}[i{{.Field}}].DecodeTrusted(v)
+			v, err = x.{{.Field}}[i{{.Field}}].DecodeTrusted({{if .Context}}ctx, {{end}}v)
 			if err != nil { return b, err }
 		}
 {{end}}
@@ -209,7 +328,11 @@ Inputs:
 			key, v, err = {{rt "ReadStringBytes"}}(v)
 			if err != nil { return b, err }
 			var tmp {{.VarType}}
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, &tmp, v)
+			{{- else }}
 			v, err = (&tmp).UnmarshalCBOR(v)
+			{{- end }}
 			if err != nil { return b, err }
 			x.{{.Field}}[key] = tmp
 		}
@@ -229,7 +352,7 @@ Inputs:
 			key, v, err = {{rt "ReadStringBytes"}}(v)
 			if err != nil { return b, err }
 			var tmp {{.VarType}}
-			v, err = (&tmp).DecodeTrusted(v)
+			v, err = (&tmp).DecodeTrusted({{if .Context}}ctx, {{end}}v)
 			if err != nil { return b, err }
 			x.{{.Field}}[key] = tmp
 		}
@@ -249,7 +372,11 @@ Inputs:
 			key, v, err = {{rt "ReadStringBytes"}}(v)
 			if err != nil { return b, err }
 			tmp := new({{.VarType}})
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, tmp, v)
+			{{- else }}
 			v, err = tmp.UnmarshalCBOR(v)
+			{{- end }}
 			if err != nil { return b, err }
 			x.{{.Field}}[key] = tmp
 		}
@@ -269,31 +396,39 @@ Inputs:
 			key, v, err = {{rt "ReadStringBytes"}}(v)
 			if err != nil { return b, err }
 			tmp := new({{.VarType}})
-			v, err = tmp.DecodeTrusted(v)
+			v, err = tmp.DecodeTrusted({{if .Context}}ctx, {{end}}v)
 			if err != nil { return b, err }
 			x.{{.Field}}[key] = tmp
 		}
 {{end}}
 
 {{define "decodeCaseUnmarshalField"}}
+		{{- if .Context }}
+		v, err = {{rt "UnmarshalContext"}}(ctx, &x.{{.Field}}, v)
+		{{- else }}
 		v, err = x.{{.Field}}.UnmarshalCBOR(v)
+		{{- end }}
 		if err != nil { return b, err }
 {{end}}
 
 {{define "decodeCasePtrUnmarshalField"}}
 		if x.{{.Field}} == nil { x.{{.Field}} = new({{.VarType}}) }
+		{{- if .Context }}
+		v, err = {{rt "UnmarshalContext"}}(ctx, x.{{.Field}}, v)
+		{{- else }}
 		v, err = x.{{.Field}}.UnmarshalCBOR(v)
+		{{- end }}
 		if err != nil { return b, err }
 {{end}}
 
 {{define "decodeCaseTrustedField"}}
-		v, err = (&x.{{.Field}}).DecodeTrusted(v)
+		v, err = (&x.{{.Field}}).DecodeTrusted({{if .Context}}ctx, {{end}}v)
 		if err != nil { return b, err }
 {{end}}
 
 {{define "decodeCasePtrTrustedField"}}
 		if x.{{.Field}} == nil { x.{{.Field}} = new({{.VarType}}) }
-		v, err = x.{{.Field}}.DecodeTrusted(v)
+		v, err = x.{{.Field}}.DecodeTrusted({{if .Context}}ctx, {{end}}v)
 		if err != nil { return b, err }
 {{end}}
 
@@ -305,6 +440,8 @@ static type:
 
   - decodeCaseMapUint64PtrTrusted    : map[uint64]*T, value uses DecodeTrusted
   - decodeCaseMapUint64Uint64Trusted : map[uint64]uint64
+  - decodeCaseMapIntKeyPtrTrusted    : map[K]*T for narrow integer K,
+                                       value uses DecodeTrusted
 
 They intentionally avoid per-entry map clearing to keep the Trusted
 path as lean as possible for hot JetStream fields like Pending and
@@ -332,7 +469,7 @@ Redelivered.
 				continue
 			}
 			val := new({{.VarType}})
-			v, err = val.DecodeTrusted(v)
+			v, err = val.DecodeTrusted({{if .Context}}ctx, {{end}}v)
 			if err != nil { return b, err }
 			x.{{.Field}}[key] = val
 		}
@@ -356,6 +493,221 @@ Redelivered.
 		}
 {{end}}
 
+{{define "decodeCaseMapIntKeyPtrTrusted"}}
+		var sz uint32
+		sz, v, err = {{rt "ReadMapHeaderBytes"}}(v)
+		if err != nil { return b, err }
+		if x.{{.Field}} == nil && sz > 0 {
+			x.{{.Field}} = make(map[{{.KeyType}}]*{{.VarType}}, sz)
+		} else if x.{{.Field}} != nil {
+			clear(x.{{.Field}})
+		}
+		for i{{.Field}} := uint32(0); i{{.Field}} < sz; i{{.Field}}++ {
+			var key {{.KeyType}}
+			key, v, err = {{.KeyReadFunc}}(v)
+			if err != nil { return b, err }
+			if {{rt "IsNil"}}(v) {
+				v, err = {{rt "ReadNilBytes"}}(v)
+				if err != nil { return b, err }
+				x.{{.Field}}[key] = nil
+				continue
+			}
+			val := new({{.VarType}})
+			v, err = val.DecodeTrusted({{if .Context}}ctx, {{end}}v)
+			if err != nil { return b, err }
+			x.{{.Field}}[key] = val
+		}
+{{end}}
+
+{{define "decodeCaseInterfaceUnmarshal"}}
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+			v, err = {{rt "ReadNilBytes"}}(v)
+			if err != nil { return b, err }
+		} else {
+			if x.{{.Field}} == nil { return b, {{rt "ErrNilUnmarshaler"}} }
+			{{- if .Context }}
+			v, err = {{rt "UnmarshalContext"}}(ctx, x.{{.Field}}, v)
+			{{- else }}
+			v, err = x.{{.Field}}.UnmarshalCBOR(v)
+			{{- end }}
+			if err != nil { return b, err }
+		}
+{{end}}
+
+{{define "decodeCaseOneOf"}}
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+			v, err = {{rt "ReadNilBytes"}}(v)
+			if err != nil { return b, err }
+		} else {
+			var which int
+			which, err = {{rt "MatchMapKeys"}}(v{{range .Variants}}, []string{ {{- range $i, $k := .Keys}}{{if $i}}, {{end}}{{printf "%q" $k}}{{end -}} }{{end}})
+			if err != nil { return b, err }
+			switch which {
+{{- range $i, $vt := .Variants }}
+			case {{$i}}:
+				tmp := new({{$vt.Type}})
+				{{- if $.Trusted }}
+				v, err = tmp.DecodeTrusted({{if $.Context}}ctx, {{end}}v)
+				{{- else if $.Context }}
+				v, err = {{rt "UnmarshalContext"}}(ctx, tmp, v)
+				{{- else }}
+				v, err = tmp.UnmarshalCBOR(v)
+				{{- end }}
+				if err != nil { return b, err }
+				x.{{$.Field}} = tmp
+{{- end }}
+			default:
+				return b, {{rt "ErrUnknownVariant"}}
+			}
+		}
+{{end}}
+
+{{define "decodeCaseNetAddr"}}
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+			v, err = {{rt "ReadNilBytes"}}(v)
+			if err != nil { return b, err }
+		} else {
+			var tmp {{.VarType}}
+			tmp, v, err = {{.ReadFunc}}(v)
+			if err != nil { return b, err }
+			x.{{.Field}} = tmp
+		}
+{{end}}
+
+{{define "decodeCaseURLField"}}
+{{- if .Pointer }}
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+			v, err = {{rt "ReadNilBytes"}}(v)
+			if err != nil { return b, err }
+		} else {
+{{- end }}
+			var s string
+			s, v, err = {{rt "ReadURIStringBytes"}}(v)
+			if err != nil { return b, err }
+			var u *url.URL
+			u, err = url.Parse(s)
+			if err != nil { return b, err }
+			x.{{.Field}} = {{if not .Pointer}}*{{end}}u
+{{- if .Pointer }}
+		}
+{{- end }}
+{{end}}
+
+{{define "decodeCaseRawCBOR"}}
+		var next []byte
+		next, err = {{rt "Skip"}}(v)
+		if err != nil { return b, err }
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+		} else {
+			x.{{.Field}} = append(x.{{.Field}}[:0], v[:len(v)-len(next)]...)
+		}
+		v = next
+{{end}}
+
+{{define "decodeCaseRawCBORTrusted"}}
+		var next []byte
+		next, err = {{rt "Skip"}}(v)
+		if err != nil { return b, err }
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+		} else {
+			x.{{.Field}} = v[:len(v)-len(next):len(v)-len(next)]
+		}
+		v = next
+{{end}}
+
+{{define "decodeCaseRef"}}
+		x.{{.Field}}, v, err = {{rt "ReadRef"}}[{{.VarType}}](v, refs)
+		if err != nil { return b, err }
+{{end}}
+
+{{define "decodeCaseRefSlice"}}
+		x.{{.Field}}, v, err = {{rt "ReadRefSlice"}}[{{.VarType}}](v, refs, x.{{.Field}})
+		if err != nil { return b, err }
+{{end}}
+
+{{define "decodeCaseRefsField"}}
+		{{- if .Pointer }}
+		if x.{{.Field}} == nil { x.{{.Field}} = new({{.VarType}}) }
+		v, err = x.{{.Field}}.{{if .Trusted}}DecodeTrustedRefs{{else}}DecodeSafeRefs{{end}}({{if .Context}}ctx, {{end}}v, refs)
+		{{- else }}
+		v, err = (&x.{{.Field}}).{{if .Trusted}}DecodeTrustedRefs{{else}}DecodeSafeRefs{{end}}({{if .Context}}ctx, {{end}}v, refs)
+		{{- end }}
+		if err != nil { return b, err }
+{{end}}
+
+{{define "decodeCasePtrDuration"}}
+		if {{rt "IsNil"}}(v) {
+			x.{{.Field}} = nil
+			v = v[1:]
+		} else {
+			if x.{{.Field}} == nil { x.{{.Field}} = new(time.Duration) }
+			*x.{{.Field}}, v, err = {{rt "ReadDurationBytes"}}(v)
+			if err != nil { return b, err }
+		}
+{{end}}
+
+{{define "decodeCaseDurationString"}}
+		var tmp string
+		tmp, v, err = {{rt "ReadStringBytes"}}(v)
+		if err != nil { return b, err }
+		x.{{.Field}}, err = time.ParseDuration(tmp)
+		if err != nil { return b, err }
+{{end}}
+
+{{define "decodeCaseDurationStringTrusted"}}
+		var tmpBytes []byte
+		tmpBytes, v, err = {{rt "ReadStringZC"}}(v)
+		if err != nil { return b, err }
+		x.{{.Field}}, err = time.ParseDuration({{rt "UnsafeString"}}(tmpBytes))
+		if err != nil { return b, err }
+{{end}}
+
+{{define "decodeCaseIntString"}}
+		var tmp string
+		tmp, v, err = {{rt "ReadStringBytes"}}(v)
+		if err != nil { return b, err }
+	{{- if .Signed }}
+		var n int64
+		n, err = strconv.ParseInt(tmp, 10, {{.Bits}})
+	{{- else }}
+		var n uint64
+		n, err = strconv.ParseUint(tmp, 10, {{.Bits}})
+	{{- end }}
+		if err != nil { return b, err }
+		x.{{.Field}} = {{.VarType}}(n)
+{{end}}
+
+{{define "decodeCaseIntStringTrusted"}}
+		var tmpBytes []byte
+		tmpBytes, v, err = {{rt "ReadStringZC"}}(v)
+		if err != nil { return b, err }
+	{{- if .Signed }}
+		var n int64
+		n, err = strconv.ParseInt({{rt "UnsafeString"}}(tmpBytes), 10, {{.Bits}})
+	{{- else }}
+		var n uint64
+		n, err = strconv.ParseUint({{rt "UnsafeString"}}(tmpBytes), 10, {{.Bits}})
+	{{- end }}
+		if err != nil { return b, err }
+		x.{{.Field}} = {{.VarType}}(n)
+{{end}}
+
+{{define "decodeCaseNullable"}}
+		if {{rt "IsNil"}}(v) {
+			var zero {{.VarType}}
+			x.{{.Field}} = zero
+			v = v[1:]
+		} else {
+		{{.Body}}
+		}
+{{end}}
+
 {{define "decodeCaseSkip"}}
 		v, err = {{rt "Skip"}}(v)
 		if err != nil { return b, err }
@@ -5,26 +5,40 @@ inline loops). These are shape-based (map/slice element types), not
 per-field, mirroring msgp-style codegen.
 
 Templates:
-  encodeMapUint64PtrMarshaler - map[uint64]*T where *T has MarshalCBOR
-  encodeMapUint64Uint64       - map[uint64]uint64
-  encodeMapStrStr             - map[string]string
-  encodeMapStrValueMarshaler  - map[string]T where T has MarshalCBOR
-  encodeMapStrPtrMarshaler    - map[string]*T where *T has MarshalCBOR
-  encodeMapStrScalar          - map[string]S where S is a scalar
-  encodeSlicePtrMarshaler     - []*T where *T has MarshalCBOR
-  encodeSliceValueMarshaler   - []T where T has MarshalCBOR
-  encodeSliceScalar           - []S where S is a scalar (bool/int/float/string)
+  encodeMapUint64PtrMarshaler   - map[uint64]*T where *T has MarshalCBOR
+  encodeMapUint64Uint64         - map[uint64]uint64
+  encodeMapIntKeyScalar         - map[K]S for narrow integer K and scalar S
+  encodeMapIntKeyPtrMarshaler   - map[K]*T for narrow integer K where *T has MarshalCBOR
+  encodeMapStrStr               - map[string]string
+  encodeMapStrValueMarshaler    - map[string]T where T has MarshalCBOR
+  encodeMapStrPtrMarshaler      - map[string]*T where *T has MarshalCBOR
+  encodeMapStrScalar            - map[string]S where S is a scalar
+  encodeSlicePtrMarshaler       - []*T where *T has MarshalCBOR
+  encodeSliceValueMarshaler     - []T where T has MarshalCBOR
+  encodeSliceInterfaceMarshaler - []I for an interface I with MarshalCBOR, null for nil elements
+  encodeSliceScalar             - []S where S is a scalar (bool/int/float/string)
+  encodeInterfaceMarshaler      - interface field whose method set has MarshalCBOR
+  encodeOneOf                   - interface field holding one of .Variants (cbor:",oneof")
+  encodePtrDuration             - *time.Duration, or null when nil
+  encodeNullable                - .Body when .Cond holds, otherwise null (cbor:",null")
+  encodeUnsigned                - signed integer as a CBOR uint, failing if negative (cbor:",unsigned")
 
 Inputs:
-  .FieldRef   - "x.F" reference to the Go field
-  .KeyName    - CBOR map/array key name
-  .GoField    - Go field name (for variable suffixes)
-  .ElemVar    - Loop variable name used for slice elements
-  .AppendFunc - Append* helper name for scalar slices
+  .FieldRef      - "x.F" reference to the Go field
+  .KeyName       - CBOR map key name, or empty for positional (array) encoding
+  .GoField       - Go field name (for variable suffixes)
+  .ElemVar       - Loop variable name used for slice elements
+  .AppendFunc    - Append* helper name for scalar slices
+  .KeyAppendFunc - Append* helper name for integer map keys
+  .Cond          - non-zero check guarding .Body
+  .Body          - value encoder wrapped by encodeNullable
+  .Variants      - struct types switched over by encodeOneOf
 */}}
 
 {{define "encodeMapUint64PtrMarshaler"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
 	for k, v := range {{.FieldRef}} {
 		b = {{rt "AppendUint64"}}(b, k)
@@ -38,7 +52,9 @@ Inputs:
 {{end}}
 
 {{define "encodeMapUint64Uint64"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
 	for k, v := range {{.FieldRef}} {
 		b = {{rt "AppendUint64"}}(b, k)
@@ -46,8 +62,37 @@ Inputs:
 	}
 {{end}}
 
+{{define "encodeMapIntKeyScalar"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
+	for k, v := range {{.FieldRef}} {
+		b = {{.KeyAppendFunc}}(b, k)
+		b = {{.AppendFunc}}(b, v)
+	}
+{{end}}
+
+{{define "encodeMapIntKeyPtrMarshaler"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
+	for k, v := range {{.FieldRef}} {
+		b = {{.KeyAppendFunc}}(b, k)
+		if v == nil {
+			b = {{rt "AppendNil"}}(b)
+		} else {
+			b, err = v.MarshalCBOR(b)
+			if err != nil { return b, err }
+		}
+	}
+{{end}}
+
 {{define "encodeMapStrStr"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
 	for k, v := range {{.FieldRef}} {
 		b = {{rt "AppendString"}}(b, k)
@@ -56,7 +101,9 @@ Inputs:
 {{end}}
 
 {{define "encodeMapStrValueMarshaler"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
 	for k, v := range {{.FieldRef}} {
 		b = {{rt "AppendString"}}(b, k)
@@ -66,7 +113,9 @@ Inputs:
 {{end}}
 
 {{define "encodeMapStrPtrMarshaler"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
 	for k, v := range {{.FieldRef}} {
 		b = {{rt "AppendString"}}(b, k)
@@ -80,7 +129,9 @@ Inputs:
 {{end}}
 
 {{define "encodeMapStrScalar"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendMapHeader"}}(b, uint32(len({{.FieldRef}})))
 	for k, v := range {{.FieldRef}} {
 		b = {{rt "AppendString"}}(b, k)
@@ -89,7 +140,9 @@ Inputs:
 {{end}}
 
 {{define "encodeSlicePtrMarshaler"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendArrayHeader"}}(b, uint32(len({{.FieldRef}})))
 	for _, {{.ElemVar}} := range {{.FieldRef}} {
 		if {{.ElemVar}} == nil {
@@ -102,7 +155,9 @@ Inputs:
 {{end}}
 
 {{define "encodeSliceValueMarshaler"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendArrayHeader"}}(b, uint32(len({{.FieldRef}})))
 	for i := range {{.FieldRef}} {
 		b, err = {{.FieldRef}}[i].MarshalCBOR(b)
@@ -110,10 +165,88 @@ Inputs:
 	}
 {{end}}
 
+{{define "encodeSliceInterfaceMarshaler"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	b = {{rt "AppendArrayHeader"}}(b, uint32(len({{.FieldRef}})))
+	for _, v := range {{.FieldRef}} {
+		if v == nil {
+			b = {{rt "AppendNil"}}(b)
+			continue
+		}
+		b, err = v.MarshalCBOR(b)
+		if err != nil { return b, err }
+	}
+{{end}}
+
 {{define "encodeSliceScalar"}}
+{{- if .KeyName }}
 	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
 	b = {{rt "AppendArrayHeader"}}(b, uint32(len({{.FieldRef}})))
 	for _, v := range {{.FieldRef}} {
 		b = {{.AppendFunc}}(b, v)
 	}
 {{end}}
+
+{{define "encodeInterfaceMarshaler"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	if {{.FieldRef}} == nil {
+		b = {{rt "AppendNil"}}(b)
+	} else {
+		b, err = {{.FieldRef}}.MarshalCBOR(b)
+		if err != nil { return b, err }
+	}
+{{end}}
+
+{{define "encodeOneOf"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	switch v := {{.FieldRef}}.(type) {
+	case nil:
+		b = {{rt "AppendNil"}}(b)
+{{- range .Variants }}
+	case *{{.}}:
+		b, err = v.MarshalCBOR(b)
+	case {{.}}:
+		b, err = v.MarshalCBOR(b)
+{{- end }}
+	default:
+		err = {{rt "ErrUnknownVariant"}}
+	}
+	if err != nil { return b, err }
+{{end}}
+
+{{define "encodePtrDuration"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	if {{.FieldRef}} == nil {
+		b = {{rt "AppendNil"}}(b)
+	} else {
+		b = {{rt "AppendDuration"}}(b, *{{.FieldRef}})
+	}
+{{end}}
+
+{{define "encodeNullable"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	if {{.Cond}} {
+	{{.Body}}
+	} else {
+		b = {{rt "AppendNil"}}(b)
+	}
+{{end}}
+
+{{define "encodeUnsigned"}}
+{{- if .KeyName }}
+	b = {{rt "AppendString"}}(b, "{{.KeyName}}")
+{{- end }}
+	if {{.FieldRef}} < 0 { return b, {{rt "ErrNegativeUnsigned"}} }
+	b = {{rt "AppendUint64"}}(b, uint64({{.FieldRef}}))
+{{end}}
@@ -1,31 +1,115 @@
 // Code generated by cborgen DO NOT EDIT.
 
+{{if .GoGenerate}}//go:generate {{.GoGenerate}}
+
+{{end -}}
+{{if .NoLint}}//nolint:all
+{{end -}}
 package {{.Package}}
 
 import cbor "github.com/synadia-labs/cbor.go/runtime"
 
 {{range .Structs}}
-{{if .MsgSizeExpr}}
-func (x {{.Name}}) Msgsize() (s int) {
-	s = {{.MsgSizeExpr}}
+{{- $readOnly := .ReadOnly }}
+{{- $writeOnly := .WriteOnly }}
+{{if .MsgSizeParts}}
+func (x {{if .HasLock}}*{{end}}{{.Name}}) Msgsize() (s int) {
+	s = {{rt "MapHeaderSize"}}
+{{- range .MsgSizeParts }}
+	s = {{rt "AddSize"}}(s, {{.}})
+{{- end }}
 	return
 }
 {{end}}
 
+{{- if not .ReadOnly }}
+{{- if .Versioned }}
+// MarshalCBOR encodes every field of {{.Name}}, as EncodeVersion does
+// for the latest schema version.
+func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.EncodeVersion(b, math.MaxInt)
+}
+
+// EncodeVersion encodes {{.Name}} for readers of schema version v,
+// leaving out fields tagged with a later cbor:",version=N".
+func (x *{{.Name}}) EncodeVersion(b []byte, v int) ([]byte, error) {
+{{- if .HasRefs }}
+	return x.EncodeVersionRefs(b, v, new({{rt "EncodeRefs"}}))
+}
+
+// MarshalCBORRefs implements cbor.RefMarshaler.
+func (x *{{.Name}}) MarshalCBORRefs(b []byte, refs *{{rt "EncodeRefs"}}) ([]byte, error) {
+	return x.EncodeVersionRefs(b, math.MaxInt, refs)
+}
+
+// EncodeVersionRefs is EncodeVersion sharing references through refs,
+// the table of the enclosing document.
+func (x *{{.Name}}) EncodeVersionRefs(b []byte, v int, refs *{{rt "EncodeRefs"}}) ([]byte, error) {
+{{- end }}
+{{- else if .HasRefs }}
+// MarshalCBOR encodes {{.Name}} as a document of its own, with a fresh
+// reference table.
+func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.MarshalCBORRefs(b, new({{rt "EncodeRefs"}}))
+}
+
+// MarshalCBORRefs implements cbor.RefMarshaler.
+func (x *{{.Name}}) MarshalCBORRefs(b []byte, refs *{{rt "EncodeRefs"}}) ([]byte, error) {
+{{- else }}
 func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
+{{- end }}
 	if x == nil {
 		return {{rt "AppendNil"}}(b), nil
 	}
-{{if .MsgSizeExpr}}
+{{if .MsgSizeParts}}
 	b = {{rt "Require"}}(b, x.Msgsize())
 {{end}}
-{{if $.UseOmit}}
-	{{- if .HasOmit }}
+{{- if .Fallthrough }}
+	overflowN, overflow, overflowErr := {{rt "MapEntriesBytes"}}(x.{{.Fallthrough}})
+	if overflowErr != nil {
+		return b, overflowErr
+	}
+{{- end }}
+{{if .ToArray}}
+	b = {{rt "AppendArrayHeader"}}(b, {{len .Fields}})
+	{{- if .EncodeNeedsErr }}
+	var err error
+	{{- end }}
+{{- range .Fields }}
+{{- if .Deprecated }}
+	// Deprecated: {{.Deprecated}}
+{{- end }}
+	{{- if .EncodeBlock }}
+	{{.EncodeBlock}}
+	{{- else if .EncodeExpr }}
+		{{- if .EncodeExprReturnsError }}
+	b, err = {{.EncodeExpr}}
+	if err != nil { return b, err }
+		{{- else }}
+	b = {{.EncodeExpr}}
+		{{- end }}
+	{{- else }}
+	b, err = {{rt "AppendInterface"}}(b, x.{{.GoName}})
+	if err != nil { return b, err }
+	{{- end }}
+{{- end }}
+{{else if $.UseOmit}}
+	{{- if or .HasOmit .Flatten .Fallthrough }}
 	count := uint32({{.NonOmitCount}})
 {{- range .Fields -}}
 {{- if .OmitEmpty }}
 	if {{.OmitEmptyCond}} { count++ }
 {{- end }}
+{{- end }}
+{{- range .Flatten }}
+	{
+		n, err := {{rt "CountMapPairs"}}(x.{{.}})
+		if err != nil { return b, err }
+		count += n
+	}
+{{- end }}
+{{- if .Fallthrough }}
+	count += overflowN
 {{- end }}
 	b = {{rt "AppendMapHeader"}}(b, count)
 	{{- else }}
@@ -35,12 +119,18 @@ func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
 	var err error
 	{{- end }}
 {{- range .Fields }}
+{{- if .Deprecated }}
+	// Deprecated: {{.Deprecated}}
+{{- end }}
 {{- if .OmitEmpty }}
 	if {{.OmitEmptyCond}} {
 		{{- if .EncodeBlock }}
+			{{- if .IntKey }}
+		b = {{.KeyExpr}}
+			{{- end }}
 		{{.EncodeBlock}}
 		{{- else }}
-		b = {{rt "AppendString"}}(b, "{{.CBORName}}")
+		b = {{.KeyExpr}}
 			{{- if .EncodeExpr }}
 				{{- if .EncodeExprReturnsError }}
 		b, err = {{.EncodeExpr}}
@@ -56,9 +146,12 @@ func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
 	}
 {{- else }}
 	{{- if .EncodeBlock }}
+		{{- if .IntKey }}
+	b = {{.KeyExpr}}
+		{{- end }}
 	{{.EncodeBlock}}
 	{{- else }}
-	b = {{rt "AppendString"}}(b, "{{.CBORName}}")
+	b = {{.KeyExpr}}
 		{{- if .EncodeExpr }}
 			{{- if .EncodeExprReturnsError }}
 	b, err = {{.EncodeExpr}}
@@ -74,15 +167,36 @@ func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
 {{- end }}
 {{- end }}
 {{else}}
+	{{- if or .Flatten .Fallthrough }}
+	count := uint32({{len .Fields}})
+{{- range .Flatten }}
+	{
+		n, err := {{rt "CountMapPairs"}}(x.{{.}})
+		if err != nil { return b, err }
+		count += n
+	}
+{{- end }}
+{{- if .Fallthrough }}
+	count += overflowN
+{{- end }}
+	b = {{rt "AppendMapHeader"}}(b, count)
+	{{- else }}
 	b = {{rt "AppendMapHeader"}}(b, {{len .Fields}})
+	{{- end }}
 	{{- if .EncodeNeedsErr }}
 	var err error
 	{{- end }}
 {{- range .Fields }}
+{{- if .Deprecated }}
+	// Deprecated: {{.Deprecated}}
+{{- end }}
 	{{- if .EncodeBlock }}
+		{{- if .IntKey }}
+	b = {{.KeyExpr}}
+		{{- end }}
 	{{.EncodeBlock}}
 	{{- else }}
-	b = {{rt "AppendString"}}(b, "{{.CBORName}}")
+	b = {{.KeyExpr}}
 		{{- if .EncodeExpr }}
 			{{- if .EncodeExprReturnsError }}
 	b, err = {{.EncodeExpr}}
@@ -97,72 +211,286 @@ func (x *{{.Name}}) MarshalCBOR(b []byte) ([]byte, error) {
 	{{- end }}
 {{- end }}
 {{end}}
+{{- range .Flatten }}
+	b = append(b, x.{{.}}...)
+{{- end }}
+{{- if .Fallthrough }}
+	b = append(b, overflow...)
+{{- end }}
 	return b, nil
 }
+{{- end }}
+{{- if not .WriteOnly }}
 
+{{- if .HasRefs }}
 // DecodeSafe decodes using validated, allocating string handling.
-func (x *{{.Name}}) DecodeSafe(b []byte) ([]byte, error) {
+func (x *{{.Name}}) DecodeSafe({{if $.Context}}ctx context.Context, {{end}}b []byte) ([]byte, error) {
+	return x.DecodeSafeRefs({{if $.Context}}ctx, {{end}}b, new({{rt "DecodeRefs"}}))
+}
+
+// DecodeSafeRefs is DecodeSafe resolving shared references through
+// refs, the table of the enclosing document.
+func (x *{{.Name}}) DecodeSafeRefs({{if $.Context}}ctx context.Context, {{end}}b []byte, refs *{{rt "DecodeRefs"}}) ([]byte, error) {
+{{- else }}
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *{{.Name}}) DecodeSafe({{if $.Context}}ctx context.Context, {{end}}b []byte) ([]byte, error) {
+{{- end }}
 	if x == nil {
 		return b, {{rt "ErrNotNil"}}
 	}
+{{- if $.Context }}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+{{- end }}
+{{- if .ToArray }}
+	if {{rt "NextType"}}(b) == {{rt "ArrayType"}} {
+		sz, rest, err := {{rt "ReadArrayHeaderBytes"}}(b)
+		if err != nil {
+			return b, err
+		}
+		for i := uint32(0); i < sz; i++ {
+			v := rest
+			switch i {
+{{- range $i, $f := .Fields }}
+{{- if $f.Deprecated }}
+			// Deprecated: {{$f.Deprecated}}
+{{- end }}
+			case {{$i}}:
+				{{$f.DecodeCaseSafe}}
+{{- end }}
+			default:
+				v, err = {{rt "Skip"}}(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+		}
+		return rest, nil
+	}
+{{- end }}
 	sz, rest, err := {{rt "ReadMapHeaderBytes"}}(b)
 	if err != nil {
 		return b, err
 	}
+{{- if .Fallthrough }}
+	var overflow []byte
+	var overflowN uint32
+{{- end }}
 	for i := uint32(0); i < sz; i++ {
+{{- if .IntKeys }}
+		key, v, err := {{rt "ReadUint64Bytes"}}(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+{{- range .Fields }}
+{{- if .Deprecated }}
+		// Deprecated: {{.Deprecated}}
+{{- end }}
+		case {{.IntKey}}:
+			{{.DecodeCaseSafe}}
+{{- end }}
+{{- else }}
 		key, v, err := {{rt "ReadStringBytes"}}(rest)
 		if err != nil {
 			return b, err
 		}
+{{- if .NoCase }}
+		key = {{rt "FoldKey"}}(key{{range .Fields}}, "{{.CBORName}}"{{end}})
+{{- end }}
 		switch key {
 {{- range .Fields }}
+{{- if .Deprecated }}
+		// Deprecated: {{.Deprecated}}
+{{- end }}
 		case "{{.CBORName}}":
 			{{.DecodeCaseSafe}}
+{{- end }}
 {{- end }}
 		default:
 			v, err = {{rt "Skip"}}(v)
 			if err != nil {
 				return b, err
 			}
+{{- if .Fallthrough }}
+			overflow = append(overflow, rest[:len(rest)-len(v)]...)
+			overflowN++
+{{- end }}
 		}
 		rest = v
 	}
+{{- if .Fallthrough }}
+	x.{{.Fallthrough}} = nil
+	if overflowN > 0 {
+		x.{{.Fallthrough}} = append({{rt "AppendMapHeader"}}(make([]byte, 0, {{rt "MapHeaderSize"}}+len(overflow)), overflowN), overflow...)
+	}
+{{- end }}
 	return rest, nil
 }
 
+{{- if .HasRefs }}
 // DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
-func (x *{{.Name}}) DecodeTrusted(b []byte) ([]byte, error) {
+func (x *{{.Name}}) DecodeTrusted({{if $.Context}}ctx context.Context, {{end}}b []byte) ([]byte, error) {
+	return x.DecodeTrustedRefs({{if $.Context}}ctx, {{end}}b, new({{rt "DecodeRefs"}}))
+}
+
+// DecodeTrustedRefs is DecodeTrusted resolving shared references through
+// refs, the table of the enclosing document.
+func (x *{{.Name}}) DecodeTrustedRefs({{if $.Context}}ctx context.Context, {{end}}b []byte, refs *{{rt "DecodeRefs"}}) ([]byte, error) {
+{{- else }}
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *{{.Name}}) DecodeTrusted({{if $.Context}}ctx context.Context, {{end}}b []byte) ([]byte, error) {
+{{- end }}
 	if x == nil {
 		return b, {{rt "ErrNotNil"}}
 	}
+{{- if $.Context }}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+{{- end }}
+{{- if .ToArray }}
+	if {{rt "NextType"}}(b) == {{rt "ArrayType"}} {
+		sz, rest, err := {{rt "ReadArrayHeaderBytes"}}(b)
+		if err != nil {
+			return b, err
+		}
+		for i := uint32(0); i < sz; i++ {
+			v := rest
+			switch i {
+{{- range $i, $f := .Fields }}
+{{- if $f.Deprecated }}
+			// Deprecated: {{$f.Deprecated}}
+{{- end }}
+			case {{$i}}:
+				{{$f.DecodeCaseTrust}}
+{{- end }}
+			default:
+				v, err = {{rt "Skip"}}(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+		}
+		return rest, nil
+	}
+{{- end }}
 	sz, rest, err := {{rt "ReadMapHeaderBytes"}}(b)
 	if err != nil {
 		return b, err
 	}
+{{- if .Fallthrough }}
+	var overflow []byte
+	var overflowN uint32
+{{- end }}
 	for i := uint32(0); i < sz; i++ {
+{{- if .IntKeys }}
+		key, v, err := {{rt "ReadUint64Bytes"}}(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+{{- range .Fields }}
+{{- if .Deprecated }}
+		// Deprecated: {{.Deprecated}}
+{{- end }}
+		case {{.IntKey}}:
+			{{.DecodeCaseTrust}}
+{{- end }}
+{{- else }}
 		keyBytes, v, err := {{rt "ReadStringZC"}}(rest)
 		if err != nil {
 			return b, err
 		}
 		key := {{rt "UnsafeString"}}(keyBytes)
+{{- if .NoCase }}
+		key = {{rt "FoldKey"}}(key{{range .Fields}}, "{{.CBORName}}"{{end}})
+{{- end }}
 		switch key {
 {{- range .Fields }}
+{{- if .Deprecated }}
+		// Deprecated: {{.Deprecated}}
+{{- end }}
 		case "{{.CBORName}}":
 			{{.DecodeCaseTrust}}
+{{- end }}
 {{- end }}
 		default:
 			v, err = {{rt "Skip"}}(v)
 			if err != nil {
 				return b, err
 			}
+{{- if .Fallthrough }}
+			overflow = append(overflow, rest[:len(rest)-len(v)]...)
+			overflowN++
+{{- end }}
 		}
 		rest = v
 	}
+{{- if .Fallthrough }}
+	x.{{.Fallthrough}} = nil
+	if overflowN > 0 {
+		x.{{.Fallthrough}} = append({{rt "AppendMapHeader"}}(make([]byte, 0, {{rt "MapHeaderSize"}}+len(overflow)), overflowN), overflow...)
+	}
+{{- end }}
 	return rest, nil
 }
 
 // UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
 func (x *{{.Name}}) UnmarshalCBOR(b []byte) ([]byte, error) {
+{{- if $.Context }}
+	return x.DecodeSafe(context.Background(), b)
+}
+
+// UnmarshalCBORContext implements cbor.ContextUnmarshaler using the Safe path.
+func (x *{{.Name}}) UnmarshalCBORContext(ctx context.Context, b []byte) ([]byte, error) {
+	return x.DecodeSafe(ctx, b)
+{{- else }}
 	return x.DecodeSafe(b)
+{{- end }}
 }
-{{end}}
+{{- if .HasRefs }}
+
+// UnmarshalCBORRefs implements cbor.RefUnmarshaler using the Safe path.
+func (x *{{.Name}}) UnmarshalCBORRefs(b []byte, refs *{{rt "DecodeRefs"}}) ([]byte, error) {
+	return x.DecodeSafeRefs({{if $.Context}}context.Background(), {{end}}b, refs)
+}
+{{- end }}
+{{- end }}
+{{- if .Validations }}
+
+// Validate checks the constraints declared in the field tags of {{.Name}}
+// and returns a cbor.ValidationError for the first one violated.
+func (x *{{.Name}}) Validate() error {
+{{- range .Validations }}
+	if {{.Cond}} {
+		return {{rt "ValidationError"}}{Field: {{printf "%q" .Field}}, Rule: {{printf "%q" .Rule}}}
+	}
+{{- end }}
+	return nil
+}
+{{- end }}
+{{- if .MethodChecks }}
+
+// Compile-time checks for {{.Name}} fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+{{- range .MethodChecks }}
+{{- if not $readOnly }}
+	_ {{rt "Marshaler"}} = (*{{.Type}})(nil) // {{.Field}}
+{{- end }}
+{{- if not $writeOnly }}
+	_ {{rt "Unmarshaler"}} = (*{{.Type}})(nil) // {{.Field}}
+{{- end }}
+{{- end }}
+)
+{{- end }}
+{{if .CDDL}}
+// CBORSchema returns a CDDL (RFC 8610) rule describing the encoding of {{.Name}}.
+func (x {{if .HasLock}}*{{end}}{{.Name}}) CBORSchema() string {
+	return `{{.CDDL}}`
+}
+{{end}}{{end}}
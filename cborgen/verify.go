@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/synadia-labs/cbor.go/cborgen/core"
+)
+
+// verifyDir is the --verify counterpart of runForDir: it regenerates
+// every companion file under dir in memory and compares it with the
+// file on disk.
+func verifyDir(dir string, opts core.Options) error {
+	paths, err := sourceFiles(dir)
+	if err != nil {
+		return err
+	}
+	outPaths := make([]string, len(paths))
+	for i, path := range paths {
+		outPaths[i] = defaultOutputPath(path)
+	}
+	return verifyFiles(paths, outPaths, opts, true)
+}
+
+// verifyFiles regenerates the output for each input path in memory and
+// prints a diff to stdout for every output file that is missing or
+// differs from the generated code. It returns an error if any file is
+// out of date, without writing anything. dirMode applies the per-file
+// options of directory mode.
+func verifyFiles(paths, outPaths []string, opts core.Options, dirMode bool) error {
+	outPath := make(map[string]string, len(paths))
+	for i, path := range paths {
+		outPath[path] = outPaths[i]
+	}
+
+	stale := 0
+	for _, pkgPaths := range byDir(paths) {
+		core.Collect(pkgPaths, opts)
+		for i, path := range pkgPaths {
+			fileOpts := opts
+			if dirMode {
+				fileOpts = dirFileOptions(opts, i)
+			}
+			want, err := core.Generate(path, outPath[path], fileOpts)
+			if err != nil {
+				return err
+			}
+			got, err := os.ReadFile(outPath[path])
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err == nil && bytes.Equal(got, want) {
+				continue
+			}
+			stale++
+			fmt.Print(lineDiff(outPath[path], got, want))
+		}
+	}
+
+	if stale > 0 {
+		return fmt.Errorf("%d generated file(s) out of date; run cborgen to update them", stale)
+	}
+	return nil
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// maxDiffEdits bounds the edit distance diffLines searches for. Beyond
+// it the differing lines are shown as one block, which keeps the
+// search's memory quadratic in a small number.
+const maxDiffEdits = 2000
+
+// diffOp is one line of an edit script: kept (' '), removed ('-') or
+// added ('+').
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// lineDiff renders a unified diff between old and new, with a hunk and
+// diffContext lines of context for each group of changes. It is enough
+// to show what changed without pulling in a diff library.
+func lineDiff(name string, old, new []byte) string {
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s (generated)\n", name, name)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk over changes separated by at most twice the
+		// context, so that neighbouring hunks do not overlap.
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				break
+			}
+			end = run
+		}
+		start := max(i-diffContext, 0)
+		stop := min(end+diffContext, len(ops))
+		writeHunk(&sb, ops, start, stop)
+		i = stop
+	}
+	return sb.String()
+}
+
+// writeHunk writes ops[start:stop] as one hunk, numbering its lines by
+// the ops before it.
+func writeHunk(sb *strings.Builder, ops []diffOp, start, stop int) {
+	var oldLine, newLine, oldN, newN int
+	for i, op := range ops[:stop] {
+		inHunk := i >= start
+		if op.kind != '+' {
+			if inHunk {
+				oldN++
+			} else {
+				oldLine++
+			}
+		}
+		if op.kind != '-' {
+			if inHunk {
+				newN++
+			} else {
+				newLine++
+			}
+		}
+	}
+	// An empty side is numbered by the line before it, as in diff -u.
+	if oldN > 0 {
+		oldLine++
+	}
+	if newN > 0 {
+		newLine++
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldLine, oldN, newLine, newN)
+	for _, op := range ops[start:stop] {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		sb.WriteByte('\n')
+	}
+}
+
+// diffLines returns a shortest edit script turning a into b. Within
+// each group of changes, removed lines come before added ones.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	// Move removals ahead of additions within each run of changes.
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(ops) && ops[j].kind != ' ' {
+			j++
+		}
+		slices.SortStableFunc(ops[i:j], func(x, y diffOp) int {
+			return cmp.Compare(y.kind, x.kind)
+		})
+		i = j
+	}
+	return ops
+}
+
+// myersDiff finds a shortest edit script with Myers' O(ND) algorithm,
+// falling back to removing all of a and adding all of b when the edit
+// distance exceeds maxDiffEdits.
+func myersDiff(a, b []string) []diffOp {
+	replace := func() []diffOp {
+		ops := make([]diffOp, 0, len(a)+len(b))
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replace()
+	}
+
+	// v[off+k] is the furthest x reached on diagonal k = x-y. trace[d]
+	// holds v[off-d-1 : off+d+2] as it was before step d.
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+	var trace [][]int
+	for d := 0; ; d++ {
+		if d > maxDiffEdits {
+			return replace()
+		}
+		trace = append(trace, slices.Clone(v[off-d-1:off+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return myersPath(trace, a, b)
+			}
+		}
+	}
+}
+
+// myersPath walks trace back from the end of a and b, returning the
+// edit script in order.
+func myersPath(trace [][]int, a, b []string) []diffOp {
+	var ops []diffOp
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		get := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(ops)
+	return ops
+}
+
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
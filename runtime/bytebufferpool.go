@@ -56,6 +56,16 @@ func (bb *ByteBuffer) Cap() int { return cap(bb.b) }
 // Reset resets the length to zero; capacity is unchanged.
 func (bb *ByteBuffer) Reset() { bb.b = bb.b[:0] }
 
+// TakeBytes returns a copy of the buffered bytes in a fresh allocation and
+// resets the buffer, so the result stays valid after the buffer is returned
+// to the pool. An empty buffer yields an empty, non-nil slice.
+func (bb *ByteBuffer) TakeBytes() []byte {
+	out := make([]byte, len(bb.b))
+	copy(out, bb.b)
+	bb.Reset()
+	return out
+}
+
 // Ensure ensures there is room for at least n more bytes without reallocation.
 // If needed, it grows the underlying slice.
 func (bb *ByteBuffer) Ensure(n int) {
@@ -193,3 +203,13 @@ func (bb *ByteBuffer) AppendTag(tag uint64) *ByteBuffer {
 	bb.b = AppendTag(bb.b, tag)
 	return bb
 }
+
+// ReadAll reads r until EOF into a pooled ByteBuffer and returns a copy of
+// the data, releasing the buffer back to the pool. Like io.ReadAll, it
+// returns the data read so far along with any error other than EOF.
+func ReadAll(r io.Reader) ([]byte, error) {
+	bb := GetByteBuffer()
+	defer PutByteBuffer(bb)
+	_, err := bb.ReadFrom(r)
+	return append([]byte(nil), bb.b...), err
+}
@@ -20,7 +20,10 @@
 //	cbor.Decode(io.Reader, cbor.Decodable)
 package cbor
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // RawPair represents an already-encoded CBOR key/value pair.
 // Key and Value must each contain exactly one CBOR item.
@@ -88,11 +91,14 @@ const (
 	tagBase64           = 22    // Expected base64 encoding
 	tagBase16           = 23    // Expected base16 encoding
 	tagCBOR             = 24    // Embedded CBOR data item
+	tagShareable        = 28    // Value that may be referenced by tag 29
+	tagSharedRef        = 29    // Reference to a tag 28 value
 	tagURI              = 32    // URI
 	tagBase64URLString  = 33    // base64url
 	tagBase64String     = 34    // base64
 	tagRegexp           = 35    // Regular expression
 	tagMIME             = 36    // MIME message
+	tagNetworkAddress   = 260   // IPv4, IPv6 or MAC address
 	tagSelfDescribeCBOR = 55799 // Self-describe CBOR (0xd9d9f7)
 )
 
@@ -181,6 +187,27 @@ type Unmarshaler interface {
 	UnmarshalCBOR([]byte) ([]byte, error)
 }
 
+// ContextUnmarshaler is implemented by types generated with cborgen's
+// --context flag. UnmarshalCBORContext behaves like UnmarshalCBOR but
+// returns ctx.Err() once ctx is done, checking it before each nested
+// struct is decoded.
+type ContextUnmarshaler interface {
+	UnmarshalCBORContext(ctx context.Context, b []byte) ([]byte, error)
+}
+
+// UnmarshalContext decodes b into u, passing ctx through when u is a
+// ContextUnmarshaler. Otherwise it returns ctx.Err() if ctx is already
+// done and calls u.UnmarshalCBOR.
+func UnmarshalContext(ctx context.Context, u Unmarshaler, b []byte) ([]byte, error) {
+	if cu, ok := u.(ContextUnmarshaler); ok {
+		return cu.UnmarshalCBORContext(ctx, b)
+	}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+	return u.UnmarshalCBOR(b)
+}
+
 // ValidateUTF8OnDecode controls whether ReadStringBytes validates UTF-8.
 // Enabled by default for spec compliance; can be disabled in hot paths.
 var ValidateUTF8OnDecode = true
@@ -5,13 +5,29 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // DiagBytes renders the next CBOR item in RFC diagnostic notation and returns the remaining bytes.
 func DiagBytes(b []byte) (string, []byte, error) {
+	return diagBytes(b, false)
+}
+
+// DiagBytesAnnotated is DiagBytes with each tag that has a registered
+// name preceded by a comment, e.g. /*epoch*/1(1700000000). The result is
+// still valid diagnostic notation. See RegisterTagName.
+func DiagBytesAnnotated(b []byte) (string, []byte, error) {
+	return diagBytes(b, true)
+}
+
+func diagBytes(b []byte, annotate bool) (string, []byte, error) {
 	bb := GetByteBuffer()
 	defer PutByteBuffer(bb)
-	rest, err := diagOneBuf(bb, b, 0)
+	rest, err := diagOneBuf(bb, b, 0, annotate)
 	if err != nil {
 		return "", b, err
 	}
@@ -20,9 +36,58 @@ func DiagBytes(b []byte) (string, []byte, error) {
 	return string(out), rest, nil
 }
 
-func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
+var (
+	tagNamesMu sync.RWMutex
+	// tagNames holds the names DiagBytesAnnotated shows for tags.
+	tagNames = map[uint64]string{
+		tagDateTimeString:   "datetime",
+		tagEpochDateTime:    "epoch",
+		tagPosBignum:        "pos-bignum",
+		tagNegBignum:        "neg-bignum",
+		tagDecimalFrac:      "decimal",
+		tagBigfloat:         "bigfloat",
+		tagBase64URL:        "expect-base64url",
+		tagBase64:           "expect-base64",
+		tagBase16:           "expect-base16",
+		tagCBOR:             "cbor",
+		tagShareable:        "shareable",
+		tagSharedRef:        "sharedref",
+		tagURI:              "uri",
+		tagBase64URLString:  "base64url",
+		tagBase64String:     "base64",
+		tagRegexp:           "regexp",
+		tagMIME:             "mime",
+		37:                  "uuid",
+		tagNetworkAddress:   "network-address",
+		tagSelfDescribeCBOR: "self-describe",
+	}
+)
+
+// RegisterTagName sets the name DiagBytesAnnotated shows for tag,
+// replacing any previous name; an empty name removes it. It is safe for
+// concurrent use.
+func RegisterTagName(tag uint64, name string) {
+	tagNamesMu.Lock()
+	defer tagNamesMu.Unlock()
+	if name == "" {
+		delete(tagNames, tag)
+		return
+	}
+	tagNames[tag] = name
+}
+
+func tagName(tag uint64) string {
+	tagNamesMu.RLock()
+	defer tagNamesMu.RUnlock()
+	return tagNames[tag]
+}
+
+// diagOneBuf writes the diagnostic notation of the next item in b to buf.
+// With annotate, tags with a registered name are preceded by a
+// /*name*/ comment.
+func diagOneBuf(buf *ByteBuffer, b []byte, depth int, annotate bool) ([]byte, error) {
 	if depth > recursionLimit {
-		return b, ErrMaxDepthExceeded
+		return b, MaxDepthError{Depth: depth}
 	}
 	if len(b) < 1 {
 		return b, ErrShortBytes
@@ -105,7 +170,7 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 				if err != nil {
 					return b, err
 				}
-				q := strconv.Quote(string(chunk))
+				q := quoteDiagString(string(chunk))
 				if !first {
 					buf.WriteString(", ")
 				} else {
@@ -120,7 +185,7 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 		if err != nil {
 			return b, err
 		}
-		buf.WriteString(strconv.Quote(s))
+		buf.WriteString(quoteDiagString(s))
 		return o, nil
 	case majorTypeArray:
 		if add == addInfoIndefinite {
@@ -142,7 +207,7 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 					first = false
 				}
 				var err error
-				p, err = diagOneBuf(buf, p, depth+1)
+				p, err = diagOneBuf(buf, p, depth+1, annotate)
 				if err != nil {
 					return b, err
 				}
@@ -158,7 +223,7 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 				buf.WriteString(", ")
 			}
 			var err error
-			p, err = diagOneBuf(buf, p, depth+1)
+			p, err = diagOneBuf(buf, p, depth+1, annotate)
 			if err != nil {
 				return b, err
 			}
@@ -186,13 +251,13 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 				}
 				// key
 				var err error
-				p, err = diagOneBuf(buf, p, depth+1)
+				p, err = diagOneBuf(buf, p, depth+1, annotate)
 				if err != nil {
 					return b, err
 				}
 				buf.WriteString(": ")
 				// value
-				p, err = diagOneBuf(buf, p, depth+1)
+				p, err = diagOneBuf(buf, p, depth+1, annotate)
 				if err != nil {
 					return b, err
 				}
@@ -208,12 +273,12 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 				buf.WriteString(", ")
 			}
 			var err error
-			p, err = diagOneBuf(buf, p, depth+1) // key
+			p, err = diagOneBuf(buf, p, depth+1, annotate) // key
 			if err != nil {
 				return b, err
 			}
 			buf.WriteString(": ")
-			p, err = diagOneBuf(buf, p, depth+1) // value
+			p, err = diagOneBuf(buf, p, depth+1, annotate) // value
 			if err != nil {
 				return b, err
 			}
@@ -225,9 +290,14 @@ func diagOneBuf(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 		if err != nil {
 			return b, err
 		}
+		if annotate {
+			if name := tagName(tag); name != "" {
+				buf.WriteString("/*" + name + "*/")
+			}
+		}
 		buf.WriteString(strconv.FormatUint(tag, 10))
 		buf.WriteString("(")
-		o2, err := diagOneBuf(buf, o, depth+1)
+		o2, err := diagOneBuf(buf, o, depth+1, annotate)
 		if err != nil {
 			return b, err
 		}
@@ -326,7 +396,11 @@ func formatFloat32Diag(f float32) string {
 }
 
 func trimTrailingZerosDot(s string) string {
-	// Trim trailing zeros and optional dot
+	// Trim trailing zeros and optional dot. Integral values such as
+	// "100" or "-0" have no fractional part to trim.
+	if !strings.Contains(s, ".") {
+		return s
+	}
 	i := len(s)
 	for i > 0 && s[i-1] == '0' {
 		i--
@@ -336,3 +410,44 @@ func trimTrailingZerosDot(s string) string {
 	}
 	return s[:i]
 }
+
+// quoteDiagString quotes s as a JSON string, the text string syntax of
+// diagnostic notation, so that ParseDiag reads it back. Printable runes
+// are written as is; other runes use \u escapes, with surrogate pairs
+// above the Basic Multilingual Plane. Invalid UTF-8 becomes U+FFFD.
+func quoteDiagString(s string) string {
+	buf := make([]byte, 0, len(s)+2)
+	buf = append(buf, '"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf = append(buf, '\\', byte(r))
+		case '\b':
+			buf = append(buf, `\b`...)
+		case '\f':
+			buf = append(buf, `\f`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		default:
+			if unicode.IsPrint(r) && r != utf8.RuneError {
+				buf = utf8.AppendRune(buf, r)
+				continue
+			}
+			if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+				buf = appendDiagEscape(buf, r1)
+				r = r2
+			}
+			buf = appendDiagEscape(buf, r)
+		}
+	}
+	return string(append(buf, '"'))
+}
+
+func appendDiagEscape(b []byte, r rune) []byte {
+	const hexDigits = "0123456789abcdef"
+	return append(b, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+}
@@ -0,0 +1,420 @@
+package cbor
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseDiag parses a single data item in RFC 8949 diagnostic notation and
+// returns its CBOR encoding. It is the inverse of DiagBytes and accepts:
+//
+//   - unsigned and negative integers (e.g. 42, -1)
+//   - floats in decimal or exponent form, Infinity, -Infinity and NaN
+//   - text strings ("hello") and byte strings (h'0102', b64'AQI=')
+//   - indefinite-length strings ((_ "a", "b"), (_ h'01', h'02'))
+//   - arrays ([1, 2], [_ 1, 2]) and maps ({1: 2}, {_ "a": 1})
+//   - tags (1(1363896240)) and simple values (true, false, null,
+//     undefined, simple(16))
+//   - /* comments */ between tokens, as written by DiagBytesAnnotated
+//
+// Text strings use JSON string syntax. Floats are encoded in the
+// shortest width that preserves their value, including the sign of -0.
+// Because DiagBytes renders integral floats without a fractional part,
+// such values parse back as integers; -0, which has no integer form,
+// parses as the float -0.
+func ParseDiag(s string) ([]byte, error) {
+	p := diagParser{s: s}
+	out, err := p.value(nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected trailing input")
+	}
+	return out, nil
+}
+
+// diagParser is a recursive-descent parser over diagnostic notation.
+type diagParser struct {
+	s   string
+	pos int
+}
+
+func (p *diagParser) errorf(msg string) error {
+	return DiagSyntaxError{Offset: p.pos, Msg: msg}
+}
+
+// skipSpace skips whitespace and /* */ comments, such as the tag names
+// written by DiagBytesAnnotated. An unterminated comment is left for
+// the caller to reject.
+func (p *diagParser) skipSpace() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		case '/':
+			if !strings.HasPrefix(p.s[p.pos:], "/*") {
+				return
+			}
+			end := strings.Index(p.s[p.pos+2:], "*/")
+			if end < 0 {
+				return
+			}
+			p.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// consume skips whitespace and then consumes c if it is next.
+func (p *diagParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *diagParser) value(b []byte, depth int) ([]byte, error) {
+	if depth > recursionLimit {
+		return b, MaxDepthError{Depth: depth}
+	}
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return b, p.errorf("unexpected end of input")
+	}
+	rest := p.s[p.pos:]
+	c := rest[0]
+	switch {
+	case c == '[':
+		return p.array(b, depth)
+	case c == '{':
+		return p.mapItem(b, depth)
+	case c == '(':
+		return p.indefiniteString(b)
+	case c == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return b, err
+		}
+		return AppendString(b, s), nil
+	case strings.HasPrefix(rest, "h'"), strings.HasPrefix(rest, "b64'"):
+		bs, err := p.byteString()
+		if err != nil {
+			return b, err
+		}
+		return AppendBytes(b, bs), nil
+	case c == '-' || c == '+' || (c >= '0' && c <= '9'):
+		return p.number(b, depth)
+	default:
+		return p.keyword(b)
+	}
+}
+
+func (p *diagParser) array(b []byte, depth int) ([]byte, error) {
+	p.pos++ // '['
+	indefinite := p.consume('_')
+	var items []byte
+	var n uint64
+	if !p.consume(']') {
+		for {
+			var err error
+			items, err = p.value(items, depth+1)
+			if err != nil {
+				return b, err
+			}
+			n++
+			if p.consume(']') {
+				break
+			}
+			if !p.consume(',') {
+				return b, p.errorf("expected ',' or ']' in array")
+			}
+		}
+	}
+	if indefinite {
+		b = AppendArrayHeaderIndefinite(b)
+		b = append(b, items...)
+		return AppendBreak(b), nil
+	}
+	b = appendUintCore(b, majorTypeArray, n)
+	return append(b, items...), nil
+}
+
+func (p *diagParser) mapItem(b []byte, depth int) ([]byte, error) {
+	p.pos++ // '{'
+	indefinite := p.consume('_')
+	var pairs []byte
+	var n uint64
+	if !p.consume('}') {
+		for {
+			var err error
+			pairs, err = p.value(pairs, depth+1)
+			if err != nil {
+				return b, err
+			}
+			if !p.consume(':') {
+				return b, p.errorf("expected ':' after map key")
+			}
+			pairs, err = p.value(pairs, depth+1)
+			if err != nil {
+				return b, err
+			}
+			n++
+			if p.consume('}') {
+				break
+			}
+			if !p.consume(',') {
+				return b, p.errorf("expected ',' or '}' in map")
+			}
+		}
+	}
+	if indefinite {
+		b = AppendMapHeaderIndefinite(b)
+		b = append(b, pairs...)
+		return AppendBreak(b), nil
+	}
+	b = appendUintCore(b, majorTypeMap, n)
+	return append(b, pairs...), nil
+}
+
+// indefiniteString parses (_ chunk, chunk, ...) where every chunk is
+// either a text string or a byte string.
+func (p *diagParser) indefiniteString(b []byte) ([]byte, error) {
+	p.pos++ // '('
+	if !p.consume('_') {
+		return b, p.errorf("expected '_' after '('")
+	}
+	var chunks []byte
+	var major uint8
+	started := false
+	if !p.consume(')') {
+		for {
+			p.skipSpace()
+			var m uint8
+			if p.pos < len(p.s) && p.s[p.pos] == '"' {
+				s, err := p.quoted()
+				if err != nil {
+					return b, err
+				}
+				m = majorTypeText
+				chunks = AppendString(chunks, s)
+			} else {
+				bs, err := p.byteString()
+				if err != nil {
+					return b, err
+				}
+				m = majorTypeBytes
+				chunks = AppendBytes(chunks, bs)
+			}
+			if started && m != major {
+				return b, p.errorf("mixed chunk types in indefinite-length string")
+			}
+			major, started = m, true
+			if p.consume(')') {
+				break
+			}
+			if !p.consume(',') {
+				return b, p.errorf("expected ',' or ')' in indefinite-length string")
+			}
+		}
+	}
+	if !started {
+		return b, p.errorf("empty indefinite-length string has no type")
+	}
+	b = append(b, makeByte(major, addInfoIndefinite))
+	b = append(b, chunks...)
+	return AppendBreak(b), nil
+}
+
+// quoted parses a double-quoted text string starting at p.pos.
+func (p *diagParser) quoted() (string, error) {
+	start := p.pos
+	i := p.pos + 1
+	for i < len(p.s) {
+		switch p.s[i] {
+		case '\\':
+			i += 2
+			continue
+		case '"':
+			// Text strings use JSON string syntax (RFC 8949 §8), so
+			// \/ and surrogate-pair \u escapes are accepted while Go
+			// escapes such as \x41 are not.
+			var s string
+			if err := json.Unmarshal([]byte(p.s[start:i+1]), &s); err != nil {
+				return "", p.errorf("invalid text string")
+			}
+			p.pos = i + 1
+			return s, nil
+		}
+		i++
+	}
+	return "", p.errorf("unterminated text string")
+}
+
+// byteString parses h'..' (hex) or b64'..' (base64 or base64url,
+// padded or not). Whitespace inside the quotes is ignored.
+func (p *diagParser) byteString() ([]byte, error) {
+	rest := p.s[p.pos:]
+	var prefix int
+	isHex := false
+	switch {
+	case strings.HasPrefix(rest, "h'"):
+		prefix, isHex = 2, true
+	case strings.HasPrefix(rest, "b64'"):
+		prefix = 4
+	default:
+		return nil, p.errorf("expected byte string")
+	}
+	end := strings.IndexByte(rest[prefix:], '\'')
+	if end < 0 {
+		return nil, p.errorf("unterminated byte string")
+	}
+	body := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, rest[prefix:prefix+end])
+	var bs []byte
+	var err error
+	if isHex {
+		bs, err = hex.DecodeString(body)
+	} else {
+		bs, err = decodeDiagBase64(body)
+	}
+	if err != nil {
+		return nil, p.errorf("invalid byte string payload")
+	}
+	p.pos += prefix + end + 1
+	return bs, nil
+}
+
+func decodeDiagBase64(s string) ([]byte, error) {
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	return enc.DecodeString(strings.TrimRight(s, "="))
+}
+
+// number parses an integer, a float, -Infinity, or a tag number
+// followed by its parenthesised content.
+func (p *diagParser) number(b []byte, depth int) ([]byte, error) {
+	start := p.pos
+	if strings.HasPrefix(p.s[p.pos:], "-Infinity") {
+		p.pos += len("-Infinity")
+		return AppendFloatCanonical(b, math.Inf(-1)), nil
+	}
+	isFloat := false
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		if c == '.' || c == 'e' || c == 'E' {
+			isFloat = true
+			continue
+		}
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		// Signs may only lead the literal or its exponent.
+		if (c == '-' || c == '+') && (p.pos == start || p.s[p.pos-1] == 'e' || p.s[p.pos-1] == 'E') {
+			continue
+		}
+		break
+	}
+	lit := p.s[start:p.pos]
+	if strings.TrimLeft(lit, "+-") == "" {
+		p.pos = start
+		return b, p.errorf("invalid number")
+	}
+	if isFloat {
+		f, err := strconv.ParseFloat(lit, 64)
+		if err != nil {
+			p.pos = start
+			return b, p.errorf("invalid float " + strconv.Quote(lit))
+		}
+		if f == 0 && math.Signbit(f) {
+			// AppendFloatCanonical folds -0 into 0; keep the sign.
+			return AppendFloat16(b, float32(f)), nil
+		}
+		return AppendFloatCanonical(b, f), nil
+	}
+	neg := lit[0] == '-'
+	digits := strings.TrimLeft(lit, "+-")
+	u, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil && neg && digits == "18446744073709551616" {
+		// -2^64 is the most negative major type 1 integer.
+		return appendUintCore(b, majorTypeNegInt, math.MaxUint64), nil
+	}
+	if err != nil {
+		p.pos = start
+		return b, p.errorf("invalid integer " + strconv.Quote(lit))
+	}
+	if !neg && lit[0] != '+' && p.pos < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		b = AppendTag(b, u)
+		b, err = p.value(b, depth+1)
+		if err != nil {
+			return b, err
+		}
+		if !p.consume(')') {
+			return b, p.errorf("expected ')' after tag content")
+		}
+		return b, nil
+	}
+	if neg {
+		if u == 0 {
+			// There is no negative zero integer, and DiagBytes writes
+			// the float -0 as -0, so read it back as that float.
+			return AppendFloat16(b, float32(math.Copysign(0, -1))), nil
+		}
+		return appendUintCore(b, majorTypeNegInt, u-1), nil
+	}
+	return AppendUint64(b, u), nil
+}
+
+// keyword parses the named simple values, Infinity/NaN and simple(N).
+func (p *diagParser) keyword(b []byte) ([]byte, error) {
+	rest := p.s[p.pos:]
+	for _, kw := range []struct {
+		name string
+		emit func([]byte) []byte
+	}{
+		{"false", func(b []byte) []byte { return AppendBool(b, false) }},
+		{"true", func(b []byte) []byte { return AppendBool(b, true) }},
+		{"null", AppendNil},
+		{"undefined", AppendUndefined},
+		{"Infinity", func(b []byte) []byte { return AppendFloatCanonical(b, math.Inf(1)) }},
+		{"NaN", func(b []byte) []byte { return AppendFloatCanonical(b, math.NaN()) }},
+	} {
+		if strings.HasPrefix(rest, kw.name) {
+			p.pos += len(kw.name)
+			return kw.emit(b), nil
+		}
+	}
+	if strings.HasPrefix(rest, "simple(") {
+		p.pos += len("simple(")
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		v, err := strconv.ParseUint(p.s[start:p.pos], 10, 8)
+		if err != nil || reservedSimpleValue(uint8(v)) {
+			p.pos = start
+			return b, p.errorf("invalid simple value")
+		}
+		if !p.consume(')') {
+			return b, p.errorf("expected ')' after simple value")
+		}
+		return AppendSimpleValue(b, uint8(v)), nil
+	}
+	return b, p.errorf("unexpected character " + strconv.QuoteRune(rune(rest[0])))
+}
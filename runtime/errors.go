@@ -22,7 +22,8 @@ var (
 	// Limits can be set on the Reader to prevent excessive memory usage by adversarial data.
 	ErrLimitExceeded error = errLimitExceeded{}
 
-	// ErrMaxDepthExceeded is returned when skip recursion depth exceeds limit
+	// ErrMaxDepthExceeded matches, via errors.Is, the MaxDepthError returned
+	// when nesting exceeds the recursion limit.
 	ErrMaxDepthExceeded error = errors.New("cbor: max depth exceeded")
 
 	// ErrNotNil is returned when expecting nil
@@ -42,8 +43,36 @@ var (
 	// ErrNonCanonicalInteger is returned when an integer is not encoded in the shortest form.
 	ErrNonCanonicalInteger error = errors.New("cbor: non-canonical integer encoding")
 
+	// ErrNilUnmarshaler is returned by generated decoders when a non-null
+	// value is decoded into an interface field that holds no concrete value
+	// to unmarshal into.
+	ErrNilUnmarshaler error = errors.New("cbor: cannot decode into nil interface value")
+
 	// ErrNonCanonicalLength is returned when a length (array/map/str/bytes) is not encoded in the shortest form.
 	ErrNonCanonicalLength error = errors.New("cbor: non-canonical length encoding")
+
+	// ErrInvalidReference is returned when a shared reference (tag 29)
+	// names no earlier shareable value of the expected type.
+	ErrInvalidReference error = errors.New("cbor: invalid shared reference")
+
+	// ErrInvalidSimpleValue is returned in strict mode when a simple value
+	// below 32 uses the two-byte (0xf8) form, which RFC 8949 section 3.3
+	// does not allow, and when writing one of the values 24..31, which
+	// could only take that form.
+	ErrInvalidSimpleValue error = errors.New("cbor: simple value below 32 in two-byte form")
+
+	// ErrNegativeUnsigned is returned when encoding a negative value in a
+	// signed integer field tagged cbor:",unsigned".
+	ErrNegativeUnsigned error = errors.New("cbor: negative value for unsigned field")
+
+	// ErrUnknownVariant is returned by generated code for a cbor:",oneof"
+	// field that holds a type outside its list, or whose encoded map has
+	// none of the keys that identify one of those types.
+	ErrUnknownVariant error = errors.New("cbor: value is not one of the field's oneof types")
+
+	// ErrUnexpectedNull is returned by ReadInterfaceWithOptions with
+	// RejectNull set when the input contains null or undefined.
+	ErrUnexpectedNull error = errors.New("cbor: unexpected null")
 )
 
 // Error is the interface satisfied
@@ -155,6 +184,41 @@ type errLimitExceeded struct{}
 func (e errLimitExceeded) Error() string   { return "cbor: configured reader limit exceeded" }
 func (e errLimitExceeded) Resumable() bool { return false }
 
+// MaxDepthError is returned when nested arrays, maps or tags exceed the
+// recursion limit. Depth is the nesting depth at which decoding stopped.
+// It matches ErrMaxDepthExceeded under errors.Is.
+type MaxDepthError struct {
+	Depth int
+}
+
+// Error implements the error interface.
+func (e MaxDepthError) Error() string {
+	return "cbor: max depth exceeded at depth " + strconv.Itoa(e.Depth)
+}
+
+// Is reports whether target is ErrMaxDepthExceeded.
+func (e MaxDepthError) Is(target error) bool { return target == ErrMaxDepthExceeded }
+
+// Resumable returns 'false' for MaxDepthErrors.
+func (e MaxDepthError) Resumable() bool { return false }
+
+// ValidationError is returned by the Validate methods cborgen generates
+// when a field violates a constraint declared in its tag, e.g.
+// cbor:"age,min=0". Field is the field's CBOR key and Rule the violated
+// tag option.
+type ValidationError struct {
+	Field string
+	Rule  string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return "cbor: field " + quoteStr(e.Field) + " violates " + e.Rule
+}
+
+// Resumable returns 'true' for ValidationErrors.
+func (e ValidationError) Resumable() bool { return true }
+
 // ArrayError is an error returned
 // when decoding a fix-sized array
 // of the wrong size
@@ -336,6 +400,28 @@ func (i InvalidAdditionalInfoError) Error() string {
 // Resumable returns 'false' for InvalidAdditionalInfoErrors.
 func (i InvalidAdditionalInfoError) Resumable() bool { return false }
 
+// InvalidTagError is returned when a value carries a tag other than
+// the ones a reader accepts, which are listed in Want.
+type InvalidTagError struct {
+	Want []uint64
+	Got  uint64
+}
+
+// Error implements the error interface.
+func (i InvalidTagError) Error() string {
+	out := "cbor: expected tag "
+	for n, tag := range i.Want {
+		if n > 0 {
+			out += " or "
+		}
+		out += strconv.FormatUint(tag, 10)
+	}
+	return out + " but got " + strconv.FormatUint(i.Got, 10)
+}
+
+// Resumable returns 'false' for InvalidTagErrors.
+func (i InvalidTagError) Resumable() bool { return false }
+
 // ErrUnsupportedType is returned when a bad argument is supplied to
 // a function that accepts arbitrary values.
 type ErrUnsupportedType struct {
@@ -361,3 +447,18 @@ func (e *ErrUnsupportedType) withContext(ctx string) error {
 	o.ctx = addCtx(o.ctx, ctx)
 	return &o
 }
+
+// DiagSyntaxError is returned by ParseDiag when its input is not valid
+// diagnostic notation.
+type DiagSyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+// Error implements the error interface.
+func (d DiagSyntaxError) Error() string {
+	return "cbor: diagnostic notation: " + d.Msg + " at offset " + strconv.Itoa(d.Offset)
+}
+
+// Resumable returns 'false' for DiagSyntaxErrors.
+func (d DiagSyntaxError) Resumable() bool { return false }
@@ -1,6 +1,9 @@
 package cbor
 
-import "unicode/utf8"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // getType returns the CBOR type from a byte
 func getType(b byte) Type {
@@ -54,6 +57,24 @@ func Require(b []byte, n int) []byte {
 	return nb
 }
 
+// FoldKey returns the first of keys equal to key under Unicode case
+// folding, or key itself when none is. An exact match is tried first.
+// Decoders generated for structs tagged cbor:",nocase" use it to map an
+// incoming map key onto the field name before switching on it.
+func FoldKey(key string, keys ...string) string {
+	for _, k := range keys {
+		if k == key {
+			return key
+		}
+	}
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
+
 // IsLikelyJSON reports whether the given byte slice looks like JSON text
 // rather than CBOR. It is a heuristic and not a formal discriminator:
 //
@@ -0,0 +1,53 @@
+package cbor
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// stringInterner is a bounded cache of decoded strings shared by
+// ReadStringBytes once EnableStringInterning has been called.
+type stringInterner struct {
+	m   sync.Map // string -> string
+	n   atomic.Int64
+	max int64
+}
+
+var internCache atomic.Pointer[stringInterner]
+
+// EnableStringInterning makes ReadStringBytes return cached strings for
+// text it has decoded before, so workloads that repeatedly decode the
+// same keys and names (stream or consumer names, for example) stop
+// allocating a new string each time. At most maxCacheSize distinct
+// strings are cached; once full, new strings are returned uncached.
+// Calling it again replaces the cache, and a maxCacheSize of zero or
+// less disables interning. It is safe for concurrent use.
+func EnableStringInterning(maxCacheSize int) {
+	if maxCacheSize <= 0 {
+		internCache.Store(nil)
+		return
+	}
+	internCache.Store(&stringInterner{max: int64(maxCacheSize)})
+}
+
+// internString returns b as a string, from the intern cache when
+// interning is enabled.
+func internString(b []byte) string {
+	c := internCache.Load()
+	if c == nil {
+		return string(b)
+	}
+	// The lookup key is not retained, so it may alias b.
+	if s, ok := c.m.Load(UnsafeString(b)); ok {
+		return s.(string)
+	}
+	s := string(b)
+	// Concurrent stores may overshoot max slightly; the bound only needs
+	// to stop unbounded growth.
+	if c.n.Load() < c.max {
+		if _, loaded := c.m.LoadOrStore(s, s); !loaded {
+			c.n.Add(1)
+		}
+	}
+	return s
+}
@@ -26,7 +26,7 @@ func ToJSONBytes(b []byte) ([]byte, []byte, error) {
 
 func toJSON(buf *ByteBuffer, b []byte, depth int) ([]byte, error) {
 	if depth > recursionLimit {
-		return b, ErrMaxDepthExceeded
+		return b, MaxDepthError{Depth: depth}
 	}
 	if len(b) < 1 {
 		return b, ErrShortBytes
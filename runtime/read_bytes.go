@@ -2,9 +2,12 @@ package cbor
 
 import (
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"math"
 	bigmath "math/big"
+	"net"
 	"regexp"
 	"time"
 )
@@ -233,6 +236,46 @@ func ReadFloat16Bytes(b []byte) (f float32, o []byte, err error) {
 	return f, b[3:], nil
 }
 
+// readAnyFloatBytes reads a half, single or double precision float as
+// a float64.
+func readAnyFloatBytes(b []byte) (float64, []byte, error) {
+	if len(b) < 1 {
+		return 0, b, ErrShortBytes
+	}
+	switch b[0] {
+	case 0xf9:
+		f, o, err := ReadFloat16Bytes(b)
+		return float64(f), o, err
+	case 0xfa:
+		f, o, err := ReadFloat32Bytes(b)
+		return float64(f), o, err
+	default:
+		return ReadFloat64Bytes(b)
+	}
+}
+
+// ReadComplex128Bytes reads a complex number written by AppendComplex128
+// or AppendComplex64: a two-element array of floats holding the real
+// and imaginary parts, each of any float width.
+func ReadComplex128Bytes(b []byte) (c complex128, o []byte, err error) {
+	sz, o, err := ReadArrayHeaderBytes(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if sz != 2 {
+		return 0, b, ArrayError{Wanted: 2, Got: sz}
+	}
+	re, o, err := readAnyFloatBytes(o)
+	if err != nil {
+		return 0, b, err
+	}
+	im, o, err := readAnyFloatBytes(o)
+	if err != nil {
+		return 0, b, err
+	}
+	return complex(re, im), o, nil
+}
+
 // ReadBoolBytes reads a bool
 func ReadBoolBytes(b []byte) (bool, []byte, error) {
 	if len(b) < 1 {
@@ -248,6 +291,22 @@ func ReadBoolBytes(b []byte) (bool, []byte, error) {
 	return false, b, TypeError{Method: BoolType, Encoded: getType(b[0])}
 }
 
+// ReadBoolLenientBytes reads a bool encoded either as a CBOR boolean or,
+// as some older producers do, as the unsigned integer 0 or 1.
+func ReadBoolLenientBytes(b []byte) (bool, []byte, error) {
+	if len(b) < 1 || getMajorType(b[0]) != majorTypeUint {
+		return ReadBoolBytes(b)
+	}
+	u, o, err := ReadUint64Bytes(b)
+	if err != nil {
+		return false, b, err
+	}
+	if u > 1 {
+		return false, b, TypeError{Method: BoolType, Encoded: getType(b[0])}
+	}
+	return u == 1, o, nil
+}
+
 // ReadInt64Bytes reads an int64
 func ReadInt64Bytes(b []byte) (i int64, o []byte, err error) {
 	if len(b) < 1 {
@@ -424,7 +483,10 @@ func ReadUintBytes(b []byte) (u uint, o []byte, err error) {
 	return uint(u64), o, nil
 }
 
-// ReadBytesBytes reads a byte string
+// ReadBytesBytes reads a byte string. A definite-length string is
+// returned zero-copy as a sub-slice of b, so it shares b's memory; the
+// chunks of an indefinite-length string are appended to scratch[:0].
+// Use ReadBytesCopy for a slice that does not alias b.
 func ReadBytesBytes(b []byte, scratch []byte) (v []byte, o []byte, err error) {
 	if len(b) < 1 {
 		return nil, b, ErrShortBytes
@@ -533,6 +595,16 @@ func ReadBytesBytes(b []byte, scratch []byte) (v []byte, o []byte, err error) {
 	}
 }
 
+// ReadBytesCopy reads a byte string into a newly allocated slice, which
+// is non-nil even for an empty string and never aliases b.
+func ReadBytesCopy(b []byte) (v []byte, o []byte, err error) {
+	v, o, err = ReadBytesBytes(b, nil)
+	if err != nil {
+		return nil, b, err
+	}
+	return append([]byte{}, v...), o, nil
+}
+
 // ReadStringZC reads a text string zero-copy (returns slice into original buffer)
 func ReadStringZC(b []byte) (v []byte, o []byte, err error) {
 	if len(b) < 1 {
@@ -605,10 +677,47 @@ func ReadStringZC(b []byte) (v []byte, o []byte, err error) {
 	return b[start:end], b[end:], nil
 }
 
-// ReadStringBytes reads a text string
+// ReadStringBytes reads a text string. After EnableStringInterning,
+// definite-length strings that were decoded before are returned from
+// the intern cache instead of being allocated again.
 func ReadStringBytes(b []byte) (s string, o []byte, err error) {
+	v, o, err := readStringContent(b)
+	if err != nil {
+		return "", b, err
+	}
+	if ValidateUTF8OnDecode && !isUTF8Valid(v) {
+		return "", b, ErrInvalidUTF8
+	}
+	if UnsafeStringDecode {
+		return UnsafeString(v), o, nil
+	}
+	return internString(v), o, nil
+}
+
+// ReadStringBytesStrict is like ReadStringBytes but always validates
+// UTF-8, regardless of ValidateUTF8OnDecode. Invalid text is reported as
+// ErrInvalidUTF8 wrapped with the offset of the first bad byte within
+// the string's content.
+func ReadStringBytesStrict(b []byte) (s string, o []byte, err error) {
+	v, o, err := readStringContent(b)
+	if err != nil {
+		return "", b, err
+	}
+	if !isUTF8Valid(v) {
+		return "", b, WrapError(ErrInvalidUTF8, fmt.Sprintf("offset %d", invalidUTF8Offset(v)))
+	}
+	if UnsafeStringDecode {
+		return UnsafeString(v), o, nil
+	}
+	return internString(v), o, nil
+}
+
+// readStringContent returns the content of a definite or indefinite
+// length text string. Definite strings alias b; the chunks of an
+// indefinite string are joined into a new slice.
+func readStringContent(b []byte) (v []byte, o []byte, err error) {
 	if len(b) < 1 {
-		return "", b, ErrShortBytes
+		return nil, b, ErrShortBytes
 	}
 	// Indefinite-length text string (0x7f)
 	if b[0] == makeByte(majorTypeText, addInfoIndefinite) {
@@ -616,33 +725,20 @@ func ReadStringBytes(b []byte) (s string, o []byte, err error) {
 		var out []byte
 		for {
 			if len(p) < 1 {
-				return "", b, ErrShortBytes
+				return nil, b, ErrShortBytes
 			}
 			if p[0] == makeByte(majorTypeSimple, simpleBreak) {
-				if ValidateUTF8OnDecode && !isUTF8Valid(out) {
-					return "", b, ErrInvalidUTF8
-				}
-				return string(out), p[1:], nil
+				return out, p[1:], nil
 			}
 			chunk, q, e := ReadStringZC(p)
 			if e != nil {
-				return "", b, e
+				return nil, b, e
 			}
 			out = append(out, chunk...)
 			p = q
 		}
 	}
-	v, o, err := ReadStringZC(b)
-	if err != nil {
-		return "", b, err
-	}
-	if ValidateUTF8OnDecode && !isUTF8Valid(v) {
-		return "", b, ErrInvalidUTF8
-	}
-	if UnsafeStringDecode {
-		return UnsafeString(v), o, nil
-	}
-	return string(v), o, nil
+	return ReadStringZC(b)
 }
 
 // ReadMapKeyZC reads a map key expecting a text string and returns its bytes zero-copy.
@@ -659,6 +755,42 @@ func ReadMapKeyZC(b []byte) (v []byte, o []byte, err error) {
 	return ReadStringZC(b)
 }
 
+// ReadMapKeyBytes reads a map key that is a definite-length text
+// string or an integer and returns its raw encoding, header included,
+// aliasing b. Comparing the result with a pre-encoded key (for example
+// AppendUint64(nil, 7)) dispatches on text and integer keys alike;
+// MapKeyIsString and MapKeyIsUint64 tell the two apart. Other key
+// types are reported as a TypeError.
+func ReadMapKeyBytes(b []byte) (keyBytes []byte, o []byte, err error) {
+	if len(b) < 1 {
+		return nil, b, ErrShortBytes
+	}
+	switch getMajorType(b[0]) {
+	case majorTypeText:
+		_, o, err = ReadStringZC(b)
+	case majorTypeUint, majorTypeNegInt:
+		_, o, err = readUintCore(b, getMajorType(b[0]))
+	default:
+		return nil, b, TypeError{Method: StrType, Encoded: getType(b[0])}
+	}
+	if err != nil {
+		return nil, b, err
+	}
+	return b[:len(b)-len(o)], o, nil
+}
+
+// MapKeyIsString reports whether keyBytes, as returned by
+// ReadMapKeyBytes, is a text string.
+func MapKeyIsString(keyBytes []byte) bool {
+	return len(keyBytes) > 0 && getMajorType(keyBytes[0]) == majorTypeText
+}
+
+// MapKeyIsUint64 reports whether keyBytes, as returned by
+// ReadMapKeyBytes, is an unsigned integer.
+func MapKeyIsUint64(keyBytes []byte) bool {
+	return len(keyBytes) > 0 && getMajorType(keyBytes[0]) == majorTypeUint
+}
+
 // ReadSimpleValue reads a simple value and returns its numeric value.
 // Returns values 0..23 (including false/true/null/undefined) directly,
 // or 32..255 following a 0xf8 prefix. Float encodings are not handled here.
@@ -774,6 +906,38 @@ func ReadTagBytes(b []byte) (tag uint64, o []byte, err error) {
 	return tag, o, nil
 }
 
+// ReadTagsBytes reads the run of consecutive semantic tags at the start
+// of b, such as tag 55799 wrapping tag 1, and returns them outermost
+// first with o positioned at the tagged item. An untagged item yields no
+// tags and no error.
+func ReadTagsBytes(b []byte) (tags []uint64, o []byte, err error) {
+	o = b
+	for {
+		if len(o) < 1 {
+			return nil, b, ErrShortBytes
+		}
+		if getMajorType(o[0]) != majorTypeTag {
+			return tags, o, nil
+		}
+		var tag uint64
+		tag, o, err = readUintCore(o, majorTypeTag)
+		if err != nil {
+			return nil, b, err
+		}
+		tags = append(tags, tag)
+	}
+}
+
+// ReadUnixMilliBytes reads an integer count of milliseconds since the
+// Unix epoch, as written by AppendUnixMilli.
+func ReadUnixMilliBytes(b []byte) (t time.Time, o []byte, err error) {
+	ms, o, err := ReadInt64Bytes(b)
+	if err != nil {
+		return time.Time{}, b, err
+	}
+	return time.UnixMilli(ms), o, nil
+}
+
 // ReadRFC3339TimeBytes reads a tag(0) RFC3339 time string into time.Time
 func ReadRFC3339TimeBytes(b []byte) (t time.Time, o []byte, err error) {
 	tag, o, err := ReadTagBytes(b)
@@ -818,6 +982,21 @@ func ReadBase64StringBytes(b []byte) (s string, o []byte, err error) {
 	return ReadStringBytes(o)
 }
 
+// ReadHexStringBytes reads a text string written by AppendHexString and
+// returns the decoded bytes in a new, non-nil slice. Upper-case digits
+// are accepted; other characters or an odd length are an error.
+func ReadHexStringBytes(b []byte) (data []byte, o []byte, err error) {
+	s, o, err := ReadStringZC(b)
+	if err != nil {
+		return nil, b, err
+	}
+	data, err = hex.AppendDecode(make([]byte, 0, hex.DecodedLen(len(s))), s)
+	if err != nil {
+		return nil, b, err
+	}
+	return data, o, nil
+}
+
 // ReadURIStringBytes reads a tag(32) URI text string
 func ReadURIStringBytes(b []byte) (uri string, o []byte, err error) {
 	tag, o, err := ReadTagBytes(b)
@@ -878,6 +1057,46 @@ func ReadBase16Bytes(b []byte) (bs []byte, o []byte, err error) {
 	return ReadBytesBytes(o, nil)
 }
 
+// ReadIPBytes reads tag(260) with a 4- or 16-byte IP address. The
+// returned address does not alias b.
+func ReadIPBytes(b []byte) (ip net.IP, o []byte, err error) {
+	tag, o, err := ReadTagBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if tag != tagNetworkAddress {
+		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+	}
+	bs, o2, err := ReadBytesBytes(o, nil)
+	if err != nil {
+		return nil, b, err
+	}
+	if len(bs) != net.IPv4len && len(bs) != net.IPv6len {
+		return nil, b, errors.New("cbor: ip address must be 4 or 16 bytes")
+	}
+	return append(net.IP(nil), bs...), o2, nil
+}
+
+// ReadHardwareAddrBytes reads tag(260) with a 6- or 8-byte MAC address.
+// The returned address does not alias b.
+func ReadHardwareAddrBytes(b []byte) (addr net.HardwareAddr, o []byte, err error) {
+	tag, o, err := ReadTagBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if tag != tagNetworkAddress {
+		return nil, b, badPrefix(majorTypeTag, majorTypeTag)
+	}
+	bs, o2, err := ReadBytesBytes(o, nil)
+	if err != nil {
+		return nil, b, err
+	}
+	if len(bs) != 6 && len(bs) != 8 {
+		return nil, b, errors.New("cbor: hardware address must be 6 or 8 bytes")
+	}
+	return append(net.HardwareAddr(nil), bs...), o2, nil
+}
+
 // ReadUUIDBytes reads tag(37) UUID as 16-byte array
 func ReadUUIDBytes(b []byte) (uuid [16]byte, o []byte, err error) {
 	tag, o, err := ReadTagBytes(b)
@@ -1116,70 +1335,68 @@ func ReadBigfloatBytes(b []byte) (exp int64, mant *bigmath.Int, o []byte, err er
 	return exp, mant, p, nil
 }
 
+// ReadBigfloatAsFloatBytes reads a tag(5) bigfloat into a *big.Float
+// holding its exact value. Exponents beyond the range of big.Float
+// yield an infinity or zero.
+func ReadBigfloatAsFloatBytes(b []byte) (f *bigmath.Float, o []byte, err error) {
+	exp, mant, o, err := ReadBigfloatBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	// big.Float exponents are int32; clamping keeps int(exp) exact on
+	// 32-bit platforms without changing the overflow result.
+	exp = max(min(exp, math.MaxInt32), math.MinInt32)
+	f = new(bigmath.Float).SetInt(mant)
+	return f.SetMantExp(f, int(exp)), o, nil
+}
+
 // ReadMapNoDupBytes validates that the next CBOR item is a map and that it has no duplicate keys.
 // Keys are compared by raw CBOR byte representation. Returns the bytes after the map or an error.
+// It is equivalent to ValidateMapNoDupKeys.
 func ReadMapNoDupBytes(b []byte) (o []byte, err error) {
-	if len(b) < 1 {
-		return b, ErrShortBytes
-	}
-	if getMajorType(b[0]) != majorTypeMap {
-		return b, badPrefix(majorTypeMap, getMajorType(b[0]))
+	return ValidateMapNoDupKeys(b)
+}
+
+// ValidateMapNoDupKeys checks that the next CBOR item is a well-formed
+// map, definite or indefinite, without duplicate keys, and returns the
+// bytes after it. Keys are compared by their raw CBOR encoding. Pairs
+// are checked as they are read and the set of seen keys refers to b
+// rather than copying the keys, so it costs one set entry per pair and
+// is discarded on return.
+func ValidateMapNoDupKeys(b []byte) (rest []byte, err error) {
+	sz, indefinite, p, err := ReadMapStartBytes(b)
+	if err != nil {
+		return b, err
 	}
-	// Indefinite-length map
-	if getAddInfo(b[0]) == addInfoIndefinite {
-		seen := make(map[string]struct{})
-		p := b[1:]
-		for {
-			if len(p) < 1 {
-				return b, ErrShortBytes
-			}
-			if p[0] == makeByte(majorTypeSimple, simpleBreak) {
-				return p[1:], nil
-			}
-			// Capture raw key bytes
-			r, err := Skip(p)
+	// Every pair takes at least two bytes; don't trust sz beyond that.
+	// Clamp before converting, as int(sz) is negative on 32-bit
+	// platforms for sz >= 1<<31.
+	seen := make(map[string]struct{}, int(min(uint64(sz), uint64(len(p)/2))))
+	for i := uint32(0); indefinite || i < sz; i++ {
+		if indefinite {
+			var done bool
+			p, done, err = ReadBreakBytes(p)
 			if err != nil {
 				return b, err
 			}
-			keyLen := len(p) - len(r)
-			rawKey := p[:keyLen]
-			keyStr := string(rawKey)
-			if _, ok := seen[keyStr]; ok {
-				return b, ErrDuplicateMapKey
-			}
-			seen[keyStr] = struct{}{}
-			// Skip value
-			r2, err := Skip(r)
-			if err != nil {
-				return b, err
+			if done {
+				break
 			}
-			p = r2
 		}
-	}
-	// Definite-length map
-	sz, p, err := ReadMapHeaderBytes(b)
-	if err != nil {
-		return b, err
-	}
-	seen := make(map[string]struct{}, sz)
-	for i := uint32(0); i < sz; i++ {
 		r, err := Skip(p)
 		if err != nil {
 			return b, err
 		}
-		keyLen := len(p) - len(r)
-		rawKey := p[:keyLen]
-		keyStr := string(rawKey)
-		if _, ok := seen[keyStr]; ok {
+		key := UnsafeString(p[:len(p)-len(r)])
+		if _, ok := seen[key]; ok {
 			return b, ErrDuplicateMapKey
 		}
-		seen[keyStr] = struct{}{}
-		// skip value
-		p2, err := Skip(r)
+		seen[key] = struct{}{}
+		// Skip value
+		p, err = Skip(r)
 		if err != nil {
 			return b, err
 		}
-		p = p2
 	}
 	return p, nil
 }
@@ -1187,6 +1404,15 @@ func ReadMapNoDupBytes(b []byte) (o []byte, err error) {
 // ForEachSequenceBytes calls onItem for each CBOR item in a CBOR sequence buffer b.
 // The item passed to onItem is a slice referencing b containing exactly one item.
 func ForEachSequenceBytes(b []byte, onItem func(item []byte) error) error {
+	return ForEachSequenceBytesUntil(b, func(item []byte) (bool, error) {
+		return false, onItem(item)
+	})
+}
+
+// ForEachSequenceBytesUntil is like ForEachSequenceBytes, but onItem can
+// end the iteration early without an error by returning stop=true. Items
+// after the one that stopped the iteration are not checked.
+func ForEachSequenceBytesUntil(b []byte, onItem func(item []byte) (stop bool, err error)) error {
 	p := b
 	for len(p) > 0 {
 		r, err := Skip(p)
@@ -1194,7 +1420,8 @@ func ForEachSequenceBytes(b []byte, onItem func(item []byte) error) error {
 			return err
 		}
 		seg := p[:len(p)-len(r)]
-		if err := onItem(seg); err != nil {
+		stop, err := onItem(seg)
+		if err != nil || stop {
 			return err
 		}
 		p = r
@@ -1229,20 +1456,44 @@ func AppendSequenceFunc(b []byte, n int, fn func(i int) ([]byte, error)) ([]byte
 	return b, nil
 }
 
+// ReadOrderedMapOptions configures ReadOrderedMapBytesWithOptions.
+type ReadOrderedMapOptions struct {
+	// ZeroCopy returns pairs whose Key and Value alias b instead of a
+	// copy, avoiding the scratch buffer. b must outlive the returned
+	// pairs and must not be modified while they are in use.
+	ZeroCopy bool
+}
+
 // ReadOrderedMapBytes reads the next CBOR map (definite or indefinite) and
 // returns a slice of RawPair in the order they appeared on the wire.
 // Each Key and Value contains exactly one CBOR item (copied).
 func ReadOrderedMapBytes(b []byte) (pairs []RawPair, o []byte, err error) {
+	return ReadOrderedMapBytesWithOptions(b, ReadOrderedMapOptions{})
+}
+
+// ReadOrderedMapBytesWithOptions is like ReadOrderedMapBytes but uses
+// the given options.
+func ReadOrderedMapBytesWithOptions(b []byte, opts ReadOrderedMapOptions) (pairs []RawPair, o []byte, err error) {
 	if len(b) < 1 {
 		return nil, b, ErrShortBytes
 	}
 	if getMajorType(b[0]) != majorTypeMap {
 		return nil, b, badPrefix(majorTypeMap, getMajorType(b[0]))
 	}
+	// Items are appended into a shared scratch buffer and returned as
+	// subslices, unless they may alias b.
+	var scratch []byte
+	capture := func(item []byte) []byte {
+		if opts.ZeroCopy {
+			return item[:len(item):len(item)]
+		}
+		start := len(scratch)
+		scratch = append(scratch, item...)
+		return scratch[start:]
+	}
 	// Indefinite-length map
 	if getAddInfo(b[0]) == addInfoIndefinite {
 		p := b[1:]
-		var scratch []byte
 		for {
 			if len(p) < 1 {
 				return nil, b, ErrShortBytes
@@ -1255,20 +1506,13 @@ func ReadOrderedMapBytes(b []byte) (pairs []RawPair, o []byte, err error) {
 			if err != nil {
 				return nil, b, err
 			}
-			klen := len(p) - len(r1)
-			// Append key bytes into a shared scratch buffer and take a subslice
-			startK := len(scratch)
-			scratch = append(scratch, p[:klen]...)
-			kraw := scratch[startK:]
+			kraw := capture(p[:len(p)-len(r1)])
 			// Capture raw value
 			r2, err := Skip(r1)
 			if err != nil {
 				return nil, b, err
 			}
-			vlen := len(r1) - len(r2)
-			startV := len(scratch)
-			scratch = append(scratch, r1[:vlen]...)
-			vraw := scratch[startV:]
+			vraw := capture(r1[:len(r1)-len(r2)])
 			pairs = append(pairs, RawPair{Key: kraw, Value: vraw})
 			p = r2
 		}
@@ -1279,24 +1523,17 @@ func ReadOrderedMapBytes(b []byte) (pairs []RawPair, o []byte, err error) {
 		return nil, b, err
 	}
 	pairs = make([]RawPair, 0, sz)
-	var scratch []byte
 	for i := uint32(0); i < sz; i++ {
 		r1, err := Skip(p)
 		if err != nil {
 			return nil, b, err
 		}
-		klen := len(p) - len(r1)
-		startK := len(scratch)
-		scratch = append(scratch, p[:klen]...)
-		kraw := scratch[startK:]
+		kraw := capture(p[:len(p)-len(r1)])
 		r2, err := Skip(r1)
 		if err != nil {
 			return nil, b, err
 		}
-		vlen := len(r1) - len(r2)
-		startV := len(scratch)
-		scratch = append(scratch, r1[:vlen]...)
-		vraw := scratch[startV:]
+		vraw := capture(r1[:len(r1)-len(r2)])
 		pairs = append(pairs, RawPair{Key: kraw, Value: vraw})
 		p = r2
 	}
@@ -1345,6 +1582,27 @@ func ReadDurationBytes(b []byte) (d time.Duration, o []byte, err error) {
 	return time.Duration(i64), o, nil
 }
 
+// ReadDurationLenientBytes reads a time.Duration encoded either as int64
+// nanoseconds or as a text string accepted by time.ParseDuration, such as
+// those written for cbor:",string" fields.
+func ReadDurationLenientBytes(b []byte) (d time.Duration, o []byte, err error) {
+	if len(b) < 1 {
+		return 0, b, ErrShortBytes
+	}
+	if getMajorType(b[0]) != majorTypeText {
+		return ReadDurationBytes(b)
+	}
+	s, o, err := ReadStringBytes(b)
+	if err != nil {
+		return 0, b, err
+	}
+	d, err = time.ParseDuration(s)
+	if err != nil {
+		return 0, b, err
+	}
+	return d, o, nil
+}
+
 // ReadMapStrStrBytes reads a map[string]string
 func ReadMapStrStrBytes(b []byte, m map[string]string) (o []byte, err error) {
 	sz, o, err := ReadMapHeaderBytes(b)
@@ -1367,14 +1625,125 @@ func ReadMapStrStrBytes(b []byte, m map[string]string) (o []byte, err error) {
 	return o, nil
 }
 
+// ReadMapStrBoolBytes reads a map[string]bool
+func ReadMapStrBoolBytes(b []byte, m map[string]bool) (o []byte, err error) {
+	sz, o, err := ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+
+	for i := uint32(0); i < sz; i++ {
+		var key string
+		var val bool
+		key, o, err = ReadStringBytes(o)
+		if err != nil {
+			return b, err
+		}
+		val, o, err = ReadBoolBytes(o)
+		if err != nil {
+			return b, err
+		}
+		m[key] = val
+	}
+	return o, nil
+}
+
 // Skip skips over the next CBOR object
 func Skip(b []byte) ([]byte, error) {
 	return skip(b, 0)
 }
 
+// CountMapPairs returns the number of key/value pairs in b, a sequence
+// of pre-encoded map entries without a map header. It returns
+// ErrShortBytes if the sequence ends between a key and its value.
+func CountMapPairs(b []byte) (uint32, error) {
+	var items uint32
+	for len(b) > 0 {
+		var err error
+		b, err = Skip(b)
+		if err != nil {
+			return 0, err
+		}
+		items++
+	}
+	if items%2 != 0 {
+		return 0, ErrShortBytes
+	}
+	return items / 2, nil
+}
+
+// MapEntriesBytes splits b, a single encoded map, into its pair count
+// and the key/value entries following the header. Empty input yields no
+// entries, and bytes after the map are an error. Generated code uses it
+// to write a cbor:",fallthrough" field back into the enclosing map.
+func MapEntriesBytes(b []byte) (n uint32, entries []byte, err error) {
+	if len(b) == 0 {
+		return 0, nil, nil
+	}
+	n, entries, err = ReadMapHeaderBytes(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	o := entries
+	for i := uint64(0); i < 2*uint64(n); i++ {
+		o, err = Skip(o)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	if len(o) != 0 {
+		return 0, nil, errors.New("cbor: trailing bytes after map")
+	}
+	return n, entries[:len(entries)-len(o)], nil
+}
+
+// MatchMapKeys scans the text keys of the map at the start of b and
+// returns the index of the first set containing the first key, in map
+// order, that belongs to any set. It returns -1 when no key matches.
+// The map may be definite or indefinite-length.
+// Generated code uses it to pick the type of a cbor:",oneof" field.
+func MatchMapKeys(b []byte, sets ...[]string) (int, error) {
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return -1, err
+	}
+	for i := uint32(0); indefinite || i < sz; i++ {
+		if indefinite {
+			var done bool
+			o, done, err = ReadBreakBytes(o)
+			if err != nil {
+				return -1, err
+			}
+			if done {
+				break
+			}
+		}
+		if NextType(o) == StrType {
+			var key []byte
+			key, o, err = ReadStringZC(o)
+			if err != nil {
+				return -1, err
+			}
+			for si, set := range sets {
+				for _, k := range set {
+					if string(key) == k {
+						return si, nil
+					}
+				}
+			}
+		} else if o, err = Skip(o); err != nil {
+			return -1, err
+		}
+		if o, err = Skip(o); err != nil {
+			return -1, err
+		}
+	}
+	return -1, nil
+}
+
 func skip(b []byte, depth int) ([]byte, error) {
 	if depth > recursionLimit {
-		return b, ErrMaxDepthExceeded
+		return b, MaxDepthError{Depth: depth}
 	}
 	if len(b) < 1 {
 		return b, ErrShortBytes
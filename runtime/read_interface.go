@@ -0,0 +1,413 @@
+package cbor
+
+import (
+	"math"
+	bigmath "math/big"
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+// Tag is a semantic tag whose number ReadInterface does not recognize.
+// Content holds the decoded tag content. Tag implements Marshaler, so
+// values returned by ReadInterface can be re-encoded with AppendInterface.
+type Tag struct {
+	Number  uint64
+	Content any
+}
+
+// MarshalCBOR implements Marshaler.
+func (t Tag) MarshalCBOR(b []byte) ([]byte, error) {
+	b = AppendTag(b, t.Number)
+	return AppendInterface(b, t.Content)
+}
+
+// SimpleValue is a CBOR simple value other than false, true, null and
+// undefined, as returned by ReadInterface.
+type SimpleValue uint8
+
+// MarshalCBOR implements Marshaler.
+func (s SimpleValue) MarshalCBOR(b []byte) ([]byte, error) {
+	if reservedSimpleValue(uint8(s)) {
+		return b, ErrInvalidSimpleValue
+	}
+	return AppendSimpleValue(b, uint8(s)), nil
+}
+
+// CBORError is an error message decoded by ReadInterfaceWithOptions from
+// a text string carrying ReadInterfaceOptions.ErrorTag.
+type CBORError string
+
+// Error implements error.
+func (e CBORError) Error() string { return string(e) }
+
+// TagRegistry maps application-defined tag numbers to decode functions
+// used by ReadInterface. The zero value is an empty registry ready for
+// use, and it is safe for concurrent use.
+type TagRegistry struct {
+	mu       sync.RWMutex
+	decoders map[uint64]func(inner any) (any, error)
+}
+
+// DefaultTagRegistry is consulted by ReadInterface. Register handlers on
+// it at init time to make them available package-wide.
+var DefaultTagRegistry = &TagRegistry{}
+
+// Register installs decode as the handler for tag, replacing any previous
+// handler. decode receives the tag content as decoded by ReadInterface
+// and returns the value to substitute for the whole tagged item.
+func (r *TagRegistry) Register(tag uint64, decode func(inner any) (any, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.decoders == nil {
+		r.decoders = make(map[uint64]func(inner any) (any, error))
+	}
+	r.decoders[tag] = decode
+}
+
+func (r *TagRegistry) lookup(tag uint64) (func(inner any) (any, error), bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.decoders[tag]
+	return fn, ok
+}
+
+// ReadInterfaceOptions controls how ReadInterfaceWithOptions maps CBOR
+// items onto Go values.
+type ReadInterfaceOptions struct {
+	// Tags is consulted before the built-in tag handling. A nil registry
+	// uses the built-ins only.
+	Tags *TagRegistry
+	// PreserveNumbers returns integers and floats as Number, keeping the
+	// encoded kind (uint64, int64, float32 or float64) instead of mapping
+	// them to the Go types listed on ReadInterface. Negative integers
+	// below math.MinInt64 are still returned as *big.Int.
+	PreserveNumbers bool
+	// DecodeURIAsURL returns tag 32 URIs as *url.URL instead of string.
+	// A URI that does not parse is reported as an error.
+	DecodeURIAsURL bool
+	// DecodeTagsAsBigFloat returns tag 5 bigfloats as *big.Float instead
+	// of Tag.
+	DecodeTagsAsBigFloat bool
+	// ZeroCopyKeys returns definite-length text map keys as strings
+	// that alias b, without copying them or validating their UTF-8,
+	// which saves an allocation per key. It is meant for trusted input
+	// only, and b must not be modified while the result is in use.
+	ZeroCopyKeys bool
+	// RejectNull makes null and undefined anywhere in the item an
+	// ErrUnexpectedNull error instead of decoding them as nil, for
+	// strict callers that do not accept absent values.
+	RejectNull bool
+	// DecodeComplexArrays returns two-element arrays whose elements are
+	// both floats, as written by AppendComplex128, as complex128 instead
+	// of []any.
+	DecodeComplexArrays bool
+	// ErrorTag, when non-zero, returns text strings carrying this tag
+	// as CBORError instead of Tag. Tag content other than a text string
+	// still decodes as Tag. The option takes precedence over Tags.
+	ErrorTag uint64
+}
+
+// ReadInterface decodes a single CBOR item from b into a generic Go value,
+// consulting DefaultTagRegistry for tags. Items map onto Go types as
+// follows:
+//
+//   - unsigned integers: uint64
+//   - negative integers: int64, or *big.Int below math.MinInt64
+//   - byte strings: []byte (copied)
+//   - text strings: string
+//   - arrays: []any, or complex128 for a pair of floats with
+//     DecodeComplexArrays
+//   - maps: map[string]any when every key is a text string, otherwise
+//     map[any]any
+//   - false/true: bool; null/undefined: nil, or ErrUnexpectedNull with
+//     RejectNull
+//   - half and single precision floats: float32; double: float64
+//   - tag 0: time.Time when the text is RFC 3339; tag 1: time.Time;
+//     tags 2 and 3: *big.Int; tag 5: *big.Float with
+//     DecodeTagsAsBigFloat; tag 32: string;
+//     tag 35: *regexp.Regexp when the pattern compiles; ErrorTag:
+//     CBORError; other tags: Tag
+//   - other simple values: SimpleValue
+func ReadInterface(b []byte) (v any, o []byte, err error) {
+	return ReadInterfaceWithOptions(b, ReadInterfaceOptions{Tags: DefaultTagRegistry})
+}
+
+// ReadInterfaceWithRegistry is like ReadInterface but consults reg instead
+// of DefaultTagRegistry. Tags not registered in reg fall through to the
+// built-in handling.
+func ReadInterfaceWithRegistry(b []byte, reg *TagRegistry) (v any, o []byte, err error) {
+	return ReadInterfaceWithOptions(b, ReadInterfaceOptions{Tags: reg})
+}
+
+// ReadInterfaceWithOptions is like ReadInterface but uses the given options.
+func ReadInterfaceWithOptions(b []byte, opts ReadInterfaceOptions) (v any, o []byte, err error) {
+	return readInterface(b, &opts, 0)
+}
+
+func readInterface(b []byte, opts *ReadInterfaceOptions, depth int) (any, []byte, error) {
+	if depth > recursionLimit {
+		return nil, b, MaxDepthError{Depth: depth}
+	}
+	if len(b) < 1 {
+		return nil, b, ErrShortBytes
+	}
+	switch getMajorType(b[0]) {
+	case majorTypeUint:
+		u, o, err := ReadUint64Bytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		if opts.PreserveNumbers {
+			var n Number
+			n.AsUint(u)
+			return n, o, nil
+		}
+		return u, o, nil
+	case majorTypeNegInt:
+		u, o, err := readUintCore(b, majorTypeNegInt)
+		if err != nil {
+			return nil, b, err
+		}
+		if u > math.MaxInt64 {
+			z := new(bigmath.Int).SetUint64(u)
+			z.Add(z, bigmath.NewInt(1))
+			return z.Neg(z), o, nil
+		}
+		if opts.PreserveNumbers {
+			var n Number
+			n.AsInt(-1 - int64(u))
+			return n, o, nil
+		}
+		return -1 - int64(u), o, nil
+	case majorTypeBytes:
+		return ReadBytesCopy(b)
+	case majorTypeText:
+		return ReadStringBytes(b)
+	case majorTypeArray:
+		return readInterfaceArray(b, opts, depth)
+	case majorTypeMap:
+		return readInterfaceMap(b, opts, depth)
+	case majorTypeTag:
+		return readInterfaceTag(b, opts, depth)
+	default:
+		return readInterfaceSimple(b, opts)
+	}
+}
+
+func readInterfaceArray(b []byte, opts *ReadInterfaceOptions, depth int) (any, []byte, error) {
+	if opts.DecodeComplexArrays && isComplexArray(b) {
+		return ReadComplex128Bytes(b)
+	}
+	sz, indefinite, o, err := ReadArrayStartBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	// Every element takes at least one byte; don't trust sz beyond that.
+	out := make([]any, 0, int(min(uint64(sz), uint64(len(o)))))
+	for i := uint32(0); indefinite || i < sz; i++ {
+		if indefinite {
+			var done bool
+			o, done, err = ReadBreakBytes(o)
+			if err != nil {
+				return nil, b, err
+			}
+			if done {
+				break
+			}
+		}
+		var elem any
+		elem, o, err = readInterface(o, opts, depth+1)
+		if err != nil {
+			return nil, b, err
+		}
+		out = append(out, elem)
+	}
+	return out, o, nil
+}
+
+// isComplexArray reports whether b starts with a definite two-element
+// array of floats.
+func isComplexArray(b []byte) bool {
+	if len(b) < 1 || b[0] != 0x82 {
+		return false
+	}
+	b = b[1:]
+	for range 2 {
+		if len(b) < 1 || b[0] < 0xf9 || b[0] > 0xfb {
+			return false
+		}
+		// 0xf9, 0xfa and 0xfb are followed by 2, 4 and 8 bytes.
+		n := 1 + 1<<(b[0]-0xf8)
+		if len(b) < n {
+			return false
+		}
+		b = b[n:]
+	}
+	return true
+}
+
+func readInterfaceMap(b []byte, opts *ReadInterfaceOptions, depth int) (any, []byte, error) {
+	sz, indefinite, o, err := ReadMapStartBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	// Every entry takes at least two bytes; don't trust sz beyond that.
+	strMap := make(map[string]any, int(min(uint64(sz), uint64(len(o)/2))))
+	var anyMap map[any]any
+	for i := uint32(0); indefinite || i < sz; i++ {
+		if indefinite {
+			var done bool
+			o, done, err = ReadBreakBytes(o)
+			if err != nil {
+				return nil, b, err
+			}
+			if done {
+				break
+			}
+		}
+		var k, val any
+		if opts.ZeroCopyKeys && len(o) > 0 && o[0] >= 0x60 && o[0] <= 0x7b {
+			var kb []byte
+			kb, o, err = ReadStringZC(o)
+			k = UnsafeString(kb)
+		} else {
+			k, o, err = readInterface(o, opts, depth+1)
+		}
+		if err != nil {
+			return nil, b, err
+		}
+		val, o, err = readInterface(o, opts, depth+1)
+		if err != nil {
+			return nil, b, err
+		}
+		if ks, ok := k.(string); ok && anyMap == nil {
+			strMap[ks] = val
+			continue
+		}
+		if !reflect.ValueOf(k).Comparable() {
+			return nil, b, &ErrUnsupportedType{T: reflect.TypeOf(k), ctx: "map key"}
+		}
+		if anyMap == nil {
+			// First non-string key: switch to map[any]any.
+			anyMap = make(map[any]any, len(strMap)+1)
+			for ks, kv := range strMap {
+				anyMap[ks] = kv
+			}
+		}
+		anyMap[k] = val
+	}
+	if anyMap != nil {
+		return anyMap, o, nil
+	}
+	return strMap, o, nil
+}
+
+func readInterfaceTag(b []byte, opts *ReadInterfaceOptions, depth int) (any, []byte, error) {
+	tag, o, err := ReadTagBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if opts.ErrorTag != 0 && tag == opts.ErrorTag {
+		if s, rest, err := ReadStringBytes(o); err == nil {
+			return CBORError(s), rest, nil
+		}
+	}
+	if fn, ok := opts.Tags.lookup(tag); ok {
+		inner, o, err := readInterface(o, opts, depth+1)
+		if err != nil {
+			return nil, b, err
+		}
+		v, err := fn(inner)
+		if err != nil {
+			return nil, b, err
+		}
+		return v, o, nil
+	}
+	switch tag {
+	case tagDateTimeString:
+		// Text that is not RFC 3339 falls through to Tag, as for
+		// regexps below.
+		if t, o, err := ReadRFC3339TimeBytes(b); err == nil {
+			return t, o, nil
+		}
+	case tagEpochDateTime:
+		return ReadTimeBytes(b)
+	case tagBigfloat:
+		if opts.DecodeTagsAsBigFloat {
+			return ReadBigfloatAsFloatBytes(b)
+		}
+	case tagPosBignum, tagNegBignum:
+		return ReadBigIntBytes(b)
+	case tagURI:
+		s, o, err := ReadURIStringBytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		if !opts.DecodeURIAsURL {
+			return s, o, nil
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, b, err
+		}
+		return u, o, nil
+	case tagRegexp:
+		// Patterns Go cannot compile, such as PCRE lookarounds, are
+		// still well-formed CBOR and fall through to Tag.
+		if re, o, err := ReadRegexpBytes(b); err == nil {
+			return re, o, nil
+		}
+	}
+	inner, o, err := readInterface(o, opts, depth+1)
+	if err != nil {
+		return nil, b, err
+	}
+	return Tag{Number: tag, Content: inner}, o, nil
+}
+
+func readInterfaceSimple(b []byte, opts *ReadInterfaceOptions) (any, []byte, error) {
+	switch getAddInfo(b[0]) {
+	case simpleFalse, simpleTrue:
+		return ReadBoolBytes(b)
+	case simpleNull, simpleUndefined:
+		if opts.RejectNull {
+			return nil, b, ErrUnexpectedNull
+		}
+		return nil, b[1:], nil
+	case simpleFloat16, simpleFloat32:
+		read := ReadFloat32Bytes
+		if getAddInfo(b[0]) == simpleFloat16 {
+			read = ReadFloat16Bytes
+		}
+		f, o, err := read(b)
+		if err != nil {
+			return nil, b, err
+		}
+		if opts.PreserveNumbers {
+			var n Number
+			n.AsFloat32(f)
+			return n, o, nil
+		}
+		return f, o, nil
+	case simpleFloat64:
+		f, o, err := ReadFloat64Bytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		if opts.PreserveNumbers {
+			var n Number
+			n.AsFloat64(f)
+			return n, o, nil
+		}
+		return f, o, nil
+	}
+	sv, o, err := ReadSimpleValue(b)
+	if err != nil {
+		return nil, b, err
+	}
+	return SimpleValue(sv), o, nil
+}
@@ -104,6 +104,21 @@ func (r *Reader) ReadMapHeader() (uint32, error) {
 	return sz, nil
 }
 
+// ReadMapStart reads a map start and indicates whether it is
+// indefinite-length. When deterministic decoding is enabled,
+// indefinite-length maps are rejected with ErrIndefiniteForbidden.
+func (r *Reader) ReadMapStart() (sz uint32, indefinite bool, err error) {
+	sz, indef, rest, err := ReadMapStartBytes(r.buf)
+	if err != nil {
+		return 0, false, err
+	}
+	if indef && r.deterministic {
+		return 0, false, ErrIndefiniteForbidden
+	}
+	r.buf = rest
+	return sz, indef, nil
+}
+
 // ReadString reads a text string and advances the buffer.
 // In strict mode, non-canonical length encodings are rejected.
 // In deterministic mode, indefinite-length strings are forbidden.
@@ -203,6 +218,22 @@ func (r *Reader) ReadBool() (bool, error) {
 	return v, nil
 }
 
+// ReadSimpleValue reads a simple value and advances the buffer.
+// In strict mode, it rejects the two-byte form for values below 32,
+// including 24..27 whose one-byte forms are taken by the float and
+// uint8 prefixes.
+func (r *Reader) ReadSimpleValue() (uint8, error) {
+	v, rest, err := ReadSimpleValue(r.buf)
+	if err != nil {
+		return 0, err
+	}
+	if r.strict && getAddInfo(r.buf[0]) == addInfoUint8 && v < 32 {
+		return 0, ErrInvalidSimpleValue
+	}
+	r.buf = rest
+	return v, nil
+}
+
 // ReadInt64 reads an int64 and advances the buffer.
 // In strict mode, it enforces canonical integer encodings for both
 // positive and negative values.
@@ -276,6 +307,23 @@ func (r *Reader) ReadUint64() (uint64, error) {
 	return v, nil
 }
 
+// ReadFloat16 reads a half-precision float and advances the buffer.
+// Half precision is already the shortest float encoding, so strict mode
+// only rejects values that have a different canonical form, such as -0
+// or a NaN other than 0xf97e00.
+func (r *Reader) ReadFloat16() (float32, error) {
+	orig := r.buf
+	v, rest, err := ReadFloat16Bytes(r.buf)
+	if err != nil {
+		return 0, err
+	}
+	if r.strict && !bytes.Equal(orig[:3], AppendFloatCanonical(nil, float64(v))) {
+		return 0, ErrNonCanonicalFloat
+	}
+	r.buf = rest
+	return v, nil
+}
+
 // ReadFloat32 reads a float32 and advances the buffer.
 func (r *Reader) ReadFloat32() (float32, error) {
 	orig := r.buf
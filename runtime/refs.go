@@ -0,0 +1,193 @@
+package cbor
+
+import "unsafe"
+
+// EncodeRefs records the pointers already written by AppendRef, so later
+// occurrences can be encoded as references. The zero value is ready for
+// use. One table spans a whole document, so reference IDs are global to
+// it; a table must not be shared between unrelated messages.
+type EncodeRefs struct {
+	ids map[unsafe.Pointer]uint64
+}
+
+// DecodeRefs holds the values decoded by ReadRef in the order they were
+// marked shareable, indexed by reference ID. The zero value is ready for
+// use.
+type DecodeRefs struct {
+	values []any
+}
+
+// RefMarshaler is implemented by generated types that take part in value
+// sharing. MarshalCBORRefs encodes like MarshalCBOR but records and
+// resolves shared pointers in refs, the table of the enclosing document,
+// instead of starting a table of its own.
+type RefMarshaler interface {
+	MarshalCBORRefs(b []byte, refs *EncodeRefs) ([]byte, error)
+}
+
+// RefUnmarshaler is the decoding counterpart of RefMarshaler.
+type RefUnmarshaler interface {
+	UnmarshalCBORRefs(b []byte, refs *DecodeRefs) ([]byte, error)
+}
+
+// AppendRef appends v using the value-sharing tags. The first time a
+// pointer is seen it is written as tag(28) around v's encoding and given
+// the next reference ID; later occurrences are written as tag(29) holding
+// that ID. A nil v is written as null. A v implementing RefMarshaler is
+// encoded with refs, so pointers nested inside it, including ones back to
+// a value being encoded, share the same IDs.
+func AppendRef[T any, P interface {
+	*T
+	Marshaler
+}](b []byte, refs *EncodeRefs, v P) ([]byte, error) {
+	if v == nil {
+		return AppendNil(b), nil
+	}
+	key := unsafe.Pointer(v)
+	if id, ok := refs.ids[key]; ok {
+		b = AppendTag(b, tagSharedRef)
+		return AppendUint64(b, id), nil
+	}
+	if refs.ids == nil {
+		refs.ids = make(map[unsafe.Pointer]uint64)
+	}
+	refs.ids[key] = uint64(len(refs.ids))
+	b = AppendTag(b, tagShareable)
+	if m, ok := any(v).(RefMarshaler); ok {
+		return m.MarshalCBORRefs(b, refs)
+	}
+	return v.MarshalCBOR(b)
+}
+
+// AppendPtrRefs is AppendPtrMarshaler for types implementing
+// RefMarshaler: a non-nil v is encoded with MarshalCBORRefs so it shares
+// refs with the enclosing document.
+func AppendPtrRefs[T any, P interface {
+	*T
+	RefMarshaler
+}](b []byte, refs *EncodeRefs, v P) ([]byte, error) {
+	if v == nil {
+		return AppendNil(b), nil
+	}
+	return v.MarshalCBORRefs(b, refs)
+}
+
+// AppendRefSlice appends s as an array whose elements are written with
+// AppendRef. A nil s is written as null.
+func AppendRefSlice[T any, P interface {
+	*T
+	Marshaler
+}](b []byte, refs *EncodeRefs, s []P) ([]byte, error) {
+	if s == nil {
+		return AppendNil(b), nil
+	}
+	b = AppendArrayHeader(b, uint32(len(s)))
+	var err error
+	for _, v := range s {
+		b, err = AppendRef[T](b, refs, v)
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// ReadRef reads a value written by AppendRef. A tag(28) value is
+// decoded into a new T and recorded in refs before its content is read;
+// a tag(29) reference returns the recorded value with that ID, or
+// ErrInvalidReference if there is none of type *T. Untagged values are
+// decoded without being recorded, and null yields nil. A *T implementing
+// RefUnmarshaler is decoded with refs, as AppendRef encodes it.
+func ReadRef[T any, P interface {
+	*T
+	Unmarshaler
+}](b []byte, refs *DecodeRefs) (P, []byte, error) {
+	if IsNil(b) {
+		return nil, b[1:], nil
+	}
+	if len(b) > 0 && getMajorType(b[0]) == majorTypeTag {
+		tag, o, err := ReadTagBytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		switch tag {
+		case tagSharedRef:
+			id, o, err := ReadUint64Bytes(o)
+			if err != nil {
+				return nil, b, err
+			}
+			if id >= uint64(len(refs.values)) {
+				return nil, b, ErrInvalidReference
+			}
+			v, ok := refs.values[id].(P)
+			if !ok {
+				return nil, b, ErrInvalidReference
+			}
+			return v, o, nil
+		case tagShareable:
+			v := P(new(T))
+			refs.values = append(refs.values, v)
+			o, err = unmarshalRefs(v, o, refs)
+			if err != nil {
+				return nil, b, err
+			}
+			return v, o, nil
+		}
+	}
+	v := P(new(T))
+	o, err := unmarshalRefs(v, b, refs)
+	if err != nil {
+		return nil, b, err
+	}
+	return v, o, nil
+}
+
+// unmarshalRefs decodes b into v, with refs when v implements
+// RefUnmarshaler.
+func unmarshalRefs(v Unmarshaler, b []byte, refs *DecodeRefs) ([]byte, error) {
+	if u, ok := v.(RefUnmarshaler); ok {
+		return u.UnmarshalCBORRefs(b, refs)
+	}
+	return v.UnmarshalCBOR(b)
+}
+
+// ReadRefSlice reads an array written by AppendRefSlice, reusing dst's
+// backing array when it is large enough. Null yields a nil slice, and a
+// length longer than the remaining input yields ErrShortBytes.
+func ReadRefSlice[T any, P interface {
+	*T
+	Unmarshaler
+}](b []byte, refs *DecodeRefs, dst []P) ([]P, []byte, error) {
+	if IsNil(b) {
+		return nil, b[1:], nil
+	}
+	sz, o, err := ReadArrayHeaderBytes(b)
+	if err != nil {
+		return dst, b, err
+	}
+	// Every element takes at least one byte, so a longer header cannot
+	// be satisfied. Checking first also keeps int(sz) from wrapping on
+	// 32-bit platforms.
+	if uint64(sz) > uint64(len(o)) {
+		return dst, b, ErrShortBytes
+	}
+	n := int(sz)
+	if cap(dst) >= n {
+		dst = dst[:n]
+	} else {
+		dst = make([]P, 0, n)
+	}
+	for i := 0; i < n; i++ {
+		var v P
+		v, o, err = ReadRef[T, P](o, refs)
+		if err != nil {
+			return dst, b, err
+		}
+		if i < len(dst) {
+			dst[i] = v
+		} else {
+			dst = append(dst, v)
+		}
+	}
+	return dst, o, nil
+}
@@ -1,28 +1,112 @@
 package cbor
 
+import "math"
+
 // Worst-case encoded sizes for common types. For variable-length types
 // such as strings and byte slices, the total encoded size is the
 // corresponding prefix size plus the length of the value.
 const (
-	Int64Size           = 9
-	IntSize             = Int64Size
-	UintSize            = Int64Size
-	Int8Size            = 2
-	Int16Size           = 3
-	Int32Size           = 5
-	Uint8Size           = 2
-	Uint16Size          = 3
-	Uint32Size          = 5
-	Uint64Size          = Int64Size
-	Float64Size         = 9
-	Float32Size         = 5
-	DurationSize        = Int64Size
-	TimeSize            = 15
-	BoolSize            = 1
-	NilSize             = 1
-	MapHeaderSize       = 5
-	ArrayHeaderSize     = 5
-	BytesPrefixSize     = 5
-	StringPrefixSize    = 5
-	ExtensionPrefixSize = 6
+	Int64Size            = 9
+	IntSize              = Int64Size
+	UintSize             = Int64Size
+	Int8Size             = 2
+	Int16Size            = 3
+	Int32Size            = 5
+	Uint8Size            = 2
+	Uint16Size           = 3
+	Uint32Size           = 5
+	Uint64Size           = Int64Size
+	Float64Size          = 9
+	Float32Size          = 5
+	DurationSize         = Int64Size
+	TimeSize             = 15
+	RFC3339TimeSize      = 38 // tag 0 + up to 35 characters of RFC 3339 text
+	BoolSize             = 1
+	NilSize              = 1
+	MapHeaderSize        = 5
+	ArrayHeaderSize      = 5
+	BytesPrefixSize      = 5
+	StringPrefixSize     = 5
+	ExtensionPrefixSize  = 6
+	IPSize               = 20 // tag 260 + 16-byte IPv6 address
+	HardwareAddrSize     = 12 // tag 260 + 8-byte EUI-64 address
+	UUIDSize             = 19 // tag 37 + 16-byte UUID
+	TypedArrayPrefixSize = 7  // tag 64-87 + byte string header
 )
+
+// Sizer is implemented by types that report a worst-case encoded size,
+// such as those generated by cborgen.
+type Sizer interface {
+	Msgsize() int
+}
+
+// AddSize returns a+b for non-negative sizes a and b, clamped at
+// math.MaxInt instead of overflowing. Generated Msgsize methods use it
+// to accumulate their fields' sizes.
+func AddSize(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// PtrMsgsize returns the worst-case encoded size of *v, or NilSize if v
+// is nil.
+func PtrMsgsize[T Sizer](v *T) int {
+	if v == nil {
+		return NilSize
+	}
+	return (*v).Msgsize()
+}
+
+// SliceMsgsize returns the worst-case encoded size of s as a CBOR array.
+func SliceMsgsize[T Sizer](s []T) int {
+	n := ArrayHeaderSize
+	for i := range s {
+		n = AddSize(n, s[i].Msgsize())
+	}
+	return n
+}
+
+// PtrSliceMsgsize returns the worst-case encoded size of s as a CBOR
+// array, counting nil elements as NilSize.
+func PtrSliceMsgsize[T Sizer](s []*T) int {
+	n := ArrayHeaderSize
+	for _, v := range s {
+		n = AddSize(n, PtrMsgsize(v))
+	}
+	return n
+}
+
+// StringsLen returns the total length of the strings in s. Generated
+// Msgsize methods add it to the per-element StringPrefixSize of a
+// []string field.
+func StringsLen(s []string) int {
+	n := 0
+	for _, v := range s {
+		n = AddSize(n, len(v))
+	}
+	return n
+}
+
+// MapKeysLen returns the total length of the keys of m, which generated
+// Msgsize methods add to the per-entry StringPrefixSize of a
+// map[string]V field.
+func MapKeysLen[V any](m map[string]V) int {
+	n := 0
+	for k := range m {
+		n = AddSize(n, len(k))
+	}
+	return n
+}
+
+// MapValuesLen returns the total length of the values of m, which
+// generated Msgsize methods add to the per-entry StringPrefixSize of a
+// map[K]string field.
+func MapValuesLen[K comparable](m map[K]string) int {
+	n := 0
+	for _, v := range m {
+		n = AddSize(n, len(v))
+	}
+	return n
+}
@@ -0,0 +1,121 @@
+package cbor
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// RFC 8746 typed array tags for unsigned integers.
+const (
+	tagTypedUint16BE = 65
+	tagTypedUint32BE = 66
+	tagTypedUint64BE = 67
+	tagTypedUint16LE = 69
+	tagTypedUint32LE = 70
+	tagTypedUint64LE = 71
+)
+
+// AppendTypedArrayUint16 appends v as an RFC 8746 typed array: tag(65)
+// around a byte string of 2-byte big-endian values.
+func AppendTypedArrayUint16(b []byte, v []uint16) []byte {
+	b = AppendTag(b, tagTypedUint16BE)
+	b = appendUintCore(b, majorTypeBytes, uint64(len(v))*2)
+	for _, u := range v {
+		b = binary.BigEndian.AppendUint16(b, u)
+	}
+	return b
+}
+
+// AppendTypedArrayUint32 appends v as an RFC 8746 typed array: tag(66)
+// around a byte string of 4-byte big-endian values.
+func AppendTypedArrayUint32(b []byte, v []uint32) []byte {
+	b = AppendTag(b, tagTypedUint32BE)
+	b = appendUintCore(b, majorTypeBytes, uint64(len(v))*4)
+	for _, u := range v {
+		b = binary.BigEndian.AppendUint32(b, u)
+	}
+	return b
+}
+
+// AppendTypedArrayUint64 appends v as an RFC 8746 typed array: tag(67)
+// around a byte string of 8-byte big-endian values.
+func AppendTypedArrayUint64(b []byte, v []uint64) []byte {
+	b = AppendTag(b, tagTypedUint64BE)
+	b = appendUintCore(b, majorTypeBytes, uint64(len(v))*8)
+	for _, u := range v {
+		b = binary.BigEndian.AppendUint64(b, u)
+	}
+	return b
+}
+
+// ReadTypedArrayUint16Bytes reads a []uint16 written either as a typed
+// array (tag 65 or 69) or as a plain array of unsigned integers.
+func ReadTypedArrayUint16Bytes(b []byte) (v []uint16, o []byte, err error) {
+	return readTypedArrayBytes(b, 2, tagTypedUint16BE, tagTypedUint16LE,
+		binary.BigEndian.Uint16, binary.LittleEndian.Uint16, ReadUint16Bytes)
+}
+
+// ReadTypedArrayUint32Bytes reads a []uint32 written either as a typed
+// array (tag 66 or 70) or as a plain array of unsigned integers.
+func ReadTypedArrayUint32Bytes(b []byte) (v []uint32, o []byte, err error) {
+	return readTypedArrayBytes(b, 4, tagTypedUint32BE, tagTypedUint32LE,
+		binary.BigEndian.Uint32, binary.LittleEndian.Uint32, ReadUint32Bytes)
+}
+
+// ReadTypedArrayUint64Bytes reads a []uint64 written either as a typed
+// array (tag 67 or 71) or as a plain array of unsigned integers.
+func ReadTypedArrayUint64Bytes(b []byte) (v []uint64, o []byte, err error) {
+	return readTypedArrayBytes(b, 8, tagTypedUint64BE, tagTypedUint64LE,
+		binary.BigEndian.Uint64, binary.LittleEndian.Uint64, ReadUint64Bytes)
+}
+
+// readTypedArrayBytes implements the ReadTypedArrayUintNBytes functions.
+// size is the element width in bytes; be and le decode one element of
+// the big- and little-endian typed array forms, and elem reads one
+// element of the plain array form.
+func readTypedArrayBytes[T uint16 | uint32 | uint64](b []byte, size int, tagBE, tagLE uint64,
+	be, le func([]byte) T, elem func([]byte) (T, []byte, error)) ([]T, []byte, error) {
+	if NextType(b) == ArrayType {
+		sz, o, err := ReadArrayHeaderBytes(b)
+		if err != nil {
+			return nil, b, err
+		}
+		// Every element takes at least one byte.
+		if uint64(sz) > uint64(len(o)) {
+			return nil, b, ErrShortBytes
+		}
+		v := make([]T, sz)
+		for i := range v {
+			v[i], o, err = elem(o)
+			if err != nil {
+				return nil, b, err
+			}
+		}
+		return v, o, nil
+	}
+	tag, o, err := ReadTagBytes(b)
+	if err != nil {
+		return nil, b, err
+	}
+	var get func([]byte) T
+	switch tag {
+	case tagBE:
+		get = be
+	case tagLE:
+		get = le
+	default:
+		return nil, b, InvalidTagError{Want: []uint64{tagBE, tagLE}, Got: tag}
+	}
+	bs, o, err := ReadBytesBytes(o, nil)
+	if err != nil {
+		return nil, b, err
+	}
+	if len(bs)%size != 0 {
+		return nil, b, errors.New("cbor: typed array length is not a multiple of the element size")
+	}
+	v := make([]T, len(bs)/size)
+	for i := range v {
+		v[i] = get(bs[i*size:])
+	}
+	return v, o, nil
+}
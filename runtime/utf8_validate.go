@@ -5,3 +5,16 @@ import "unicode/utf8"
 // isUTF8Valid validates UTF-8 for a byte slice. It can be overridden by
 // architecture-specific, SIMD-accelerated implementations via build tags.
 var isUTF8Valid = func(b []byte) bool { return utf8.Valid(b) }
+
+// invalidUTF8Offset returns the byte offset of the first invalid UTF-8
+// sequence in b, or -1 if b is valid.
+func invalidUTF8Offset(b []byte) int {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
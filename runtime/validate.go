@@ -12,19 +12,40 @@ func ValidateWellFormedBytes(b []byte) (rest []byte, err error) {
 
 // ValidateDocument validates that all items in b are well-formed until input is exhausted.
 func ValidateDocument(b []byte) error {
-	var err error
-	for len(b) > 0 {
-		b, err = validateWellFormed(b, 0)
+	_, err := ValidateDocumentWithOptions(b, ValidateDocumentOptions{})
+	return err
+}
+
+// ValidateDocumentOptions controls ValidateDocumentWithOptions.
+type ValidateDocumentOptions struct {
+	// AllowTrailing stops at the first item that is not well-formed,
+	// such as padding appended by a transport, instead of reporting it
+	// as an error. Padding that happens to be well-formed CBOR, like
+	// 0x00 bytes (the integer 0), is still validated as items.
+	AllowTrailing bool
+}
+
+// ValidateDocumentWithOptions validates the items in b like
+// ValidateDocument and returns the number of leading bytes that hold
+// well-formed items. Without AllowTrailing, bytes after them are
+// reported through err.
+func ValidateDocumentWithOptions(b []byte, opts ValidateDocumentOptions) (consumed int, err error) {
+	for consumed < len(b) {
+		rest, err := validateWellFormed(b[consumed:], 0)
 		if err != nil {
-			return err
+			if opts.AllowTrailing {
+				return consumed, nil
+			}
+			return consumed, err
 		}
+		consumed = len(b) - len(rest)
 	}
-	return nil
+	return consumed, nil
 }
 
 func validateWellFormed(b []byte, depth int) ([]byte, error) {
 	if depth > recursionLimit {
-		return b, ErrMaxDepthExceeded
+		return b, MaxDepthError{Depth: depth}
 	}
 	if len(b) < 1 {
 		return b, ErrShortBytes
@@ -134,6 +155,17 @@ func validateWellFormed(b []byte, depth int) ([]byte, error) {
 			return b, err
 		}
 		for i := uint64(0); i < sz; i++ {
+			// Integers, the bulk of large numeric arrays, are skipped
+			// inline rather than through a recursive call.
+			if len(p) > 0 {
+				if n := intItemSize(p[0]); n > 0 {
+					if len(p) < n {
+						return b, ErrShortBytes
+					}
+					p = p[n:]
+					continue
+				}
+			}
 			p, err = validateWellFormed(p, depth+1)
 			if err != nil {
 				return b, err
@@ -211,3 +243,25 @@ func validateWellFormed(b []byte, depth int) ([]byte, error) {
 	}
 	return b, &ErrUnsupportedType{}
 }
+
+// intItemSize returns the encoded size of an integer item (major type 0
+// or 1) that starts with lead, or 0 if lead does not start one.
+func intItemSize(lead byte) int {
+	major := getMajorType(lead)
+	if major != majorTypeUint && major != majorTypeNegInt {
+		return 0
+	}
+	switch add := getAddInfo(lead); {
+	case add < addInfoUint8:
+		return 1
+	case add == addInfoUint8:
+		return 2
+	case add == addInfoUint16:
+		return 3
+	case add == addInfoUint32:
+		return 5
+	case add == addInfoUint64:
+		return 9
+	}
+	return 0
+}
@@ -3,11 +3,16 @@ package cbor
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"math"
 	bigmath "math/big"
+	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"time"
 )
@@ -62,6 +67,18 @@ func AppendArrayHeader(b []byte, sz uint32) []byte {
 	return appendUintCore(b, majorTypeArray, uint64(sz))
 }
 
+// AppendMapHeader64 appends a map header with a size in the full uint64
+// range, using the 8-byte length form above math.MaxUint32.
+func AppendMapHeader64(b []byte, sz uint64) []byte {
+	return appendUintCore(b, majorTypeMap, sz)
+}
+
+// AppendArrayHeader64 appends an array header with a size in the full
+// uint64 range, using the 8-byte length form above math.MaxUint32.
+func AppendArrayHeader64(b []byte, sz uint64) []byte {
+	return appendUintCore(b, majorTypeArray, sz)
+}
+
 // AppendArrayHeaderIndefinite appends an indefinite-length array header (0x9f)
 func AppendArrayHeaderIndefinite(b []byte) []byte {
 	return append(b, makeByte(majorTypeArray, addInfoIndefinite))
@@ -109,6 +126,22 @@ func AppendFloat32(b []byte, f float32) []byte {
 	return o
 }
 
+// AppendComplex128 appends c as a two-element array of float64s holding
+// its real and imaginary parts. CBOR has no complex number type; see
+// ReadComplex128Bytes.
+func AppendComplex128(b []byte, c complex128) []byte {
+	b = AppendArrayHeader(b, 2)
+	b = AppendFloat64(b, real(c))
+	return AppendFloat64(b, imag(c))
+}
+
+// AppendComplex64 is AppendComplex128 with float32 parts.
+func AppendComplex64(b []byte, c complex64) []byte {
+	b = AppendArrayHeader(b, 2)
+	b = AppendFloat32(b, real(c))
+	return AppendFloat32(b, imag(c))
+}
+
 // AppendFloatCanonical appends the shortest-width float (f16/f32/f64) that preserves the value.
 func AppendFloatCanonical(b []byte, f float64) []byte {
 	// Normalize -0 to +0 for canonical
@@ -117,7 +150,7 @@ func AppendFloatCanonical(b []byte, f float64) []byte {
 	}
 	// NaN: canonicalize to float16 NaN
 	if math.IsNaN(f) {
-		return AppendFloat16(b, float32(f))
+		return AppendNaN(b)
 	}
 	// Try f16
 	f16 := float32ToFloat16Bits(float32(f))
@@ -132,6 +165,16 @@ func AppendFloatCanonical(b []byte, f float64) []byte {
 	return AppendFloat64(b, f)
 }
 
+// NaNFloat16 is the canonical encoding of NaN, the float16 quiet NaN
+// 0xf97e00 (RFC 8949 §4.2.1).
+const NaNFloat16 = "\xf9\x7e\x00"
+
+// AppendNaN appends the canonical float16 NaN encoding, regardless of
+// the NaN payload or width a caller might otherwise use.
+func AppendNaN(b []byte) []byte {
+	return append(b, 0xf9, 0x7e, 0x00)
+}
+
 // AppendFloat16 appends a float16 (IEEE 754 binary16) encoded value
 func AppendFloat16(b []byte, f float32) []byte {
 	o, n := ensure(b, 3)
@@ -223,7 +266,9 @@ func AppendUint32(b []byte, u uint32) []byte {
 	return appendUintCore(b, majorTypeUint, uint64(u))
 }
 
-// AppendBytes appends a byte string
+// AppendBytes appends a byte string. Go lengths are never negative and
+// always fit the 8-byte length form, so every input has a valid header
+// and no error path is needed.
 func AppendBytes(b []byte, data []byte) []byte {
 	sz := uint64(len(data))
 	// Compute header size and reserve in one shot to avoid double ensure + copy
@@ -268,7 +313,8 @@ func AppendBytes(b []byte, data []byte) []byte {
 	return o[:n+int(sz)]
 }
 
-// AppendString appends a text string
+// AppendString appends a text string. As with AppendBytes, every Go
+// string length is representable in the header.
 func AppendString(b []byte, s string) []byte {
 	sz := uint64(len(s))
 	// Compute header size and reserve once
@@ -331,9 +377,12 @@ func AppendBool(b []byte, val bool) []byte {
 // AppendSimpleValue appends a generic simple value.
 // Values 0..23 are encoded in the additional information;
 // values 32..255 are encoded as 0xf8 XX.
-// Note: 24..27 are reserved for float encodings and are not handled here.
+// Values 24..31 have no well-formed encoding (RFC 8949 section 3.3), and
+// AppendSimpleValue panics on them.
 func AppendSimpleValue(b []byte, val uint8) []byte {
 	switch {
+	case reservedSimpleValue(val):
+		panic(fmt.Sprintf("cbor: simple value %d cannot be encoded", val))
 	case val <= addInfoDirect:
 		return append(b, makeByte(majorTypeSimple, val))
 	default:
@@ -344,6 +393,12 @@ func AppendSimpleValue(b []byte, val uint8) []byte {
 	}
 }
 
+// reservedSimpleValue reports whether val is one of the simple values
+// 24..31, which AppendSimpleValue cannot encode.
+func reservedSimpleValue(val uint8) bool {
+	return val > addInfoDirect && val < 32
+}
+
 // AppendTime appends a time.Time as CBOR tag 1 (epoch timestamp)
 func AppendTime(b []byte, t time.Time) []byte {
 	b = AppendTag(b, tagEpochDateTime)
@@ -356,6 +411,13 @@ func AppendTime(b []byte, t time.Time) []byte {
 	return AppendFloat64(b, f)
 }
 
+// AppendUnixMilli appends t as an untagged integer count of
+// milliseconds since the Unix epoch, truncating finer precision. CBOR
+// has no tag for this form, but it is common in JSON-derived schemas.
+func AppendUnixMilli(b []byte, t time.Time) []byte {
+	return AppendInt64(b, t.UnixMilli())
+}
+
 // AppendTag appends a generic semantic tag
 func AppendTag(b []byte, tag uint64) []byte {
 	return appendUintCore(b, majorTypeTag, tag)
@@ -385,12 +447,37 @@ func AppendBase64String(b []byte, s string) []byte {
 	return AppendString(b, s)
 }
 
+// AppendHexString appends data as a text string holding its lowercase
+// hex encoding, e.g. "0a1b" for []byte{0x0a, 0x1b}.
+func AppendHexString(b []byte, data []byte) []byte {
+	b = appendUintCore(b, majorTypeText, uint64(hex.EncodedLen(len(data))))
+	return hex.AppendEncode(b, data)
+}
+
 // AppendURI appends a tag(32) URI text string
 func AppendURI(b []byte, uri string) []byte {
 	b = AppendTag(b, tagURI)
 	return AppendString(b, uri)
 }
 
+// AppendURL appends u as a tag(32) URI text string, or null if u is
+// nil.
+func AppendURL(b []byte, u *url.URL) []byte {
+	if u == nil {
+		return AppendNil(b)
+	}
+	return AppendURI(b, u.String())
+}
+
+// AppendRaw appends raw, a pre-encoded CBOR data item, verbatim. An
+// empty raw is written as null so the output stays well-formed.
+func AppendRaw(b []byte, raw []byte) []byte {
+	if len(raw) == 0 {
+		return AppendNil(b)
+	}
+	return append(b, raw...)
+}
+
 // AppendEmbeddedCBOR appends tag(24) with a byte string containing embedded CBOR payload
 func AppendEmbeddedCBOR(b []byte, payload []byte) []byte {
 	b = AppendTag(b, tagCBOR)
@@ -403,6 +490,29 @@ func AppendUUID(b []byte, uuid [16]byte) []byte {
 	return AppendBytes(b, uuid[:])
 }
 
+// AppendIP appends tag(260) with an IP address as byte string. IPv4
+// addresses are written in their 4-byte form; a nil ip is written as null.
+func AppendIP(b []byte, ip net.IP) []byte {
+	if ip == nil {
+		return AppendNil(b)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	b = AppendTag(b, tagNetworkAddress)
+	return AppendBytes(b, ip)
+}
+
+// AppendHardwareAddr appends tag(260) with a MAC address as byte string.
+// A nil addr is written as null.
+func AppendHardwareAddr(b []byte, addr net.HardwareAddr) []byte {
+	if addr == nil {
+		return AppendNil(b)
+	}
+	b = AppendTag(b, tagNetworkAddress)
+	return AppendBytes(b, addr)
+}
+
 // AppendRegexpString appends tag(35) with a regular expression pattern as text
 func AppendRegexpString(b []byte, re string) []byte {
 	b = AppendTag(b, tagRegexp)
@@ -467,6 +577,43 @@ func AppendDecimalFraction(b []byte, exponent int64, mantissa *bigmath.Int) []by
 	return b
 }
 
+// AppendDecimalFromFloat appends f as a tag(4) decimal fraction with
+// exponent -precision, i.e. with precision digits after the decimal
+// point (a negative precision rounds to tens, hundreds, ...). The
+// mantissa is f scaled by 10^precision and rounded to the nearest
+// integer, halves away from zero. A nil f is written as null and an
+// infinite one as a float infinity, which a decimal fraction cannot hold.
+func AppendDecimalFromFloat(b []byte, f *bigmath.Float, precision int) []byte {
+	if f == nil {
+		return AppendNil(b)
+	}
+	if f.IsInf() {
+		return AppendFloat64(b, math.Inf(f.Sign()))
+	}
+	// Scale the exact rational value of f and round the quotient.
+	r, _ := f.Rat(nil)
+	num, den := new(bigmath.Int).Set(r.Num()), new(bigmath.Int).Set(r.Denom())
+	digits := int64(precision)
+	if digits < 0 {
+		digits = -digits
+	}
+	scale := new(bigmath.Int).Exp(bigmath.NewInt(10), bigmath.NewInt(digits), nil)
+	if precision >= 0 {
+		num.Mul(num, scale)
+	} else {
+		den.Mul(den, scale)
+	}
+	mant, rem := new(bigmath.Int).QuoRem(num, den, new(bigmath.Int))
+	if rem.Abs(rem).Lsh(rem, 1).Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			mant.Sub(mant, bigmath.NewInt(1))
+		} else {
+			mant.Add(mant, bigmath.NewInt(1))
+		}
+	}
+	return AppendDecimalFraction(b, -int64(precision), mant)
+}
+
 // AppendBigfloat appends tag(5) bigfloat [exponent, mantissa]
 func AppendBigfloat(b []byte, exponent int64, mantissa *bigmath.Int) []byte {
 	b = AppendTag(b, tagBigfloat)
@@ -476,6 +623,25 @@ func AppendBigfloat(b []byte, exponent int64, mantissa *bigmath.Int) []byte {
 	return b
 }
 
+// AppendBigfloatFromFloat appends f exactly as a tag(5) bigfloat, with
+// the integer mantissa taken from f.MantExp. A nil f is written as null
+// and an infinite one as a float infinity, which a bigfloat cannot hold.
+func AppendBigfloatFromFloat(b []byte, f *bigmath.Float) []byte {
+	if f == nil {
+		return AppendNil(b)
+	}
+	if f.IsInf() {
+		return AppendFloat64(b, math.Inf(f.Sign()))
+	}
+	// f = m × 2^exp with 0.5 <= |m| < 1; shifting m left by its
+	// precision makes it an integer without losing bits.
+	m := new(bigmath.Float)
+	exp := f.MantExp(m)
+	prec := int(m.MinPrec())
+	mant, _ := m.SetMantExp(m, prec).Int(nil)
+	return AppendBigfloat(b, int64(exp)-int64(prec), mant)
+}
+
 // AppendBase64URL appends tag(21) with a byte string payload
 func AppendBase64URL(b []byte, data []byte) []byte {
 	b = AppendTag(b, tagBase64URL)
@@ -570,6 +736,17 @@ func AppendMapStrStr(b []byte, m map[string]string) []byte {
 	return b
 }
 
+// AppendMapStrBool appends a map[string]bool
+func AppendMapStrBool(b []byte, m map[string]bool) []byte {
+	sz := uint32(len(m))
+	b = AppendMapHeader(b, sz)
+	for key, val := range m {
+		b = AppendString(b, key)
+		b = AppendBool(b, val)
+	}
+	return b
+}
+
 // AppendMapStrInterface appends a map[string]any
 func AppendMapStrInterface(b []byte, m map[string]any) ([]byte, error) {
 	sz := uint32(len(m))
@@ -603,15 +780,7 @@ func AppendMapUint64Marshaler[T any](b []byte, m map[uint64]T) ([]byte, error) {
 	var err error
 	for k, v := range m {
 		b = AppendUint64(b, k)
-		var mval Marshaler
-		if mm, ok := any(v).(Marshaler); ok {
-			mval = mm
-		} else if mm, ok := any(&v).(Marshaler); ok {
-			mval = mm
-		} else {
-			return b, &ErrUnsupportedType{}
-		}
-		b, err = mval.MarshalCBOR(b)
+		b, err = appendMarshalerValue(b, v)
 		if err != nil {
 			return b, err
 		}
@@ -619,6 +788,18 @@ func AppendMapUint64Marshaler[T any](b []byte, m map[uint64]T) ([]byte, error) {
 	return b, nil
 }
 
+// appendMarshalerValue appends v through its Marshaler implementation,
+// declared on either T or *T.
+func appendMarshalerValue[T any](b []byte, v T) ([]byte, error) {
+	if mm, ok := any(v).(Marshaler); ok {
+		return mm.MarshalCBOR(b)
+	}
+	if mm, ok := any(&v).(Marshaler); ok {
+		return mm.MarshalCBOR(b)
+	}
+	return b, &ErrUnsupportedType{}
+}
+
 // AppendMapUint64Uint64 appends a map[uint64]uint64 as a CBOR map with
 // uint64 keys and values. This is used for ConsumerState.Redelivered and
 // avoids reflection or interface-based encoding.
@@ -645,6 +826,20 @@ func AppendPtrMarshaler[T any](b []byte, v *T) ([]byte, error) {
 	return b, &ErrUnsupportedType{}
 }
 
+// AppendSlice appends v as a CBOR array, calling enc to append each
+// element. It stops at the first error returned by enc.
+func AppendSlice[T any](b []byte, v []T, enc func(dst []byte, elem T) ([]byte, error)) ([]byte, error) {
+	b = AppendArrayHeader(b, uint32(len(v)))
+	var err error
+	for i := range v {
+		b, err = enc(b, v[i])
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
 // AppendSliceMarshaler appends a slice of values that have a corresponding
 // Marshaler implementation to a CBOR array. It is intended for use by
 // generated code (cborgen) to avoid per-element AppendInterface overhead.
@@ -671,13 +866,24 @@ func AppendSliceMarshaler[T any](b []byte, v []T) ([]byte, error) {
 	return b, nil
 }
 
-// AppendInterface appends an arbitrary value
+// AppendInterface appends an arbitrary value. Converting a value to any
+// can allocate at the call site (e.g. a uint32 above 255 or any realistic
+// time.Duration), before the type switch runs, so callers that know the
+// static type should use the typed helper such as AppendUint32 or
+// AppendDuration instead.
 func AppendInterface(b []byte, i any) ([]byte, error) {
 	if i == nil {
 		return AppendNil(b), nil
 	}
 
 	switch v := i.(type) {
+	case Number:
+		return v.MarshalCBOR(b)
+	case *Number:
+		if v == nil {
+			return AppendNil(b), nil
+		}
+		return v.MarshalCBOR(b)
 	case Marshaler:
 		return v.MarshalCBOR(b)
 	case string:
@@ -708,12 +914,36 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 		return AppendFloat32(b, v), nil
 	case float64:
 		return AppendFloat64(b, v), nil
-	case []byte:
+	case complex64:
+		return AppendComplex64(b, v), nil
+	case complex128:
+		return AppendComplex128(b, v), nil
+	case json.RawMessage:
+		// Treat RawMessage as an opaque CBOR byte string. A type switch
+		// matches the dynamic type exactly, so []byte below never
+		// catches it; the case sits here to keep the two together.
+		return AppendBytes(b, []byte(v)), nil
+	case []byte: // []uint8 == []byte; handled here too
 		return AppendBytes(b, v), nil
 	case time.Time:
 		return AppendTime(b, v), nil
+	case *time.Time:
+		if v == nil {
+			return AppendNil(b), nil
+		}
+		return AppendTime(b, *v), nil
 	case time.Duration:
 		return AppendDuration(b, v), nil
+	case *url.URL:
+		return AppendURL(b, v), nil
+	case url.URL:
+		return AppendURL(b, &v), nil
+	case *regexp.Regexp:
+		return AppendRegexp(b, v), nil
+	case *bigmath.Int:
+		return AppendBigInt(b, v), nil
+	case *bigmath.Float:
+		return AppendBigfloatFromFloat(b, v), nil
 	case []int:
 		b = AppendArrayHeader(b, uint32(len(v)))
 		for _, elem := range v {
@@ -786,6 +1016,12 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			b = AppendString(b, elem)
 		}
 		return b, nil
+	case [][]byte:
+		b = AppendArrayHeader(b, uint32(len(v)))
+		for _, elem := range v {
+			b = AppendBytes(b, elem)
+		}
+		return b, nil
 	case map[string]int:
 		b = AppendMapHeader(b, uint32(len(v)))
 		for k, val := range v {
@@ -800,6 +1036,20 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			b = AppendInt64(b, val)
 		}
 		return b, nil
+	case map[string]int32:
+		b = AppendMapHeader(b, uint32(len(v)))
+		for k, val := range v {
+			b = AppendString(b, k)
+			b = AppendInt32(b, val)
+		}
+		return b, nil
+	case map[string]int16:
+		b = AppendMapHeader(b, uint32(len(v)))
+		for k, val := range v {
+			b = AppendString(b, k)
+			b = AppendInt16(b, val)
+		}
+		return b, nil
 	case map[string]uint:
 		b = AppendMapHeader(b, uint32(len(v)))
 		for k, val := range v {
@@ -814,6 +1064,20 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			b = AppendUint64(b, val)
 		}
 		return b, nil
+	case map[string]uint32:
+		b = AppendMapHeader(b, uint32(len(v)))
+		for k, val := range v {
+			b = AppendString(b, k)
+			b = AppendUint32(b, val)
+		}
+		return b, nil
+	case map[string]uint16:
+		b = AppendMapHeader(b, uint32(len(v)))
+		for k, val := range v {
+			b = AppendString(b, k)
+			b = AppendUint16(b, val)
+		}
+		return b, nil
 	case map[string]float64:
 		b = AppendMapHeader(b, uint32(len(v)))
 		for k, val := range v {
@@ -821,6 +1085,15 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			b = AppendFloat64(b, val)
 		}
 		return b, nil
+	case map[string]float32:
+		b = AppendMapHeader(b, uint32(len(v)))
+		for k, val := range v {
+			b = AppendString(b, k)
+			b = AppendFloat32(b, val)
+		}
+		return b, nil
+	case map[string]bool:
+		return AppendMapStrBool(b, v), nil
 	case map[string]string:
 		b = AppendMapHeader(b, uint32(len(v)))
 		for k, val := range v {
@@ -828,9 +1101,6 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			b = AppendString(b, val)
 		}
 		return b, nil
-	case json.RawMessage:
-		// Treat RawMessage as an opaque CBOR byte string.
-		return AppendBytes(b, []byte(v)), nil
 	case json.Number:
 		if iv, err := v.Int64(); err == nil {
 			return AppendInt64(b, iv), nil
@@ -841,6 +1111,28 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 		return b, &ErrUnsupportedType{}
 	case map[string]any:
 		return AppendMapStrInterface(b, v)
+	case map[int64]any:
+		b = AppendMapHeader(b, uint32(len(v)))
+		var err error
+		for k, val := range v {
+			b = AppendInt64(b, k)
+			b, err = AppendInterface(b, val)
+			if err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case map[uint64]any:
+		b = AppendMapHeader(b, uint32(len(v)))
+		var err error
+		for k, val := range v {
+			b = AppendUint64(b, k)
+			b, err = AppendInterface(b, val)
+			if err != nil {
+				return b, err
+			}
+		}
+		return b, nil
 	case []any:
 		b = AppendArrayHeader(b, uint32(len(v)))
 		var err error
@@ -851,6 +1143,14 @@ func AppendInterface(b []byte, i any) ([]byte, error) {
 			}
 		}
 		return b, nil
+	case error:
+		// Errors travel as their message. Marshaler errors were matched
+		// above; a typed nil pointer is written as null rather than
+		// panicking in Error.
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return AppendNil(b), nil
+		}
+		return AppendString(b, v.Error()), nil
 	default:
 		// Fallback: handle slices and maps of Marshaler types via reflection.
 		rv := reflect.ValueOf(i)
@@ -950,6 +1250,40 @@ func AppendMapStrStrDeterministic(b []byte, m map[string]string) []byte {
 	return b
 }
 
+// AppendMapStrStrDeterministicKeys is like AppendMapStrStrDeterministic
+// but writes the entries in the order of keys, a caller-maintained list
+// of m's keys already sorted by encoded key bytes (shorter keys first,
+// then bytewise), so no scratch slice is allocated. When keys is nil or
+// is not exactly m's keys in that order, m's keys are sorted internally
+// instead.
+func AppendMapStrStrDeterministicKeys(b []byte, m map[string]string, keys []string) []byte {
+	if keys == nil || len(keys) != len(m) {
+		return AppendMapStrStrDeterministic(b, m)
+	}
+	start := len(b)
+	b = AppendMapHeader(b, uint32(len(keys)))
+	for i, k := range keys {
+		v, ok := m[k]
+		// Strictly increasing keys are distinct, so as many of them as
+		// m has, all present, are exactly m's keys.
+		if !ok || (i > 0 && !encodedKeyLess(keys[i-1], k)) {
+			return AppendMapStrStrDeterministic(b[:start], m)
+		}
+		b = AppendString(b, k)
+		b = AppendString(b, v)
+	}
+	return b
+}
+
+// encodedKeyLess reports whether string key a sorts before b by encoded
+// bytes: shorter keys first, then bytewise.
+func encodedKeyLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
 // AppendMapStrInterfaceDeterministic appends a map[string]any with keys sorted by encoded key bytes.
 func AppendMapStrInterfaceDeterministic(b []byte, m map[string]any) ([]byte, error) {
 	sz := uint32(len(m))
@@ -984,6 +1318,26 @@ func AppendBreak(b []byte) []byte {
 	return append(b, makeByte(majorTypeSimple, simpleBreak))
 }
 
+// AppendRawMapNoDup appends a map with entries provided as raw CBOR
+// key/value pairs, in the given order. It returns b unchanged and
+// ErrDuplicateMapKey if two keys have identical encodings. It is the
+// encode-side counterpart of ReadMapNoDupBytes.
+func AppendRawMapNoDup(b []byte, pairs []RawPair) ([]byte, error) {
+	seen := make(map[string]struct{}, len(pairs))
+	for i := range pairs {
+		if _, ok := seen[string(pairs[i].Key)]; ok {
+			return b, ErrDuplicateMapKey
+		}
+		seen[string(pairs[i].Key)] = struct{}{}
+	}
+	b = AppendMapHeader(b, uint32(len(pairs)))
+	for i := range pairs {
+		b = append(b, pairs[i].Key...)
+		b = append(b, pairs[i].Value...)
+	}
+	return b, nil
+}
+
 // AppendRawMapDeterministic appends a map with entries provided as raw CBOR key/value pairs.
 // Pairs are sorted by CBOR-encoded key bytes to ensure RFC 8949 deterministic order.
 func AppendRawMapDeterministic(b []byte, pairs []RawPair) []byte {
@@ -1063,6 +1417,19 @@ func AppendRawMapDeterministic(b []byte, pairs []RawPair) []byte {
 func AppendMapDeterministic[K comparable, V any](b []byte, m map[K]V,
 	encKey func(dst []byte, k K) []byte,
 	encVal func(dst []byte, v V) ([]byte, error),
+) ([]byte, error) {
+	return AppendMapDeterministicE(b, m, func(dst []byte, k K) ([]byte, error) {
+		return encKey(dst, k), nil
+	}, encVal)
+}
+
+// AppendMapDeterministicE is AppendMapDeterministic for key encoders
+// that can fail, e.g. custom key types that validate themselves. The
+// first error from encKey or encVal is returned; b is returned
+// unchanged when a key fails to encode.
+func AppendMapDeterministicE[K comparable, V any](b []byte, m map[K]V,
+	encKey func(dst []byte, k K) ([]byte, error),
+	encVal func(dst []byte, v V) ([]byte, error),
 ) ([]byte, error) {
 	type item struct {
 		keyEnc []byte
@@ -1077,7 +1444,11 @@ func AppendMapDeterministic[K comparable, V any](b []byte, m map[K]V,
 	var scratch []byte
 	for k, v := range m {
 		prev := len(scratch)
-		scratch = encKey(scratch, k)
+		var err error
+		scratch, err = encKey(scratch, k)
+		if err != nil {
+			return b, err
+		}
 		ke := scratch[prev:]
 		items = append(items, item{keyEnc: ke, key: k, val: v})
 	}
@@ -1203,6 +1574,11 @@ func AppendMapDeterministicStrFloat64(b []byte, m map[string]float64) []byte {
 	return out
 }
 
+// AppendMapDeterministicStrBytes appends m with its keys in RFC 8949
+// core deterministic order: shorter encoded keys first, then bytewise.
+// Only the keys decide the order; values of any length, including
+// empty or nil slices (both written as an empty byte string), do not
+// affect it.
 func AppendMapDeterministicStrBytes(b []byte, m map[string][]byte) []byte {
 	out, _ := AppendMapDeterministic(b, m, EncKeyString, EncValBytes)
 	return out
@@ -1211,3 +1587,25 @@ func AppendMapDeterministicStrBytes(b []byte, m map[string][]byte) []byte {
 func AppendMapDeterministicStrInterface(b []byte, m map[string]any) ([]byte, error) {
 	return AppendMapDeterministic(b, m, EncKeyString, EncValInterface)
 }
+
+// AppendMapDeterministicUint64Marshaler is AppendMapUint64Marshaler with
+// the pairs written in ascending key order, as required for
+// deterministic encoding.
+func AppendMapDeterministicUint64Marshaler[T any](b []byte, m map[uint64]T) ([]byte, error) {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// Unsigned integers sort the same by value as by encoded bytes.
+	slices.Sort(keys)
+	b = AppendMapHeader(b, uint32(len(keys)))
+	var err error
+	for _, k := range keys {
+		b = AppendUint64(b, k)
+		b, err = appendMarshalerValue(b, m[k])
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
@@ -1,5 +1,10 @@
 package cbor
 
+import (
+	bigmath "math/big"
+	"time"
+)
+
 // Writer provides a minimal CBOR writer backed by ByteBuffer.
 // It is intended for use by generated EncodeMsg implementations.
 type Writer struct {
@@ -71,3 +76,43 @@ func (w *Writer) WriteBytes(v []byte) error {
 	w.bb.AppendBytes(v)
 	return nil
 }
+
+// WriteTag writes a semantic tag header; the tagged item must follow.
+func (w *Writer) WriteTag(tag uint64) error {
+	w.bb.AppendTag(tag)
+	return nil
+}
+
+// WriteTime writes a time.Time as tag 1 (epoch timestamp).
+func (w *Writer) WriteTime(t time.Time) error {
+	w.bb.b = AppendTime(w.bb.b, t)
+	return nil
+}
+
+// WriteDuration writes a time.Duration as int64 nanoseconds.
+func (w *Writer) WriteDuration(d time.Duration) error {
+	w.bb.b = AppendDuration(w.bb.b, d)
+	return nil
+}
+
+// WriteBigInt writes a big integer as tag 2 or 3, or null if z is nil.
+func (w *Writer) WriteBigInt(z *bigmath.Int) error {
+	w.bb.b = AppendBigInt(w.bb.b, z)
+	return nil
+}
+
+// WriteFloat16 writes a float32 value as a half-precision float.
+func (w *Writer) WriteFloat16(f float32) error {
+	w.bb.b = AppendFloat16(w.bb.b, f)
+	return nil
+}
+
+// WriteSimpleValue writes a simple value. The values 24..31 have no
+// well-formed encoding and yield ErrInvalidSimpleValue.
+func (w *Writer) WriteSimpleValue(val uint8) error {
+	if reservedSimpleValue(val) {
+		return ErrInvalidSimpleValue
+	}
+	w.bb.b = AppendSimpleValue(w.bb.b, val)
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package cddl holds fixtures generated with --cddl to exercise the
+// CBORSchema methods.
+package cddl
+
+import "time"
+
+// Account covers scalars, containers, pointers and tag options.
+type Account struct {
+	Name    string            `cbor:"name"`
+	Age     int               `cbor:"age,omitempty"`
+	Seq     int64             `cbor:"seq,uint"`
+	Tags    []string          `cbor:"tags"`
+	Limits  map[string]uint64 `cbor:"limits"`
+	Owner   *Owner            `cbor:"owner"`
+	Keys    []byte            `cbor:"keys"`
+	Created time.Time         `cbor:"created"`
+	TTL     time.Duration     `cbor:"ttl,string"`
+	Extra   []byte            `cbor:"extra,rawcbor"`
+}
+
+// Owner is referenced from Account by rule name.
+type Owner struct {
+	ID     uint32            `cbor:"id"`
+	Scores map[uint16]*Owner `cbor:"scores,omitempty"`
+}
+
+// Point is keyed by integers, so its rule uses numeric keys.
+type Point struct {
+	_ struct{} `cbor:",keytype=int"`
+	X int64    `cbor:"x"`
+	Y int64    `cbor:"y,omitempty,key=5"`
+}
+
+// Holder carries either an Account or an Owner, so its rule offers both.
+type Holder struct {
+	Of any `cbor:"of,oneof=Account,Owner"`
+}
+
+// Window picks a different wire form for each of its times.
+type Window struct {
+	Start time.Time `cbor:"start,rfc3339"`
+	End   time.Time `cbor:"end,epochms"`
+	Seen  time.Time `cbor:"seen,epoch"`
+}
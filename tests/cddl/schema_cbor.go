@@ -0,0 +1,1020 @@
+// Code generated by cborgen DO NOT EDIT.
+
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i . --cddl
+
+package cddl
+
+import (
+	"math"
+	"time"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func (x Account) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("age")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("seq")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tags")+cbor.ArrayHeaderSize+len(x.Tags)*cbor.StringPrefixSize+cbor.StringsLen(x.Tags))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("limits")+cbor.MapHeaderSize+len(x.Limits)*(cbor.StringPrefixSize+cbor.Uint64Size)+cbor.MapKeysLen(x.Limits))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("owner")+cbor.PtrMsgsize(x.Owner))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("keys")+cbor.BytesPrefixSize+len(x.Keys))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("created")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ttl")+cbor.DurationSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("extra")+cbor.BytesPrefixSize+len(x.Extra))
+	return
+}
+
+func (x *Account) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(9)
+	if x.Age != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	if x.Age != 0 {
+		b = cbor.AppendString(b, "age")
+		b = cbor.AppendInt(b, x.Age)
+	}
+	b = cbor.AppendString(b, "seq")
+	b = cbor.AppendUint64(b, uint64(x.Seq))
+
+	b = cbor.AppendString(b, "tags")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+	for _, v := range x.Tags {
+		b = cbor.AppendString(b, v)
+	}
+
+	b = cbor.AppendString(b, "limits")
+	b = cbor.AppendMapHeader(b, uint32(len(x.Limits)))
+	for k, v := range x.Limits {
+		b = cbor.AppendString(b, k)
+		b = cbor.AppendUint64(b, v)
+	}
+	b = cbor.AppendString(b, "owner")
+	b, err = cbor.AppendPtrMarshaler(b, x.Owner)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "keys")
+	b, err = cbor.AppendInterface(b, x.Keys)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "created")
+	b = cbor.AppendTime(b, x.Created)
+	b = cbor.AppendString(b, "ttl")
+	b = cbor.AppendString(b, x.TTL.String())
+	b = cbor.AppendString(b, "extra")
+	b = cbor.AppendRaw(b, x.Extra)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Account) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "age":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Age = tmp
+		case "seq":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt64 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Seq = int64(tmp)
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		case "limits":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Limits == nil && sz > 0 {
+				x.Limits = make(map[string]uint64, sz)
+			} else if x.Limits != nil {
+				clear(x.Limits)
+			}
+			for iLimits := uint32(0); iLimits < sz; iLimits++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Limits[key] = tmp
+			}
+		case "owner":
+
+			if x.Owner == nil {
+				x.Owner = new(Owner)
+			}
+			v, err = x.Owner.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "keys":
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Keys = tmp
+		case "created":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Created = tmp
+		case "ttl":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.TTL, err = time.ParseDuration(tmp)
+			if err != nil {
+				return b, err
+			}
+		case "extra":
+
+			var next []byte
+			next, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+			if cbor.IsNil(v) {
+				x.Extra = nil
+			} else {
+				x.Extra = append(x.Extra[:0], v[:len(v)-len(next)]...)
+			}
+			v = next
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Account) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "age":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Age = tmp
+		case "seq":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt64 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Seq = int64(tmp)
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		case "limits":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Limits == nil && sz > 0 {
+				x.Limits = make(map[string]uint64, sz)
+			} else if x.Limits != nil {
+				clear(x.Limits)
+			}
+			for iLimits := uint32(0); iLimits < sz; iLimits++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Limits[key] = tmp
+			}
+		case "owner":
+
+			if x.Owner == nil {
+				x.Owner = new(Owner)
+			}
+			v, err = x.Owner.DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "keys":
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Keys = tmp
+		case "created":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Created = tmp
+		case "ttl":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.TTL, err = time.ParseDuration(cbor.UnsafeString(tmpBytes))
+			if err != nil {
+				return b, err
+			}
+		case "extra":
+
+			var next []byte
+			next, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+			if cbor.IsNil(v) {
+				x.Extra = nil
+			} else {
+				x.Extra = v[: len(v)-len(next) : len(v)-len(next)]
+			}
+			v = next
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Account) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// CBORSchema returns a CDDL (RFC 8610) rule describing the encoding of Account.
+func (x Account) CBORSchema() string {
+	return `Account = {
+  "name": tstr,
+  ? "age": int,
+  "seq": uint,
+  "tags": [* tstr],
+  "limits": {* tstr => uint},
+  "owner": Owner / nil,
+  "keys": bstr,
+  "created": time,
+  "ttl": tstr,
+  "extra": any
+}`
+}
+
+func (x Owner) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.Uint32Size)
+	return
+}
+
+func (x *Owner) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if len(x.Scores) != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendUint32(b, x.ID)
+	if len(x.Scores) != 0 {
+
+		b = cbor.AppendString(b, "scores")
+		b = cbor.AppendMapHeader(b, uint32(len(x.Scores)))
+		for k, v := range x.Scores {
+			b = cbor.AppendUint16(b, k)
+			if v == nil {
+				b = cbor.AppendNil(b)
+			} else {
+				b, err = v.MarshalCBOR(b)
+				if err != nil {
+					return b, err
+				}
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Owner) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "id":
+
+			var tmp uint32
+			tmp, v, err = cbor.ReadUint32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "scores":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Scores == nil && sz > 0 {
+				x.Scores = make(map[uint16]*Owner, sz)
+			} else if x.Scores != nil {
+				clear(x.Scores)
+			}
+			for iScores := uint32(0); iScores < sz; iScores++ {
+				var key uint16
+				key, v, err = cbor.ReadUint16Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cbor.IsNil(v) {
+					v, err = cbor.ReadNilBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Scores[key] = nil
+					continue
+				}
+				tmp := new(Owner)
+				v, err = tmp.UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+				x.Scores[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Owner) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+
+			var tmp uint32
+			tmp, v, err = cbor.ReadUint32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "scores":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Scores == nil && sz > 0 {
+				x.Scores = make(map[uint16]*Owner, sz)
+			} else if x.Scores != nil {
+				clear(x.Scores)
+			}
+			for iScores := uint32(0); iScores < sz; iScores++ {
+				var key uint16
+				key, v, err = cbor.ReadUint16Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cbor.IsNil(v) {
+					v, err = cbor.ReadNilBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Scores[key] = nil
+					continue
+				}
+				val := new(Owner)
+				v, err = val.DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+				x.Scores[key] = val
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Owner) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// CBORSchema returns a CDDL (RFC 8610) rule describing the encoding of Owner.
+func (x Owner) CBORSchema() string {
+	return `Owner = {
+  "id": uint,
+  ? "scores": {* uint => (Owner / nil)}
+}`
+}
+
+func (x Point) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("x")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("y")+cbor.Int64Size)
+	return
+}
+
+func (x *Point) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if x.Y != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendUint64(b, 0)
+	b = cbor.AppendInt64(b, x.X)
+	if x.Y != 0 {
+		b = cbor.AppendUint64(b, 5)
+		b = cbor.AppendInt64(b, x.Y)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Point) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadUint64Bytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case 0:
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.X = tmp
+		case 5:
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Y = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Point) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadUint64Bytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case 0:
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.X = tmp
+		case 5:
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Y = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Point) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// CBORSchema returns a CDDL (RFC 8610) rule describing the encoding of Point.
+func (x Point) CBORSchema() string {
+	return `Point = {
+  0: int,
+  ? 5: int
+}`
+}
+
+func (x *Holder) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.AppendMapHeader(b, uint32(1))
+	var err error
+
+	b = cbor.AppendString(b, "of")
+	switch v := x.Of.(type) {
+	case nil:
+		b = cbor.AppendNil(b)
+	case *Account:
+		b, err = v.MarshalCBOR(b)
+	case Account:
+		b, err = v.MarshalCBOR(b)
+	case *Owner:
+		b, err = v.MarshalCBOR(b)
+	case Owner:
+		b, err = v.MarshalCBOR(b)
+	default:
+		err = cbor.ErrUnknownVariant
+	}
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Holder) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "of":
+
+			if cbor.IsNil(v) {
+				x.Of = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var which int
+				which, err = cbor.MatchMapKeys(v, []string{"name", "age", "seq", "tags", "limits", "owner", "keys", "created", "ttl", "extra"}, []string{"id", "scores"})
+				if err != nil {
+					return b, err
+				}
+				switch which {
+				case 0:
+					tmp := new(Account)
+					v, err = tmp.UnmarshalCBOR(v)
+					if err != nil {
+						return b, err
+					}
+					x.Of = tmp
+				case 1:
+					tmp := new(Owner)
+					v, err = tmp.UnmarshalCBOR(v)
+					if err != nil {
+						return b, err
+					}
+					x.Of = tmp
+				default:
+					return b, cbor.ErrUnknownVariant
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Holder) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "of":
+
+			if cbor.IsNil(v) {
+				x.Of = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var which int
+				which, err = cbor.MatchMapKeys(v, []string{"name", "age", "seq", "tags", "limits", "owner", "keys", "created", "ttl", "extra"}, []string{"id", "scores"})
+				if err != nil {
+					return b, err
+				}
+				switch which {
+				case 0:
+					tmp := new(Account)
+					v, err = tmp.DecodeTrusted(v)
+					if err != nil {
+						return b, err
+					}
+					x.Of = tmp
+				case 1:
+					tmp := new(Owner)
+					v, err = tmp.DecodeTrusted(v)
+					if err != nil {
+						return b, err
+					}
+					x.Of = tmp
+				default:
+					return b, cbor.ErrUnknownVariant
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Holder) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// CBORSchema returns a CDDL (RFC 8610) rule describing the encoding of Holder.
+func (x Holder) CBORSchema() string {
+	return `Holder = {
+  "of": Account / Owner / nil
+}`
+}
+
+func (x Window) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("start")+cbor.RFC3339TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("end")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("seen")+cbor.TimeSize)
+	return
+}
+
+func (x *Window) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(3))
+	b = cbor.AppendString(b, "start")
+	b = cbor.AppendRFC3339Time(b, x.Start)
+	b = cbor.AppendString(b, "end")
+	b = cbor.AppendUnixMilli(b, x.End)
+	b = cbor.AppendString(b, "seen")
+	b = cbor.AppendTime(b, x.Seen)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Window) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "start":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadRFC3339TimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Start = tmp
+		case "end":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadUnixMilliBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.End = tmp
+		case "seen":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seen = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Window) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "start":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadRFC3339TimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Start = tmp
+		case "end":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadUnixMilliBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.End = tmp
+		case "seen":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seen = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Window) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// CBORSchema returns a CDDL (RFC 8610) rule describing the encoding of Window.
+func (x Window) CBORSchema() string {
+	return `Window = {
+  "start": tdate,
+  "end": int,
+  "seen": time
+}`
+}
@@ -0,0 +1,45 @@
+package cddl
+
+import "testing"
+
+func TestCBORSchema(t *testing.T) {
+	cases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Account", Account{}.CBORSchema(), `Account = {
+  "name": tstr,
+  ? "age": int,
+  "seq": uint,
+  "tags": [* tstr],
+  "limits": {* tstr => uint},
+  "owner": Owner / nil,
+  "keys": bstr,
+  "created": time,
+  "ttl": tstr,
+  "extra": any
+}`},
+		{"Owner", Owner{}.CBORSchema(), `Owner = {
+  "id": uint,
+  ? "scores": {* uint => (Owner / nil)}
+}`},
+		{"Point", Point{}.CBORSchema(), `Point = {
+  0: int,
+  ? 5: int
+}`},
+		{"Holder", Holder{}.CBORSchema(), `Holder = {
+  "of": Account / Owner / nil
+}`},
+		{"Window", Window{}.CBORSchema(), `Window = {
+  "start": tdate,
+  "end": int,
+  "seen": time
+}`},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Fatalf("%s schema:\n%s\nwant:\n%s", tc.name, tc.got, tc.want)
+		}
+	}
+}
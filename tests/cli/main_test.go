@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// cborgenBin is the cborgen binary built by TestMain, so the tests run
+// the command as users do, exit status included.
+var cborgenBin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "cborgen-cli")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cborgenBin = filepath.Join(dir, "cborgen")
+	build := exec.Command("go", "build", "-o", cborgenBin, "github.com/synadia-labs/cbor.go/cborgen")
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "build cborgen: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// itemSrc is a small source file that generates without errors.
+const itemSrc = "package demo\n\ntype Item struct {\n\tName string `cbor:\"name\"`\n\tQty  int    `cbor:\"qty\"`\n}\n"
+
+// cborgen runs the cborgen binary in dir and returns its stdout, stderr
+// and exit status.
+func cborgen(t *testing.T, dir string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.Command(cborgenBin, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	case err != nil:
+		t.Fatalf("run cborgen: %v", err)
+	}
+	return outBuf.String(), errBuf.String(), code
+}
+
+// writeFile writes src to name in dir.
+func writeFile(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// exists reports whether name exists in dir.
+func exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+// badSrc fails generation: a struct cannot be both readonly and
+// writeonly.
+const badSrc = "package demo\n\ntype Bad struct {\n\t_    struct{} `cbor:\",readonly,writeonly\"`\n\tName string   `cbor:\"name\"`\n}\n"
+
+// TestIgnoreErrors checks that --ignore-errors reports a failing file,
+// still generates the files after it and exits with an error counting
+// the failures, while without it generation stops at the failure.
+func TestIgnoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	// Files are generated in lexical order, so bad.go fails first.
+	writeFile(t, dir, "bad.go", badSrc)
+	writeFile(t, dir, "item.go", itemSrc)
+
+	_, stderr, code := cborgen(t, dir, "-i", ".")
+	if code != 1 || !strings.Contains(stderr, "readonly cannot be combined with writeonly") {
+		t.Fatalf("exit %d, stderr %q", code, stderr)
+	}
+	if exists(dir, "item_cbor.go") {
+		t.Fatalf("item_cbor.go written after the failure without --ignore-errors")
+	}
+
+	_, stderr, code = cborgen(t, dir, "-i", ".", "--ignore-errors")
+	if code != 1 {
+		t.Fatalf("exit %d, want 1; stderr %q", code, stderr)
+	}
+	if !strings.Contains(stderr, "cborgen: bad.go: Bad: readonly cannot be combined with writeonly") {
+		t.Fatalf("failure of bad.go not reported: %q", stderr)
+	}
+	if !strings.Contains(stderr, "generation failed for 1 file(s)") {
+		t.Fatalf("failures not summarized: %q", stderr)
+	}
+	if exists(dir, "bad_cbor.go") || !exists(dir, "item_cbor.go") {
+		t.Fatalf("bad_cbor.go written: %v, item_cbor.go written: %v", exists(dir, "bad_cbor.go"), exists(dir, "item_cbor.go"))
+	}
+}
+
+// TestNoLint checks that --nolint puts a single //nolint:all directive
+// in every generated file, between the generated-code header and the
+// package clause, and that it is absent otherwise.
+func TestNoLint(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.go", itemSrc)
+	writeFile(t, dir, "tag.go", "package demo\n\ntype Tag struct {\n\tKey string `cbor:\"key\"`\n}\n")
+
+	for _, nolint := range []bool{true, false} {
+		args := []string{"-i", "."}
+		if nolint {
+			args = append(args, "--nolint")
+		}
+		if _, stderr, code := cborgen(t, dir, args...); code != 0 {
+			t.Fatalf("nolint %v: exit %d: %s", nolint, code, stderr)
+		}
+		for _, name := range []string{"item_cbor.go", "tag_cbor.go"} {
+			src, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := strings.Count(string(src), "//nolint")
+			if !nolint {
+				if n != 0 {
+					t.Fatalf("%s has //nolint without --nolint", name)
+				}
+				continue
+			}
+			if n != 1 {
+				t.Fatalf("%s has %d //nolint directives, want 1", name, n)
+			}
+			lines := strings.Split(string(src), "\n")
+			pkg := slices.Index(lines, "package demo")
+			if pkg < 1 || lines[pkg-1] != "//nolint:all" || !strings.HasPrefix(lines[0], "// Code generated by cborgen") {
+				t.Fatalf("%s: //nolint:all is not right above the package clause:\n%s", name, strings.Join(lines[:max(pkg+1, 1)], "\n"))
+			}
+		}
+	}
+}
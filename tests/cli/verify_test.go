@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.go", itemSrc)
+	if _, stderr, code := cborgen(t, dir, "-i", "."); code != 0 {
+		t.Fatalf("generate exit %d: %s", code, stderr)
+	}
+	stdout, stderr, code := cborgen(t, dir, "-i", ".", "--verify")
+	if code != 0 || stdout != "" {
+		t.Fatalf("verify exit %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+}
+
+// TestVerifyStale checks that a stale file fails verification with a
+// diff of small hunks that hold only the changed lines and their
+// context, and is left as it was.
+func TestVerifyStale(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.go", itemSrc)
+	if _, stderr, code := cborgen(t, dir, "-i", "."); code != 0 {
+		t.Fatalf("generate exit %d: %s", code, stderr)
+	}
+	before, err := os.ReadFile(filepath.Join(dir, "item_cbor.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "item.go", strings.Replace(itemSrc, `cbor:"qty"`, `cbor:"count"`, 1))
+
+	stdout, stderr, code := cborgen(t, dir, "-i", ".", "--verify")
+	if code != 1 {
+		t.Fatalf("verify exit %d, want 1; stderr %q", code, stderr)
+	}
+	if !strings.Contains(stderr, "1 generated file(s) out of date") {
+		t.Fatalf("stderr = %q", stderr)
+	}
+	if !strings.HasPrefix(stdout, "--- item_cbor.go\n+++ item_cbor.go (generated)\n@@ ") {
+		t.Fatalf("diff header = %q", stdout)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")[2:]
+	var hunks, removed, added int
+	for _, line := range lines {
+		switch line[0] {
+		case '@':
+			hunks++
+		case '-':
+			removed++
+			if !strings.Contains(line, `"qty"`) {
+				t.Fatalf("unchanged line shown as removed: %q", line)
+			}
+		case '+':
+			added++
+			if !strings.Contains(line, `"count"`) {
+				t.Fatalf("unchanged line shown as added: %q", line)
+			}
+		}
+	}
+	if hunks < 2 || removed == 0 || removed != added || len(lines) > hunks*(2*3+1)+removed+added {
+		t.Fatalf("diff is not made of minimal hunks:\n%s", stdout)
+	}
+
+	after, err := os.ReadFile(filepath.Join(dir, "item_cbor.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("verify rewrote item_cbor.go")
+	}
+}
+
+// TestVerifyMissing checks that a missing output fails verification
+// with a diff that adds the whole file, which is not written.
+func TestVerifyMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "item.go", itemSrc)
+	stdout, stderr, code := cborgen(t, dir, "-i", ".", "--verify")
+	if code != 1 {
+		t.Fatalf("verify exit %d, want 1; stderr %q", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) < 4 || lines[0] != "--- item_cbor.go" || !strings.HasPrefix(lines[2], "@@ -0,0 +1,") {
+		t.Fatalf("diff = %q", stdout)
+	}
+	for _, line := range lines[3:] {
+		if line[0] != '+' {
+			t.Fatalf("missing file diff has non-added line %q", line)
+		}
+	}
+	if exists(dir, "item_cbor.go") {
+		t.Fatalf("verify wrote item_cbor.go")
+	}
+}
@@ -149,6 +149,28 @@ func TestCommunityVectors(t *testing.T) {
 							if got != v.Diagnostic {
 								t.Fatalf("%s: diag mismatch: got %q want %q (hex %s)", impl.name, got, v.Diagnostic, v.Hex)
 							}
+							// ParseDiag may pick a different float width than
+							// the vector, so compare after rendering back.
+							enc, err := cbor.ParseDiag(v.Diagnostic)
+							if reservedSimpleDiag(v.Diagnostic) {
+								// RFC 7049 lists simple(24) as f818, which
+								// RFC 8949 section 3.3 no longer allows to be
+								// written.
+								if err == nil {
+									t.Fatalf("%s: ParseDiag(%s) = %x, want an error", impl.name, v.Diagnostic, enc)
+								}
+								return
+							}
+							if err != nil {
+								t.Fatalf("%s: ParseDiag error: %v", impl.name, err)
+							}
+							back, _, err := impl.diag(enc)
+							if err != nil {
+								t.Fatalf("%s: diag of parsed error: %v", impl.name, err)
+							}
+							if back != v.Diagnostic {
+								t.Fatalf("%s: ParseDiag round-trip: got %q want %q", impl.name, back, v.Diagnostic)
+							}
 						}
 					})
 				}
@@ -159,3 +181,14 @@ func TestCommunityVectors(t *testing.T) {
 		}
 	}
 }
+
+// reservedSimpleDiag reports whether diag is one of simple(24) to
+// simple(31), which have no well-formed encoding.
+func reservedSimpleDiag(diag string) bool {
+	for v := 24; v < 32; v++ {
+		if diag == "simple("+strconv.Itoa(v)+")" {
+			return true
+		}
+	}
+	return false
+}
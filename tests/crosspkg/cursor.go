@@ -0,0 +1,16 @@
+// Package crosspkg holds fixtures generated with --known-generated-pkg,
+// whose fields use struct types generated in another package. The
+// directive that regenerates them lives in cursor_cbor.go.
+package crosspkg
+
+import meta "github.com/synadia-labs/cbor.go/tests/jetstreammeta"
+
+// Cursor refers to generated jetstreammeta types by value and by
+// pointer. Pending is not listed on the command line, so it is encoded
+// with AppendInterface and skipped on decode.
+type Cursor struct {
+	Name    string              `cbor:"name"`
+	Last    meta.SequencePair   `cbor:"last"`
+	State   *meta.ConsumerState `cbor:"state"`
+	Pending *meta.Pending       `cbor:"pending"`
+}
@@ -0,0 +1,171 @@
+// Code generated by cborgen DO NOT EDIT.
+
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i cursor.go -o cursor_cbor.go --known-generated-pkg=github.com/synadia-labs/cbor.go/tests/jetstreammeta/SequencePair,ConsumerState
+
+package crosspkg
+
+import (
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+	meta "github.com/synadia-labs/cbor.go/tests/jetstreammeta"
+)
+
+func (x Cursor) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	return
+}
+
+func (x *Cursor) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 4)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "last")
+	b, err = x.Last.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "state")
+	b, err = cbor.AppendPtrMarshaler(b, x.State)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "pending")
+	b, err = cbor.AppendInterface(b, x.Pending)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Cursor) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "last":
+
+			v, err = x.Last.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "state":
+
+			if x.State == nil {
+				x.State = new(meta.ConsumerState)
+			}
+			v, err = x.State.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "pending":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Cursor) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "last":
+
+			v, err = (&x.Last).DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "state":
+
+			if x.State == nil {
+				x.State = new(meta.ConsumerState)
+			}
+			v, err = x.State.DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "pending":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Cursor) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// Compile-time checks for Cursor fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*meta.SequencePair)(nil)  // Last
+	_ cbor.Unmarshaler = (*meta.SequencePair)(nil)  // Last
+	_ cbor.Marshaler   = (*meta.ConsumerState)(nil) // State
+	_ cbor.Unmarshaler = (*meta.ConsumerState)(nil) // State
+)
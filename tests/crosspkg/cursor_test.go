@@ -0,0 +1,104 @@
+package crosspkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/synadia-labs/cbor.go/cborgen/core"
+	meta "github.com/synadia-labs/cbor.go/tests/jetstreammeta"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	in := Cursor{
+		Name:    "c",
+		Last:    meta.SequencePair{Consumer: 1, Stream: 2},
+		State:   &meta.ConsumerState{Delivered: meta.SequencePair{Consumer: 3, Stream: 4}},
+		Pending: &meta.Pending{Sequence: 5, Timestamp: 6},
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, dec := range []func(*Cursor, []byte) ([]byte, error){(*Cursor).DecodeSafe, (*Cursor).DecodeTrusted} {
+		var out Cursor
+		if _, err := dec(&out, b); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if out.Name != in.Name || out.Last != in.Last || out.State == nil || out.State.Delivered != in.State.Delivered || out.Pending != nil {
+			t.Fatalf("decode = %+v, want %+v without Pending", out, in)
+		}
+	}
+}
+
+func TestCursorTrustedUsesKnownGenerated(t *testing.T) {
+	src, err := os.ReadFile("cursor_cbor.go")
+	if err != nil {
+		t.Fatalf("read cursor_cbor.go: %v", err)
+	}
+	file := string(src)
+	start := strings.Index(file, "func (x *Cursor) DecodeTrusted")
+	if start == -1 {
+		t.Fatalf("Cursor.DecodeTrusted not found")
+	}
+	file = file[start:]
+	for _, want := range []string{
+		"(&x.Last).DecodeTrusted(v)",
+		"x.State.DecodeTrusted(v)",
+	} {
+		if !strings.Contains(file, want) {
+			t.Fatalf("DecodeTrusted does not contain %q", want)
+		}
+	}
+}
+
+func TestCursorGoGenerateDirective(t *testing.T) {
+	src, err := os.ReadFile("cursor_cbor.go")
+	if err != nil {
+		t.Fatalf("read cursor_cbor.go: %v", err)
+	}
+	want := "\n//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i cursor.go -o cursor_cbor.go " +
+		"--known-generated-pkg=github.com/synadia-labs/cbor.go/tests/jetstreammeta/SequencePair,ConsumerState\n"
+	if !strings.Contains(string(src), want) {
+		t.Fatalf("cursor_cbor.go lacks the directive %q", want)
+	}
+}
+
+// TestKnownGeneratedImportNames checks that types of a known generated
+// package are found under its default import name, which drops a major
+// version suffix, and that an import whose default name cannot be told
+// needs an explicit one.
+func TestKnownGeneratedImportNames(t *testing.T) {
+	cases := []struct {
+		name, imp, path string
+		ok              bool
+	}{
+		{"major_version_dir", `"example.com/meta/v2"`, "example.com/meta/v2", true},
+		{"gopkg_in", `"gopkg.in/meta.v3"`, "gopkg.in/meta.v3", true},
+		{"not_identifier", `"example.com/go-meta"`, "example.com/go-meta", false},
+		{"explicit_name", `meta "example.com/go-meta"`, "example.com/go-meta", true},
+	}
+	for _, tc := range cases {
+		dir := t.TempDir()
+		in := filepath.Join(dir, "holder.go")
+		src := "package holder\n\nimport " + tc.imp + "\n\ntype Holder struct {\n\tStream meta.Stream `cbor:\"stream\"`\n}\n"
+		if err := os.WriteFile(in, []byte(src), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		opts := core.Options{KnownGenerated: map[string][]string{tc.path: {"Stream"}}}
+		out, err := core.Generate(in, filepath.Join(dir, "holder_cbor.go"), opts)
+		if !tc.ok {
+			if err == nil || !strings.Contains(err.Error(), "needs an explicit name") {
+				t.Fatalf("%s: Generate error = %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Generate error = %v", tc.name, err)
+		}
+		if !strings.Contains(string(out), "(&x.Stream).DecodeTrusted(v)") {
+			t.Fatalf("%s: meta.Stream is not decoded with DecodeTrusted", tc.name)
+		}
+	}
+}
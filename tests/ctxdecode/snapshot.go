@@ -0,0 +1,39 @@
+// Package ctxdecode holds fixtures generated with --context to exercise
+// cancellation of nested decodes.
+package ctxdecode
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+// Snapshot nests Entry values through every container shape.
+type Snapshot struct {
+	Name    string            `cbor:"name"`
+	Marker  Marker            `cbor:"marker"`
+	Head    Entry             `cbor:"head"`
+	Tail    *Entry            `cbor:"tail"`
+	Entries []Entry           `cbor:"entries"`
+	Ptrs    []*Entry          `cbor:"ptrs"`
+	ByName  map[string]*Entry `cbor:"by_name"`
+}
+
+// Entry is a nested struct decoded through the context-aware path.
+type Entry struct {
+	Seq  uint64 `cbor:"seq"`
+	Data []byte `cbor:"data"`
+}
+
+// Marker is a hand-written type whose decode runs onDecode, letting
+// tests cancel a context part way through a Snapshot.
+type Marker struct {
+	onDecode func()
+}
+
+func (m *Marker) MarshalCBOR(b []byte) ([]byte, error) {
+	return cbor.AppendNil(b), nil
+}
+
+func (m *Marker) UnmarshalCBOR(b []byte) ([]byte, error) {
+	if m.onDecode != nil {
+		m.onDecode()
+	}
+	return cbor.ReadNilBytes(b)
+}
@@ -0,0 +1,493 @@
+// Code generated by cborgen DO NOT EDIT.
+
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i . --context
+
+package ctxdecode
+
+import (
+	"context"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func (x Snapshot) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("head")+x.Head.Msgsize())
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tail")+cbor.PtrMsgsize(x.Tail))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("entries")+cbor.SliceMsgsize(x.Entries))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ptrs")+cbor.PtrSliceMsgsize(x.Ptrs))
+	return
+}
+
+func (x *Snapshot) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 7)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "marker")
+	b, err = x.Marker.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "head")
+	b, err = x.Head.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "tail")
+	b, err = cbor.AppendPtrMarshaler(b, x.Tail)
+	if err != nil {
+		return b, err
+	}
+
+	b = cbor.AppendString(b, "entries")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Entries)))
+	for i := range x.Entries {
+		b, err = x.Entries[i].MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	b = cbor.AppendString(b, "ptrs")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Ptrs)))
+	for _, e := range x.Ptrs {
+		if e == nil {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = e.MarshalCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	b = cbor.AppendString(b, "by_name")
+	b = cbor.AppendMapHeader(b, uint32(len(x.ByName)))
+	for k, v := range x.ByName {
+		b = cbor.AppendString(b, k)
+		if v == nil {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = v.MarshalCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Snapshot) DecodeSafe(ctx context.Context, b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "marker":
+
+			v, err = cbor.UnmarshalContext(ctx, &x.Marker, v)
+			if err != nil {
+				return b, err
+			}
+		case "head":
+
+			v, err = cbor.UnmarshalContext(ctx, &x.Head, v)
+			if err != nil {
+				return b, err
+			}
+		case "tail":
+
+			if x.Tail == nil {
+				x.Tail = new(Entry)
+			}
+			v, err = cbor.UnmarshalContext(ctx, x.Tail, v)
+			if err != nil {
+				return b, err
+			}
+		case "entries":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Entries) >= int(sz) {
+				x.Entries = x.Entries[:sz]
+			} else {
+				x.Entries = make([]Entry, sz)
+			}
+			if sz > 0 {
+				_ = x.Entries[sz-1]
+			}
+			for iEntries := uint32(0); iEntries < sz; iEntries++ {
+				var tmp Entry
+				v, err = cbor.UnmarshalContext(ctx, &tmp, v)
+				if err != nil {
+					return b, err
+				}
+				x.Entries[iEntries] = tmp
+			}
+		case "ptrs":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Ptrs) >= int(sz) {
+				x.Ptrs = x.Ptrs[:sz]
+			} else {
+				x.Ptrs = make([]*Entry, sz)
+			}
+			if sz > 0 {
+				_ = x.Ptrs[sz-1]
+			}
+			for iPtrs := uint32(0); iPtrs < sz; iPtrs++ {
+				if x.Ptrs[iPtrs] == nil {
+					x.Ptrs[iPtrs] = new(Entry)
+				}
+				v, err = cbor.UnmarshalContext(ctx, x.Ptrs[iPtrs], v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "by_name":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.ByName == nil && sz > 0 {
+				x.ByName = make(map[string]*Entry, sz)
+			} else if x.ByName != nil {
+				clear(x.ByName)
+			}
+			for iByName := uint32(0); iByName < sz; iByName++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				tmp := new(Entry)
+				v, err = cbor.UnmarshalContext(ctx, tmp, v)
+				if err != nil {
+					return b, err
+				}
+				x.ByName[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Snapshot) DecodeTrusted(ctx context.Context, b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "marker":
+
+			v, err = cbor.UnmarshalContext(ctx, &x.Marker, v)
+			if err != nil {
+				return b, err
+			}
+		case "head":
+
+			v, err = (&x.Head).DecodeTrusted(ctx, v)
+			if err != nil {
+				return b, err
+			}
+		case "tail":
+
+			if x.Tail == nil {
+				x.Tail = new(Entry)
+			}
+			v, err = x.Tail.DecodeTrusted(ctx, v)
+			if err != nil {
+				return b, err
+			}
+		case "entries":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Entries) >= int(sz) {
+				x.Entries = x.Entries[:sz]
+			} else {
+				x.Entries = make([]Entry, sz)
+			}
+			if sz > 0 {
+				_ = x.Entries[sz-1]
+			}
+			for iEntries := uint32(0); iEntries < sz; iEntries++ {
+				var tmp Entry
+				v, err = (&tmp).DecodeTrusted(ctx, v)
+				if err != nil {
+					return b, err
+				}
+				x.Entries[iEntries] = tmp
+			}
+		case "ptrs":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Ptrs) >= int(sz) {
+				x.Ptrs = x.Ptrs[:sz]
+			} else {
+				x.Ptrs = make([]*Entry, sz)
+			}
+			if sz > 0 {
+				_ = x.Ptrs[sz-1]
+			}
+			for iPtrs := uint32(0); iPtrs < sz; iPtrs++ {
+				if x.Ptrs[iPtrs] == nil {
+					x.Ptrs[iPtrs] = new(Entry)
+				}
+				v, err = x.Ptrs[iPtrs].DecodeTrusted(ctx, v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "by_name":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.ByName == nil && sz > 0 {
+				x.ByName = make(map[string]*Entry, sz)
+			} else if x.ByName != nil {
+				clear(x.ByName)
+			}
+			for iByName := uint32(0); iByName < sz; iByName++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				tmp := new(Entry)
+				v, err = tmp.DecodeTrusted(ctx, v)
+				if err != nil {
+					return b, err
+				}
+				x.ByName[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Snapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(context.Background(), b)
+}
+
+// UnmarshalCBORContext implements cbor.ContextUnmarshaler using the Safe path.
+func (x *Snapshot) UnmarshalCBORContext(ctx context.Context, b []byte) ([]byte, error) {
+	return x.DecodeSafe(ctx, b)
+}
+
+// Compile-time checks for Snapshot fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*Marker)(nil) // Marker
+	_ cbor.Unmarshaler = (*Marker)(nil) // Marker
+)
+
+func (x Entry) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("seq")+cbor.Uint64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("data")+cbor.BytesPrefixSize+len(x.Data))
+	return
+}
+
+func (x *Entry) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "seq")
+	b = cbor.AppendUint64(b, x.Seq)
+	b = cbor.AppendString(b, "data")
+	b, err = cbor.AppendInterface(b, x.Data)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Entry) DecodeSafe(ctx context.Context, b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "seq":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seq = tmp
+		case "data":
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Data = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Entry) DecodeTrusted(ctx context.Context, b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if err := ctx.Err(); err != nil {
+		return b, err
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "seq":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seq = tmp
+		case "data":
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadBytesBytes(v, nil)
+			if err != nil {
+				return b, err
+			}
+			x.Data = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Entry) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(context.Background(), b)
+}
+
+// UnmarshalCBORContext implements cbor.ContextUnmarshaler using the Safe path.
+func (x *Entry) UnmarshalCBORContext(ctx context.Context, b []byte) ([]byte, error) {
+	return x.DecodeSafe(ctx, b)
+}
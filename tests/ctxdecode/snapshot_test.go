@@ -0,0 +1,84 @@
+package ctxdecode
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func sample(t *testing.T) []byte {
+	t.Helper()
+	in := Snapshot{
+		Name:    "s",
+		Head:    Entry{Seq: 1},
+		Tail:    &Entry{Seq: 2},
+		Entries: []Entry{{Seq: 3}, {Seq: 4}},
+		Ptrs:    []*Entry{{Seq: 5}},
+		ByName:  map[string]*Entry{"a": {Seq: 6, Data: []byte{1}}},
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return b
+}
+
+func TestSnapshotContextRoundTrip(t *testing.T) {
+	b := sample(t)
+	decoders := []func(*Snapshot, context.Context, []byte) ([]byte, error){(*Snapshot).DecodeSafe, (*Snapshot).DecodeTrusted}
+	for _, dec := range decoders {
+		var out Snapshot
+		rest, err := dec(&out, context.Background(), b)
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("leftover bytes: %d", len(rest))
+		}
+		if out.Tail == nil || out.Tail.Seq != 2 || len(out.Entries) != 2 || out.ByName["a"].Seq != 6 {
+			t.Fatalf("unexpected result: %+v", out)
+		}
+	}
+
+	var out Snapshot
+	if _, err := out.UnmarshalCBOR(b); err != nil {
+		t.Fatalf("UnmarshalCBOR: %v", err)
+	}
+	if out.Head.Seq != 1 {
+		t.Fatalf("Head.Seq = %d", out.Head.Seq)
+	}
+}
+
+func TestSnapshotContextCanceled(t *testing.T) {
+	b := sample(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	decoders := []func(*Snapshot, context.Context, []byte) ([]byte, error){(*Snapshot).DecodeSafe, (*Snapshot).DecodeTrusted}
+	for _, dec := range decoders {
+		var out Snapshot
+		if _, err := dec(&out, ctx, b); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+		if out.Name != "" {
+			t.Fatalf("decoded %q after cancellation", out.Name)
+		}
+	}
+}
+
+// TestSnapshotContextCanceledMidway cancels while the hand-written
+// Marker field is decoded; the following nested Entry must not be read.
+func TestSnapshotContextCanceledMidway(t *testing.T) {
+	b := sample(t)
+	decoders := []func(*Snapshot, context.Context, []byte) ([]byte, error){(*Snapshot).DecodeSafe, (*Snapshot).DecodeTrusted}
+	for _, dec := range decoders {
+		ctx, cancel := context.WithCancel(context.Background())
+		var out Snapshot
+		out.Marker.onDecode = cancel
+		if _, err := dec(&out, ctx, b); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+		if out.Name != "s" || out.Head.Seq != 0 {
+			t.Fatalf("unexpected progress: name=%q head=%d", out.Name, out.Head.Seq)
+		}
+	}
+}
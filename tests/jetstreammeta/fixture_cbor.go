@@ -1,3 +1,5 @@
 // Code generated by cborgen DO NOT EDIT.
 
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i .
+
 package jetstreammeta
@@ -104,3 +104,14 @@ func TestBuildMetaSnapshotFixture_TrustedDecode(t *testing.T) {
 }
 
 func testTime() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+func TestBuildMetaSnapshotFixture_MsgsizeCoversStreams(t *testing.T) {
+	snap := BuildMetaSnapshotFixture(2, 2)
+	var sum int
+	for _, s := range snap.Streams {
+		sum += s.Msgsize()
+	}
+	if got := snap.Msgsize(); got < sum {
+		t.Fatalf("MetaSnapshot.Msgsize() = %d, less than sum of streams %d", got, sum)
+	}
+}
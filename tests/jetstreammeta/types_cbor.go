@@ -9,7 +9,27 @@ import (
 )
 
 func (x ClientInfo) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("host") + cbor.StringPrefixSize + len(x.Host) + cbor.StringPrefixSize + len("id") + cbor.Uint64Size + cbor.StringPrefixSize + len("acc") + cbor.StringPrefixSize + len(x.Account) + cbor.StringPrefixSize + len("svc") + cbor.StringPrefixSize + len(x.Service) + cbor.StringPrefixSize + len("user") + cbor.StringPrefixSize + len(x.User) + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("lang") + cbor.StringPrefixSize + len(x.Lang) + cbor.StringPrefixSize + len("ver") + cbor.StringPrefixSize + len(x.Version) + cbor.StringPrefixSize + len("rtt") + cbor.DurationSize + cbor.StringPrefixSize + len("server") + cbor.StringPrefixSize + len(x.Server) + cbor.StringPrefixSize + len("cluster") + cbor.StringPrefixSize + len(x.Cluster) + cbor.StringPrefixSize + len("alts") + cbor.ArrayHeaderSize + len(x.Alternates)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("jwt") + cbor.StringPrefixSize + len(x.Jwt) + cbor.StringPrefixSize + len("issuer_key") + cbor.StringPrefixSize + len(x.IssuerKey) + cbor.StringPrefixSize + len("name_tag") + cbor.StringPrefixSize + len(x.NameTag) + cbor.StringPrefixSize + len("tags") + cbor.ArrayHeaderSize + len(x.Tags)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("kind") + cbor.StringPrefixSize + len(x.Kind) + cbor.StringPrefixSize + len("client_type") + cbor.StringPrefixSize + len(x.ClientType) + cbor.StringPrefixSize + len("client_id") + cbor.StringPrefixSize + len(x.MQTTClient) + cbor.StringPrefixSize + len("nonce") + cbor.StringPrefixSize + len(x.Nonce)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("host")+cbor.StringPrefixSize+len(x.Host))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.Uint64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("acc")+cbor.StringPrefixSize+len(x.Account))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("svc")+cbor.StringPrefixSize+len(x.Service))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("user")+cbor.StringPrefixSize+len(x.User))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("lang")+cbor.StringPrefixSize+len(x.Lang))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ver")+cbor.StringPrefixSize+len(x.Version))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("rtt")+cbor.DurationSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("server")+cbor.StringPrefixSize+len(x.Server))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("cluster")+cbor.StringPrefixSize+len(x.Cluster))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("alts")+cbor.ArrayHeaderSize+len(x.Alternates)*cbor.StringPrefixSize+cbor.StringsLen(x.Alternates))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("jwt")+cbor.StringPrefixSize+len(x.Jwt))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("issuer_key")+cbor.StringPrefixSize+len(x.IssuerKey))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name_tag")+cbor.StringPrefixSize+len(x.NameTag))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tags")+cbor.ArrayHeaderSize+len(x.Tags)*cbor.StringPrefixSize+cbor.StringsLen(x.Tags))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("kind")+cbor.StringPrefixSize+len(x.Kind))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("client_type")+cbor.StringPrefixSize+len(x.ClientType))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("client_id")+cbor.StringPrefixSize+len(x.MQTTClient))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("nonce")+cbor.StringPrefixSize+len(x.Nonce))
 	return
 }
 
@@ -658,7 +678,12 @@ func (x *ClientInfo) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x RaftGroup) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("peers") + cbor.ArrayHeaderSize + len(x.Peers)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("cluster") + cbor.StringPrefixSize + len(x.Cluster) + cbor.StringPrefixSize + len("preferred") + cbor.StringPrefixSize + len(x.Preferred) + cbor.StringPrefixSize + len("scale_up") + cbor.BoolSize
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("peers")+cbor.ArrayHeaderSize+len(x.Peers)*cbor.StringPrefixSize+cbor.StringsLen(x.Peers))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("cluster")+cbor.StringPrefixSize+len(x.Cluster))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("preferred")+cbor.StringPrefixSize+len(x.Preferred))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("scale_up")+cbor.BoolSize)
 	return
 }
 
@@ -890,8 +915,17 @@ func (x *RaftGroup) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// Compile-time checks for RaftGroup fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*StorageType)(nil) // Storage
+	_ cbor.Unmarshaler = (*StorageType)(nil) // Storage
+)
+
 func (x SequencePair) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("consumer_seq") + cbor.Uint64Size + cbor.StringPrefixSize + len("stream_seq") + cbor.Uint64Size
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("consumer_seq")+cbor.Uint64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("stream_seq")+cbor.Uint64Size)
 	return
 }
 
@@ -1002,7 +1036,9 @@ func (x *SequencePair) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x Pending) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("sequence") + cbor.Uint64Size + cbor.StringPrefixSize + len("ts") + cbor.Int64Size
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("sequence")+cbor.Uint64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ts")+cbor.Int64Size)
 	return
 }
 
@@ -1112,11 +1148,20 @@ func (x *Pending) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+func (x ConsumerState) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("delivered")+x.Delivered.Msgsize())
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ack_floor")+x.AckFloor.Msgsize())
+	return
+}
+
 func (x *ConsumerState) MarshalCBOR(b []byte) ([]byte, error) {
 	if x == nil {
 		return cbor.AppendNil(b), nil
 	}
 
+	b = cbor.Require(b, x.Msgsize())
+
 	count := uint32(2)
 	if len(x.Pending) != 0 {
 		count++
@@ -1370,7 +1415,13 @@ func (x *ConsumerState) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x consumerAssignment) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("stream") + cbor.StringPrefixSize + len(x.Stream)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("client")+cbor.PtrMsgsize(x.Client))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("created")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("stream")+cbor.StringPrefixSize+len(x.Stream))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("group")+cbor.PtrMsgsize(x.Group))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("state")+cbor.PtrMsgsize(x.State))
 	return
 }
 
@@ -1594,7 +1645,11 @@ func (x *consumerAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x streamAssignment) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("sync") + cbor.StringPrefixSize + len(x.Sync)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("client")+cbor.PtrMsgsize(x.Client))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("created")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("group")+cbor.PtrMsgsize(x.Group))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("sync")+cbor.StringPrefixSize+len(x.Sync))
 	return
 }
 
@@ -1772,7 +1827,13 @@ func (x *streamAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x WriteableConsumerAssignment) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("stream") + cbor.StringPrefixSize + len(x.Stream)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("client")+cbor.PtrMsgsize(x.Client))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("created")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("stream")+cbor.StringPrefixSize+len(x.Stream))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("group")+cbor.PtrMsgsize(x.Group))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("state")+cbor.PtrMsgsize(x.State))
 	return
 }
 
@@ -1996,7 +2057,12 @@ func (x *WriteableConsumerAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x WriteableStreamAssignment) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("created") + cbor.TimeSize + cbor.StringPrefixSize + len("sync") + cbor.StringPrefixSize + len(x.Sync)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("client")+cbor.PtrMsgsize(x.Client))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("created")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("group")+cbor.PtrMsgsize(x.Group))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("sync")+cbor.StringPrefixSize+len(x.Sync))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("consumers")+cbor.PtrSliceMsgsize(x.Consumers))
 	return
 }
 
@@ -2240,7 +2306,8 @@ func (x *WriteableStreamAssignment) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x MetaSnapshot) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("streams") + cbor.ArrayHeaderSize + len(x.Streams)*0
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("streams")+cbor.SliceMsgsize(x.Streams))
 	return
 }
 
@@ -2371,7 +2438,10 @@ func (x *MetaSnapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x StreamConfigSnapshot) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("subjects") + cbor.ArrayHeaderSize + len(x.Subjects)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("metadata") + cbor.MapHeaderSize + len(x.Metadata)*(cbor.StringPrefixSize+cbor.StringPrefixSize)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("subjects")+cbor.ArrayHeaderSize+len(x.Subjects)*cbor.StringPrefixSize+cbor.StringsLen(x.Subjects))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("metadata")+cbor.MapHeaderSize+len(x.Metadata)*(cbor.StringPrefixSize+cbor.StringPrefixSize)+cbor.MapKeysLen(x.Metadata)+cbor.MapValuesLen(x.Metadata))
 	return
 }
 
@@ -2596,8 +2666,18 @@ func (x *StreamConfigSnapshot) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
 
+// Compile-time checks for StreamConfigSnapshot fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*StorageType)(nil) // Storage
+	_ cbor.Unmarshaler = (*StorageType)(nil) // Storage
+)
+
 func (x ConsumerConfigSnapshot) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("durable") + cbor.StringPrefixSize + len(x.Durable) + cbor.StringPrefixSize + len("mem_storage") + cbor.BoolSize + cbor.StringPrefixSize + len("metadata") + cbor.MapHeaderSize + len(x.Metadata)*(cbor.StringPrefixSize+cbor.StringPrefixSize)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("durable")+cbor.StringPrefixSize+len(x.Durable))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("mem_storage")+cbor.BoolSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("metadata")+cbor.MapHeaderSize+len(x.Metadata)*(cbor.StringPrefixSize+cbor.StringPrefixSize)+cbor.MapKeysLen(x.Metadata)+cbor.MapValuesLen(x.Metadata))
 	return
 }
 
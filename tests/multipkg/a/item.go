@@ -0,0 +1,9 @@
+// Package a generates an Item type whose name package b reuses for a
+// type of its own. The two are generated in one cborgen run over
+// tests/multipkg.
+package a
+
+// Item is generated here; b.Item is not.
+type Item struct {
+	Name string `cbor:"name"`
+}
@@ -0,0 +1,101 @@
+// Code generated by cborgen DO NOT EDIT.
+
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i .
+
+package a
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+func (x Item) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	return
+}
+
+func (x *Item) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 1)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Item) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Item) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Item) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -0,0 +1,28 @@
+// Package b declares an Item unrelated to a.Item, encoded by hand. A
+// cborgen run over tests/multipkg must not treat it as generated.
+package b
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+// Item is encoded as its name alone, through hand-written methods.
+type Item struct {
+	_    struct{} `cbor:",ignore"`
+	Name string
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (x *Item) MarshalCBOR(b []byte) ([]byte, error) {
+	return cbor.AppendString(b, x.Name), nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (x *Item) UnmarshalCBOR(b []byte) ([]byte, error) {
+	var err error
+	x.Name, b, err = cbor.ReadStringBytes(b)
+	return b, err
+}
+
+// Holder nests b.Item, which has no Msgsize or DecodeTrusted.
+type Holder struct {
+	Item Item `cbor:"item"`
+}
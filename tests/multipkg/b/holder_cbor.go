@@ -0,0 +1,100 @@
+// Code generated by cborgen DO NOT EDIT.
+
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i .
+
+package b
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+func (x *Holder) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.AppendMapHeader(b, 1)
+	var err error
+	b = cbor.AppendString(b, "item")
+	b, err = x.Item.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Holder) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "item":
+
+			v, err = x.Item.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Holder) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "item":
+
+			v, err = x.Item.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Holder) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// Compile-time checks for Holder fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*Item)(nil) // Item
+	_ cbor.Unmarshaler = (*Item)(nil) // Item
+)
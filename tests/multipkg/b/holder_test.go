@@ -0,0 +1,24 @@
+package b
+
+import (
+	"testing"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func TestHolderUsesHandWrittenItem(t *testing.T) {
+	in := Holder{Item: Item{Name: "x"}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if diag, _, _ := cbor.DiagBytes(b); diag != `{"item": "x"}` {
+		t.Fatalf("diag = %s", diag)
+	}
+	for _, decode := range []func(*Holder, []byte) ([]byte, error){(*Holder).DecodeSafe, (*Holder).DecodeTrusted} {
+		var out Holder
+		if _, err := decode(&out, b); err != nil || out.Item.Name != "x" {
+			t.Fatalf("decode = %+v, %v", out, err)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package tests
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
+	"math"
 	"testing"
 
 	cbor "github.com/synadia-labs/cbor.go/runtime"
@@ -76,6 +79,14 @@ func TestRFCExamplesDiagAndWellFormed(t *testing.T) {
 				t.Fatalf("diag mismatch: got %q want %q (hex %s)", got, ex.diag, ex.hex)
 			}
 
+			parsed, err := cbor.ParseDiag(ex.diag)
+			if err != nil {
+				t.Fatalf("ParseDiag error: %v", err)
+			}
+			if !bytes.Equal(parsed, msg) {
+				t.Fatalf("ParseDiag mismatch: got %x want %s", parsed, ex.hex)
+			}
+
 			rest2, err := cbor.ValidateWellFormedBytes(msg)
 			if err != nil {
 				t.Fatalf("ValidateWellFormedBytes error: %v", err)
@@ -86,3 +97,146 @@ func TestRFCExamplesDiagAndWellFormed(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDiagForms(t *testing.T) {
+	cases := []struct {
+		diag string
+		hex  string
+	}{
+		{"-1000", "3903e7"},
+		{"18446744073709551615", "1bffffffffffffffff"},
+		{"-18446744073709551616", "3bffffffffffffffff"},
+		{"1.5", "f93e00"},
+		{"1.0e+300", "fb7e37e43c8800759c"},
+		{"-4.1", "fbc010666666666666"},
+		{"Infinity", "f97c00"},
+		{"-Infinity", "f9fc00"},
+		{"NaN", "f97e00"},
+		{"false", "f4"},
+		{"true", "f5"},
+		{"null", "f6"},
+		{"undefined", "f7"},
+		{"simple(16)", "f0"},
+		{"simple(255)", "f8ff"},
+		{"h''", "40"},
+		{"h'01 02'", "420102"},
+		{"b64'AQI='", "420102"},
+		{"b64'-_8'", "42fbff"},
+		{"\"\\u00fc\"", "62c3bc"},
+		{"\"\\/\"", "612f"},
+		{"\"\\uD83D\\uDE00\"", "64f09f9880"},
+		{"-0.0", "f98000"},
+		{"-0", "f98000"},
+		{"0.0", "f90000"},
+		{"(_ h'0102', h'030405')", "5f42010243030405ff"},
+		{"(_ \"strea\", \"ming\")", "7f657374726561646d696e67ff"},
+		{"{_ \"a\": 1, \"b\": [_ ]}", "bf61610161629fffff"},
+		{" [ 1 , [2, 3], {} ] ", "8301820203a0"},
+		{"24(h'6449455446')", "d818456449455446"},
+		{"32(\"http://www.example.com\")", "d82076687474703a2f2f7777772e6578616d706c652e636f6d"},
+	}
+	for _, tc := range cases {
+		got, err := cbor.ParseDiag(tc.diag)
+		if err != nil {
+			t.Fatalf("ParseDiag(%q) error: %v", tc.diag, err)
+		}
+		if hex.EncodeToString(got) != tc.hex {
+			t.Fatalf("ParseDiag(%q) = %x want %s", tc.diag, got, tc.hex)
+		}
+	}
+}
+
+// TestParseDiagRoundTrip checks that DiagBytes output parses back to the
+// same encoding for strings needing escapes and for -0.
+func TestParseDiagRoundTrip(t *testing.T) {
+	var msgs [][]byte
+	for _, s := range []string{"tab\there", "bell\a", "\x00\x1f\x7f", "quote\"back\\", "\u2028", "\U000e0001", "😀"} {
+		msgs = append(msgs, cbor.AppendString(nil, s))
+	}
+	msgs = append(msgs, cbor.AppendFloat16(nil, float32(math.Copysign(0, -1))), cbor.AppendFloat64(nil, math.Copysign(0, -1)))
+	for _, msg := range msgs {
+		diag, _, err := cbor.DiagBytes(msg)
+		if err != nil {
+			t.Fatalf("DiagBytes(%x) error: %v", msg, err)
+		}
+		got, err := cbor.ParseDiag(diag)
+		if err != nil {
+			t.Fatalf("ParseDiag(%s) error: %v", diag, err)
+		}
+		want := msg
+		if msg[0] == 0xfb {
+			want = []byte{0xf9, 0x80, 0x00} // shortest width
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("ParseDiag(%s) = %x want %x", diag, got, want)
+		}
+	}
+}
+
+func TestParseDiagErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"[1, 2",
+		"{1 2}",
+		"\"abc",
+		"h'0'",
+		"1(2",
+		"(_ )",
+		"(_ \"a\", h'01')",
+		"simple(256)",
+		"1 2",
+		"-",
+		"bogus",
+		"1 /* unterminated",
+		"/*/ 1",
+		"\"\\x41\"",
+	} {
+		_, err := cbor.ParseDiag(in)
+		if err == nil {
+			t.Fatalf("ParseDiag(%q) expected error", in)
+		}
+		var se cbor.DiagSyntaxError
+		if !errors.As(err, &se) {
+			t.Fatalf("ParseDiag(%q) error %T is not DiagSyntaxError", in, err)
+		}
+	}
+}
+
+func TestDiagBytesAnnotated(t *testing.T) {
+	cases := []struct {
+		hex  string
+		want string
+	}{
+		{"c11a6553f100", "/*epoch*/1(1700000000)"},
+		{"d82550123e4567e89b12d3a456426614174000", "/*uuid*/37(h'123e4567e89b12d3a456426614174000')"},
+		{"82c249010000000000000000d90fa001", "[/*pos-bignum*/2(h'010000000000000000'), 4000(1)]"},
+	}
+	for _, tc := range cases {
+		b, _ := hex.DecodeString(tc.hex)
+		got, rest, err := cbor.DiagBytesAnnotated(b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("DiagBytesAnnotated(%s) error: %v rest:%d", tc.hex, err, len(rest))
+		}
+		if got != tc.want {
+			t.Fatalf("DiagBytesAnnotated(%s) = %s want %s", tc.hex, got, tc.want)
+		}
+		// The comments must not get in the way of parsing the output back.
+		back, err := cbor.ParseDiag(got)
+		if err != nil {
+			t.Fatalf("ParseDiag(%q) error: %v", got, err)
+		}
+		if hex.EncodeToString(back) != tc.hex {
+			t.Fatalf("ParseDiag(%q) = %x want %s", got, back, tc.hex)
+		}
+	}
+
+	cbor.RegisterTagName(4000, "app-id")
+	defer cbor.RegisterTagName(4000, "")
+	b, _ := hex.DecodeString("d90fa001")
+	if got, _, _ := cbor.DiagBytesAnnotated(b); got != "/*app-id*/4000(1)" {
+		t.Fatalf("registered name: got %s", got)
+	}
+	if got, _, _ := cbor.DiagBytes(b); got != "4000(1)" {
+		t.Fatalf("DiagBytes must not annotate: got %s", got)
+	}
+}
@@ -1,11 +1,20 @@
 package tests
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
+	"regexp"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
+	"unsafe"
 
 	cbor "github.com/synadia-labs/cbor.go/runtime"
 )
@@ -36,6 +45,110 @@ func TestDeterministicWriterOrder(t *testing.T) {
 	}
 }
 
+// TestDeterministicKeysOrder checks that AppendMapStrStrDeterministicKeys
+// follows the caller's key order and matches AppendMapStrStrDeterministic
+// when given nil keys or keys that are not exactly the map's, sorted.
+func TestDeterministicKeysOrder(t *testing.T) {
+	m := map[string]string{"bb": "2", "a": "1", "c": "3"}
+	want := cbor.AppendMapStrStrDeterministic(nil, m)
+	// Shorter keys sort first: "a", "c", "bb".
+	if got := cbor.AppendMapStrStrDeterministicKeys(nil, m, []string{"a", "c", "bb"}); !bytesEqual(got, want) {
+		t.Fatalf("sorted keys: got %x want %x", got, want)
+	}
+	if got := cbor.AppendMapStrStrDeterministicKeys(nil, m, nil); !bytesEqual(got, want) {
+		t.Fatalf("nil keys: got %x want %x", got, want)
+	}
+	mismatched := map[string][]string{
+		"empty":     {},
+		"missing":   {"a", "c", "zz"},
+		"duplicate": {"a", "a", "bb"},
+		"extra":     {"a", "c", "bb", "d"},
+		"unsorted":  {"a", "bb", "c"},
+	}
+	for name, keys := range mismatched {
+		if got := cbor.AppendMapStrStrDeterministicKeys([]byte{0x01}, m, keys); !bytesEqual(got, append([]byte{0x01}, want...)) {
+			t.Fatalf("%s keys: got %x want 01%x", name, got, want)
+		}
+	}
+	if got, want := cbor.AppendMapStrStrDeterministicKeys(nil, nil, []string{}), []byte{0xa0}; !bytesEqual(got, want) {
+		t.Fatalf("empty map: got %x want %x", got, want)
+	}
+}
+
+// TestReadOrderedMapZeroCopy checks that ReadOrderedMapBytesWithOptions
+// returns the same pairs with and without ZeroCopy, aliasing the input
+// only when it is set.
+func TestReadOrderedMapZeroCopy(t *testing.T) {
+	for _, h := range []string{
+		"a261620161610263",   // {"b": 1, "a": 2} followed by 0x63
+		"bf616201616102ff63", // indefinite form of the same map
+	} {
+		b := mustHex(t, h)
+		copied, rest, err := cbor.ReadOrderedMapBytes(b)
+		if err != nil {
+			t.Fatalf("%s: ReadOrderedMapBytes error: %v", h, err)
+		}
+		if !bytesEqual(rest, []byte{0x63}) {
+			t.Fatalf("%s: rest = %x", h, rest)
+		}
+		aliased, rest, err := cbor.ReadOrderedMapBytesWithOptions(b, cbor.ReadOrderedMapOptions{ZeroCopy: true})
+		if err != nil {
+			t.Fatalf("%s: zero-copy error: %v", h, err)
+		}
+		if !bytesEqual(rest, []byte{0x63}) {
+			t.Fatalf("%s: zero-copy rest = %x", h, rest)
+		}
+		if len(copied) != 2 || len(aliased) != 2 {
+			t.Fatalf("%s: got %d and %d pairs", h, len(copied), len(aliased))
+		}
+		for i := range copied {
+			if !bytesEqual(copied[i].Key, aliased[i].Key) || !bytesEqual(copied[i].Value, aliased[i].Value) {
+				t.Fatalf("%s: pair %d differs: %x/%x vs %x/%x", h, i,
+					copied[i].Key, copied[i].Value, aliased[i].Key, aliased[i].Value)
+			}
+		}
+		if !bytesEqual(aliased[0].Key, mustHex(t, "6162")) {
+			t.Fatalf("%s: first key = %x", h, aliased[0].Key)
+		}
+		// In both encodings the second value, 0x02, is at offset 6.
+		if &aliased[1].Value[0] != &b[6] {
+			t.Fatalf("%s: zero-copy value does not alias the input", h)
+		}
+		if &copied[1].Value[0] == &b[6] {
+			t.Fatalf("%s: default value aliases the input", h)
+		}
+	}
+}
+
+// TestReadBytesCopy checks that ReadBytesBytes aliases definite-length
+// input while ReadBytesCopy always returns a fresh, non-nil slice.
+func TestReadBytesCopy(t *testing.T) {
+	b := mustHex(t, "4301020340")
+	zc, rest, err := cbor.ReadBytesBytes(b, nil)
+	if err != nil || !bytesEqual(zc, []byte{1, 2, 3}) {
+		t.Fatalf("ReadBytesBytes = %x, %v", zc, err)
+	}
+	if &zc[0] != &b[1] {
+		t.Fatalf("ReadBytesBytes copied a definite-length string")
+	}
+	cp, rest2, err := cbor.ReadBytesCopy(b)
+	if err != nil || !bytesEqual(cp, zc) || !bytesEqual(rest2, rest) {
+		t.Fatalf("ReadBytesCopy = %x, %x, %v", cp, rest2, err)
+	}
+	if &cp[0] == &b[1] {
+		t.Fatalf("ReadBytesCopy aliases the input")
+	}
+	empty, rest, err := cbor.ReadBytesCopy(rest)
+	if err != nil || empty == nil || len(empty) != 0 || len(rest) != 0 {
+		t.Fatalf("ReadBytesCopy(h'') = %#v, %x, %v", empty, rest, err)
+	}
+	// Indefinite-length strings are assembled either way.
+	cp, _, err = cbor.ReadBytesCopy(mustHex(t, "5f42010241ffff"))
+	if err != nil || !bytesEqual(cp, []byte{1, 2, 0xff}) {
+		t.Fatalf("ReadBytesCopy(indefinite) = %x, %v", cp, err)
+	}
+}
+
 // TestDuplicateKeyDetection validates that ReadMapNoDupBytes reports
 // ErrDuplicateMapKey when a map contains duplicate keys.
 func TestDuplicateKeyDetection(t *testing.T) {
@@ -47,6 +160,205 @@ func TestDuplicateKeyDetection(t *testing.T) {
 	}
 }
 
+// TestDeterministicUint64Marshaler checks that the pairs come out in
+// ascending key order across encoded widths and that the result matches
+// the generic deterministic encoder.
+func TestDeterministicUint64Marshaler(t *testing.T) {
+	m := map[uint64]*cbor.Raw{}
+	for _, k := range []uint64{1 << 40, 24, 3, 300, 0, 1 << 20} {
+		r := cbor.Raw(cbor.AppendUint64(nil, k+1))
+		m[k] = &r
+	}
+	got, err := cbor.AppendMapDeterministicUint64Marshaler(nil, m)
+	if err != nil {
+		t.Fatalf("AppendMapDeterministicUint64Marshaler error: %v", err)
+	}
+	want, err := cbor.AppendMapDeterministic(nil, m, cbor.EncKeyUint64, func(dst []byte, v *cbor.Raw) ([]byte, error) {
+		return v.MarshalCBOR(dst)
+	})
+	if err != nil {
+		t.Fatalf("AppendMapDeterministic error: %v", err)
+	}
+	if !bytesEqual(got, want) {
+		t.Fatalf("got %x want %x", got, want)
+	}
+	if _, err := cbor.AppendMapDeterministicUint64Marshaler(nil, map[uint64]int{1: 1}); err == nil {
+		t.Fatalf("expected error for non-Marshaler values")
+	}
+}
+
+// TestAppendMapDeterministicE checks that the error-returning variant
+// matches AppendMapDeterministic and stops at the first failing key.
+func TestAppendMapDeterministicE(t *testing.T) {
+	m := map[string]int64{"bb": 2, "a": 1, "ccc": 3}
+	encKey := func(dst []byte, k string) ([]byte, error) {
+		if k == "" {
+			return dst, errors.New("empty key")
+		}
+		return cbor.AppendString(dst, k), nil
+	}
+	got, err := cbor.AppendMapDeterministicE(nil, m, encKey, cbor.EncValInt64)
+	if err != nil {
+		t.Fatalf("AppendMapDeterministicE error: %v", err)
+	}
+	want, err := cbor.AppendMapDeterministic(nil, m, cbor.EncKeyString, cbor.EncValInt64)
+	if err != nil {
+		t.Fatalf("AppendMapDeterministic error: %v", err)
+	}
+	if !bytesEqual(got, want) {
+		t.Fatalf("got %x want %x", got, want)
+	}
+
+	m[""] = 0
+	prefix := []byte{0x01}
+	out, err := cbor.AppendMapDeterministicE(prefix, m, encKey, cbor.EncValInt64)
+	if err == nil || err.Error() != "empty key" {
+		t.Fatalf("err = %v, want empty key", err)
+	}
+	if !bytesEqual(out, prefix) {
+		t.Fatalf("out = %x, want %x", out, prefix)
+	}
+}
+
+// TestAppendMapDeterministicStrBytes_SortOrder checks that entries come
+// out in RFC 8949 key order (shorter encoded keys first, then bytewise)
+// whatever the lengths of their byte string values.
+func TestAppendMapDeterministicStrBytes_SortOrder(t *testing.T) {
+	cases := []struct {
+		name string
+		m    map[string][]byte
+		keys []string
+		hex  string
+	}{
+		{"equal_values", map[string][]byte{"b": {1}, "c": {3}, "a": {2}},
+			[]string{"a", "b", "c"}, "a3616141026162410161634103"},
+		// Value lengths run against key order and do not change it.
+		{"mixed_values", map[string][]byte{"aa": {}, "b": {1, 2, 3}, "a": {1, 2}},
+			[]string{"a", "b", "aa"}, "a3616142010261624301020362616140"},
+		{"empty_values", map[string][]byte{"y": nil, "x": {}},
+			[]string{"x", "y"}, "a2617840617940"},
+		{"single", map[string][]byte{"k": {0xff}},
+			[]string{"k"}, "a1616b41ff"},
+	}
+	for _, tc := range cases {
+		got := cbor.AppendMapDeterministicStrBytes(nil, tc.m)
+		want := mustHex(t, tc.hex)
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %x want %x", tc.name, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%s: byte %d = %02x want %02x (got %x)", tc.name, i, got[i], want[i], got)
+			}
+		}
+		sz, o, err := cbor.ReadMapHeaderBytes(got)
+		if err != nil || int(sz) != len(tc.keys) {
+			t.Fatalf("%s: map header %d, %v", tc.name, sz, err)
+		}
+		for _, k := range tc.keys {
+			var key string
+			key, o, err = cbor.ReadStringBytes(o)
+			if err != nil || key != k {
+				t.Fatalf("%s: key %q, %v want %q", tc.name, key, err, k)
+			}
+			if _, o, err = cbor.ReadBytesBytes(o, nil); err != nil {
+				t.Fatalf("%s: value for %q: %v", tc.name, k, err)
+			}
+		}
+	}
+}
+
+// TestValidateMapNoDupKeys checks duplicate detection in definite and
+// indefinite maps, including keys that differ only in encoding, and that
+// a huge declared length is not trusted.
+func TestValidateMapNoDupKeys(t *testing.T) {
+	cases := []struct {
+		name, hex string
+		err       error
+	}{
+		{"definite", "a2616101616202f5", nil},
+		{"indefinite", "bf616101616202fff5", nil},
+		{"indefinite_dup", "bf616101616102ff", cbor.ErrDuplicateMapKey},
+		// 1 and 1 encoded as 0x1801 are different raw keys.
+		{"raw_distinct", "a201f41801f4f5", nil},
+		{"huge_len", "ba7fffffff6161", cbor.ErrShortBytes},
+		// Does not fit an int on 32-bit platforms.
+		{"huge_len_uint32", "baffffffff6161", cbor.ErrShortBytes},
+		{"not_map", "80", nil},
+	}
+	for _, tc := range cases {
+		rest, err := cbor.ValidateMapNoDupKeys(mustHex(t, tc.hex))
+		switch {
+		case tc.name == "not_map":
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.name)
+			}
+		case tc.err != nil:
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("%s: got %v want %v", tc.name, err, tc.err)
+			}
+		case err != nil:
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		case !bytesEqual(rest, []byte{0xf5}):
+			t.Fatalf("%s: rest = %x", tc.name, rest)
+		}
+	}
+}
+
+// TestMatchMapKeys checks that the first key in any set picks that set,
+// in definite and indefinite-length maps, skipping non-text keys.
+func TestMatchMapKeys(t *testing.T) {
+	sets := [][]string{{"a"}, {"b", "c"}}
+	cases := []struct {
+		name, hex string
+		want      int
+	}{
+		// {1: 0, "c": 1, "a": 2}
+		{"definite", "a30100616301616102", 1},
+		{"indefinite", "bf0100616301616102ff", 1},
+		{"indefinite_none", "bf617a01ff", -1},
+		{"empty_indefinite", "bfff", -1},
+	}
+	for _, tc := range cases {
+		got, err := cbor.MatchMapKeys(mustHex(t, tc.hex), sets...)
+		if err != nil || got != tc.want {
+			t.Fatalf("%s: got %d, %v want %d", tc.name, got, err, tc.want)
+		}
+	}
+	if _, err := cbor.MatchMapKeys(mustHex(t, "bf617a01"), sets...); !errors.Is(err, cbor.ErrShortBytes) {
+		t.Fatalf("missing break: got %v want %v", err, cbor.ErrShortBytes)
+	}
+}
+
+// TestAppendRawMapNoDup verifies that duplicate raw keys are rejected
+// before anything is appended and that unique pairs keep their order.
+func TestAppendRawMapNoDup(t *testing.T) {
+	pairs := []cbor.RawPair{
+		{Key: cbor.AppendString(nil, "b"), Value: cbor.AppendUint64(nil, 1)},
+		{Key: cbor.AppendString(nil, "a"), Value: cbor.AppendUint64(nil, 2)},
+	}
+	got, err := cbor.AppendRawMapNoDup(nil, pairs)
+	if err != nil {
+		t.Fatalf("AppendRawMapNoDup error: %v", err)
+	}
+	if want := mustHex(t, "a2616201616102"); !bytesEqual(got, want) {
+		t.Fatalf("AppendRawMapNoDup = %x want %x", got, want)
+	}
+	if _, err := cbor.ReadMapNoDupBytes(got); err != nil {
+		t.Fatalf("ReadMapNoDupBytes error: %v", err)
+	}
+
+	prefix := []byte{0x01}
+	pairs = append(pairs, cbor.RawPair{Key: cbor.AppendString(nil, "b"), Value: cbor.AppendUint64(nil, 3)})
+	got, err = cbor.AppendRawMapNoDup(prefix, pairs)
+	if !errors.Is(err, cbor.ErrDuplicateMapKey) {
+		t.Fatalf("expected ErrDuplicateMapKey, got %v", err)
+	}
+	if !bytesEqual(got, prefix) {
+		t.Fatalf("buffer modified on error: %x", got)
+	}
+}
+
 // TestStrictModeLengthAndIndefinite exercises strict and deterministic
 // decoding behaviors similar to the prototype:
 //
@@ -69,6 +381,19 @@ func TestStrictModeLengthAndIndefinite(t *testing.T) {
 		t.Fatalf("expected ErrIndefiniteForbidden, got %v", err)
 	}
 
+	// Indefinite map forbidden in deterministic mode: 0xbf 0xff (empty indefinite map)
+	indMap := mustHex(t, "bfff")
+	r = cbor.NewReaderBytes(indMap)
+	r.SetDeterministicDecode(true)
+	if _, _, err := r.ReadMapStart(); !errors.Is(err, cbor.ErrIndefiniteForbidden) {
+		t.Fatalf("expected ErrIndefiniteForbidden for map, got %v", err)
+	}
+	// Without deterministic mode the same map is accepted.
+	r = cbor.NewReaderBytes(indMap)
+	if _, indef, err := r.ReadMapStart(); err != nil || !indef {
+		t.Fatalf("expected indefinite map start, got indef=%v err=%v", indef, err)
+	}
+
 	// Non-canonical map length: 0xb8 0x02 (map of length 2 encoded as uint8)
 	ncMap := mustHex(t, "b802")
 	r = cbor.NewReaderBytes(ncMap)
@@ -110,6 +435,76 @@ func TestStrictModeLengthAndIndefinite(t *testing.T) {
 	}
 }
 
+// TestReaderReadSimpleValue checks that Reader.ReadSimpleValue advances
+// the buffer and, in strict mode, rejects two-byte encodings of values
+// below 32 (RFC 8949 section 3.3).
+func TestReaderReadSimpleValue(t *testing.T) {
+	r := cbor.NewReaderBytes(mustHex(t, "f0f8ff"))
+	r.SetStrictDecode(true)
+	for _, want := range []uint8{16, 255} {
+		v, err := r.ReadSimpleValue()
+		if err != nil {
+			t.Fatalf("ReadSimpleValue error: %v", err)
+		}
+		if v != want {
+			t.Fatalf("got %d want %d", v, want)
+		}
+	}
+	if len(r.Remaining()) != 0 {
+		t.Fatalf("leftover: %x", r.Remaining())
+	}
+
+	for _, h := range []string{"f818", "f81b", "f810"} {
+		r = cbor.NewReaderBytes(mustHex(t, h))
+		r.SetStrictDecode(true)
+		if _, err := r.ReadSimpleValue(); !errors.Is(err, cbor.ErrInvalidSimpleValue) {
+			t.Fatalf("%s: expected ErrInvalidSimpleValue, got %v", h, err)
+		}
+		if len(r.Remaining()) != 2 {
+			t.Fatalf("%s: buffer advanced on error", h)
+		}
+		// Lenient mode keeps accepting them.
+		r = cbor.NewReaderBytes(mustHex(t, h))
+		if _, err := r.ReadSimpleValue(); err != nil {
+			t.Fatalf("%s: lenient ReadSimpleValue error: %v", h, err)
+		}
+	}
+}
+
+// TestWriteReservedSimpleValues checks that the simple values 24..31,
+// which have no well-formed encoding, are rejected by every writer while
+// their neighbours 23 and 32 still encode.
+func TestWriteReservedSimpleValues(t *testing.T) {
+	if got := cbor.AppendSimpleValue(nil, 23); !bytesEqual(got, mustHex(t, "f7")) {
+		t.Fatalf("simple(23) = %x", got)
+	}
+	if got := cbor.AppendSimpleValue(nil, 32); !bytesEqual(got, mustHex(t, "f820")) {
+		t.Fatalf("simple(32) = %x", got)
+	}
+	for v := uint8(24); v < 32; v++ {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("AppendSimpleValue(%d) did not panic", v)
+				}
+			}()
+			cbor.AppendSimpleValue(nil, v)
+		}()
+		if b, err := cbor.SimpleValue(v).MarshalCBOR(nil); !errors.Is(err, cbor.ErrInvalidSimpleValue) || len(b) != 0 {
+			t.Fatalf("SimpleValue(%d).MarshalCBOR = %x, %v", v, b, err)
+		}
+		bb := cbor.GetByteBuffer()
+		w := cbor.NewWriter(bb)
+		if err := w.WriteSimpleValue(v); !errors.Is(err, cbor.ErrInvalidSimpleValue) || len(w.Bytes()) != 0 {
+			t.Fatalf("WriteSimpleValue(%d) = %x, %v", v, w.Bytes(), err)
+		}
+		cbor.PutByteBuffer(bb)
+		if _, err := cbor.ParseDiag(fmt.Sprintf("simple(%d)", v)); err == nil {
+			t.Fatalf("ParseDiag(simple(%d)) accepted a reserved value", v)
+		}
+	}
+}
+
 // TestStrictModeIntegers exercises canonical integer encodings under
 // strict mode. Certain non-minimal integer encodings should be
 // rejected with ErrNonCanonicalLength, while canonical forms must
@@ -214,6 +609,549 @@ func TestReadBytesUint64LengthShort(t *testing.T) {
 	}
 }
 
+// TestAppendInterfaceByteSlices verifies that [][]byte is encoded as an
+// array of byte strings rather than via the reflection fallback.
+func TestAppendInterfaceByteSlices(t *testing.T) {
+	got, err := cbor.AppendInterface(nil, [][]byte{{0x01, 0x02}, {}, nil})
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	want := mustHex(t, "834201024040")
+	if !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface([][]byte) = %x want %x", got, want)
+	}
+}
+
+// TestAppendInterfaceNarrowMapValues verifies the explicit cases for
+// map[string]T with a narrow numeric T, which encode each value at its
+// own width rather than via the reflection fallback.
+func TestAppendInterfaceNarrowMapValues(t *testing.T) {
+	cases := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"float32", map[string]float32{"a": 1.5}, "a16161fa3fc00000"},
+		{"int32", map[string]int32{"a": -70000}, "a161613a0001116f"},
+		{"int16", map[string]int16{"a": -300}, "a1616139012b"},
+		{"uint32", map[string]uint32{"a": 70000}, "a161611a00011170"},
+		{"uint16", map[string]uint16{"a": 300}, "a1616119012c"},
+	}
+	for _, tc := range cases {
+		got, err := cbor.AppendInterface(nil, tc.in)
+		if err != nil {
+			t.Fatalf("%s: AppendInterface error: %v", tc.name, err)
+		}
+		if want := mustHex(t, tc.want); !bytesEqual(got, want) {
+			t.Fatalf("%s: AppendInterface = %x want %x", tc.name, got, want)
+		}
+	}
+}
+
+// TestAppendInterface_ByteSliceAlias verifies that []uint8, being the
+// same type as []byte, is encoded as a byte string rather than an array
+// of integers.
+func TestAppendInterface_ByteSliceAlias(t *testing.T) {
+	got, err := cbor.AppendInterface(nil, []uint8{1, 2, 3})
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	want := mustHex(t, "43010203")
+	if !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface([]uint8) = %x want %x", got, want)
+	}
+}
+
+// TestAppendInterfaceTimePointer verifies that *time.Time is encoded
+// like time.Time, and a nil pointer as null.
+func TestAppendInterfaceTimePointer(t *testing.T) {
+	ts := time.Unix(1700000000, 0).UTC()
+	got, err := cbor.AppendInterface(nil, &ts)
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := cbor.AppendTime(nil, ts); !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(*time.Time) = %x want %x", got, want)
+	}
+	got, err = cbor.AppendInterface(nil, (*time.Time)(nil))
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := []byte{0xf6}; !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(nil *time.Time) = %x want %x", got, want)
+	}
+}
+
+// TestValidateDocumentTrailing verifies that ValidateDocumentWithOptions
+// reports how many bytes hold well-formed items, and that AllowTrailing
+// ignores what follows instead of returning an error.
+func TestValidateDocumentTrailing(t *testing.T) {
+	// 1, "a", then a reserved additional info byte and some padding.
+	b := mustHex(t, "0161611cffff")
+	if err := cbor.ValidateDocument(b); err == nil {
+		t.Fatalf("ValidateDocument accepted trailing bytes")
+	}
+	n, err := cbor.ValidateDocumentWithOptions(b, cbor.ValidateDocumentOptions{})
+	if err == nil || n != 3 {
+		t.Fatalf("ValidateDocumentWithOptions = %d, %v; want 3 and an error", n, err)
+	}
+	n, err = cbor.ValidateDocumentWithOptions(b, cbor.ValidateDocumentOptions{AllowTrailing: true})
+	if err != nil || n != 3 {
+		t.Fatalf("AllowTrailing: ValidateDocumentWithOptions = %d, %v; want 3, nil", n, err)
+	}
+	// A truncated final item is trailing too.
+	n, err = cbor.ValidateDocumentWithOptions(mustHex(t, "0182"), cbor.ValidateDocumentOptions{AllowTrailing: true})
+	if err != nil || n != 1 {
+		t.Fatalf("AllowTrailing truncated: = %d, %v; want 1, nil", n, err)
+	}
+	n, err = cbor.ValidateDocumentWithOptions(mustHex(t, "0102"), cbor.ValidateDocumentOptions{})
+	if err != nil || n != 2 {
+		t.Fatalf("well-formed: = %d, %v; want 2, nil", n, err)
+	}
+}
+
+// TestReadMapKeyBytes verifies that ReadMapKeyBytes returns the raw
+// encoding of text and integer keys, so they can be compared with
+// pre-encoded keys, and rejects other key types.
+func TestReadMapKeyBytes(t *testing.T) {
+	// {"a": 1, 7: 2, -1: 3, 1000: 4}
+	b := mustHex(t, "a4616101070220031903e804")
+	_, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeaderBytes error: %v", err)
+	}
+	wantKeys := [][]byte{
+		cbor.AppendString(nil, "a"),
+		cbor.AppendUint64(nil, 7),
+		cbor.AppendInt64(nil, -1),
+		cbor.AppendUint64(nil, 1000),
+	}
+	for i, want := range wantKeys {
+		var key []byte
+		key, rest, err = cbor.ReadMapKeyBytes(rest)
+		if err != nil {
+			t.Fatalf("key %d: ReadMapKeyBytes error: %v", i, err)
+		}
+		if !bytesEqual(key, want) {
+			t.Fatalf("key %d = %x want %x", i, key, want)
+		}
+		if got := cbor.MapKeyIsString(key); got != (i == 0) {
+			t.Fatalf("key %d: MapKeyIsString = %v", i, got)
+		}
+		if got := cbor.MapKeyIsUint64(key); got != (i == 1 || i == 3) {
+			t.Fatalf("key %d: MapKeyIsUint64 = %v", i, got)
+		}
+		if rest, err = cbor.Skip(rest); err != nil {
+			t.Fatalf("key %d: Skip error: %v", i, err)
+		}
+	}
+	if len(rest) != 0 {
+		t.Fatalf("leftover %x", rest)
+	}
+
+	// Byte string, indefinite text and truncated keys are rejected.
+	for _, h := range []string{"4161", "7f6161ff", "6261", "19"} {
+		in := mustHex(t, h)
+		if _, o, err := cbor.ReadMapKeyBytes(in); err == nil || len(o) != len(in) {
+			t.Fatalf("%s: err = %v, rest = %x; want an error and the input", h, err, o)
+		}
+	}
+}
+
+// TestValidateWellFormedIntArrays verifies arrays of integers, which
+// are validated without recursing per element, including truncated and
+// reserved encodings inside them.
+func TestValidateWellFormedIntArrays(t *testing.T) {
+	ok := []string{
+		"8301 1818 3903e7",                // [1, 24, -1000]
+		"8420 1b0000000100000000 f5 6161", // [-1, 2^32, true, "a"]
+		"80",
+	}
+	for _, h := range ok {
+		b := mustHex(t, strings.ReplaceAll(h, " ", ""))
+		rest, err := cbor.ValidateWellFormedBytes(append(b, 0x00))
+		if err != nil || len(rest) != 1 {
+			t.Fatalf("%s: err %v, rest %d", h, err, len(rest))
+		}
+	}
+	bad := []string{
+		"820119", // uint16 missing its bytes
+		"830102", // array short of elements
+		"82011c", // reserved additional info 28
+	}
+	for _, h := range bad {
+		if _, err := cbor.ValidateWellFormedBytes(mustHex(t, h)); err == nil {
+			t.Fatalf("%s: expected error", h)
+		}
+	}
+}
+
+// TestAddSizeClamps verifies that AddSize saturates at math.MaxInt
+// rather than wrapping to a negative size.
+func TestAddSizeClamps(t *testing.T) {
+	if got := cbor.AddSize(1, 2); got != 3 {
+		t.Fatalf("AddSize(1, 2) = %d", got)
+	}
+	if got := cbor.AddSize(math.MaxInt-1, 5); got != math.MaxInt {
+		t.Fatalf("AddSize near MaxInt = %d, want math.MaxInt", got)
+	}
+}
+
+// TestReadStringBytesStrict verifies that invalid UTF-8 is rejected with
+// the offset of the first bad byte, in both definite and indefinite
+// strings, independent of ValidateUTF8OnDecode.
+func TestReadStringBytesStrict(t *testing.T) {
+	s, rest, err := cbor.ReadStringBytesStrict(cbor.AppendString(nil, "héllo"))
+	if err != nil || s != "héllo" || len(rest) != 0 {
+		t.Fatalf("ReadStringBytesStrict = %q, %v", s, err)
+	}
+
+	cases := []struct {
+		name, hex, offset string
+	}{
+		{"definite", "6461c3a8ff", "offset 3"},
+		{"indefinite", "7f61616262ffff", "offset 2"},
+	}
+	for _, tc := range cases {
+		_, _, err := cbor.ReadStringBytesStrict(mustHex(t, tc.hex))
+		if !errors.Is(err, cbor.ErrInvalidUTF8) {
+			t.Fatalf("%s: err = %v, want ErrInvalidUTF8", tc.name, err)
+		}
+		if !strings.HasSuffix(err.Error(), tc.offset) {
+			t.Fatalf("%s: err = %q, want %s", tc.name, err, tc.offset)
+		}
+	}
+}
+
+// TestAppendInterfaceRawMessage verifies that json.RawMessage is written
+// as a byte string holding the raw JSON.
+func TestAppendInterfaceRawMessage(t *testing.T) {
+	got, err := cbor.AppendInterface(nil, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "427b7d"); !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(json.RawMessage) = %x want %x", got, want)
+	}
+}
+
+// TestAppendInterfaceBigFloat verifies that *big.Float is written as an
+// exact tag 5 bigfloat and comes back from ReadInterface as *big.Float
+// only when DecodeTagsAsBigFloat is set.
+func TestAppendInterfaceBigFloat(t *testing.T) {
+	// 1.5 = 3 × 2^-1, RFC 8949 appendix A.
+	got, err := cbor.AppendInterface(nil, big.NewFloat(1.5))
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "c5822003"); !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(1.5) = %x want %x", got, want)
+	}
+
+	third := new(big.Float).SetPrec(200).Quo(big.NewFloat(-1), big.NewFloat(3))
+	for _, f := range []*big.Float{big.NewFloat(0), big.NewFloat(-1.5), big.NewFloat(1e300), third} {
+		b, err := cbor.AppendInterface(nil, f)
+		if err != nil {
+			t.Fatalf("AppendInterface(%v) error: %v", f, err)
+		}
+		v, rest, err := cbor.ReadInterfaceWithOptions(b, cbor.ReadInterfaceOptions{DecodeTagsAsBigFloat: true})
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("ReadInterface(%v) error: %v, rest %d", f, err, len(rest))
+		}
+		if bf, ok := v.(*big.Float); !ok || bf.Cmp(f) != 0 {
+			t.Fatalf("ReadInterface = %#v, want %v", v, f)
+		}
+	}
+
+	v, _, err := cbor.ReadInterface(got)
+	if err != nil {
+		t.Fatalf("ReadInterface error: %v", err)
+	}
+	if tg, ok := v.(cbor.Tag); !ok || tg.Number != 5 {
+		t.Fatalf("ReadInterface without DecodeTagsAsBigFloat = %#v, want Tag 5", v)
+	}
+
+	got, err = cbor.AppendInterface(nil, new(big.Float).SetInf(true))
+	if want := mustHex(t, "fbfff0000000000000"); err != nil || !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(-Inf) = %x, %v want %x", got, err, want)
+	}
+}
+
+// TestAppendInterfaceRegexp verifies that *regexp.Regexp is written as
+// tag 35 and read back by ReadInterface, while a pattern Go cannot compile
+// is still returned as a Tag.
+func TestAppendInterfaceRegexp(t *testing.T) {
+	got, err := cbor.AppendInterface(nil, regexp.MustCompile("a+"))
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "d82362612b"); !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(*regexp.Regexp) = %x want %x", got, want)
+	}
+	v, rest, err := cbor.ReadInterface(got)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("ReadInterface error: %v, rest %d", err, len(rest))
+	}
+	if re, ok := v.(*regexp.Regexp); !ok || re.String() != "a+" {
+		t.Fatalf("ReadInterface = %#v, want *regexp.Regexp a+", v)
+	}
+
+	got, err = cbor.AppendInterface(nil, (*regexp.Regexp)(nil))
+	if err != nil || !bytesEqual(got, []byte{0xf6}) {
+		t.Fatalf("AppendInterface(nil *regexp.Regexp) = %x, %v", got, err)
+	}
+
+	v, _, err = cbor.ReadInterface(cbor.AppendRegexpString(nil, "(?=a)"))
+	if err != nil {
+		t.Fatalf("ReadInterface error: %v", err)
+	}
+	if tag, ok := v.(cbor.Tag); !ok || tag.Number != 35 || tag.Content != "(?=a)" {
+		t.Fatalf("ReadInterface = %#v, want Tag 35", v)
+	}
+}
+
+// TestAppendInterfaceIntKeyAnyMaps verifies that map[int64]any and
+// map[uint64]any are encoded with integer keys and recursively encoded
+// values.
+func TestAppendInterfaceIntKeyAnyMaps(t *testing.T) {
+	got, err := cbor.AppendInterface(nil, map[int64]any{-1: "a"})
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "a1206161"); !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(map[int64]any) = %x want %x", got, want)
+	}
+	got, err = cbor.AppendInterface(nil, map[uint64]any{1: []any{true}})
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "a10181f5"); !bytesEqual(got, want) {
+		t.Fatalf("AppendInterface(map[uint64]any) = %x want %x", got, want)
+	}
+	if _, err := cbor.AppendInterface(nil, map[uint64]any{1: make(chan int)}); err == nil {
+		t.Fatalf("expected error for unsupported value")
+	}
+}
+
+// TestMapStrBool round-trips map[string]bool through the dedicated
+// helpers and checks that AppendInterface uses the same encoding.
+func TestMapStrBool(t *testing.T) {
+	in := map[string]bool{"a": true}
+	b := cbor.AppendMapStrBool(nil, in)
+	if want := mustHex(t, "a16161f5"); !bytesEqual(b, want) {
+		t.Fatalf("AppendMapStrBool = %x want %x", b, want)
+	}
+	viaIface, err := cbor.AppendInterface(nil, in)
+	if err != nil || !bytesEqual(viaIface, b) {
+		t.Fatalf("AppendInterface(map[string]bool) = %x, %v", viaIface, err)
+	}
+
+	in = map[string]bool{"on": true, "off": false}
+	out := map[string]bool{}
+	rest, err := cbor.ReadMapStrBoolBytes(cbor.AppendMapStrBool(nil, in), out)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("ReadMapStrBoolBytes err: %v rest:%d", err, len(rest))
+	}
+	if len(out) != 2 || !out["on"] || out["off"] {
+		t.Fatalf("ReadMapStrBoolBytes = %v", out)
+	}
+	if _, err := cbor.ReadMapStrBoolBytes(mustHex(t, "a1616101"), out); err == nil {
+		t.Fatalf("expected error for non-bool value")
+	}
+}
+
+// TestReadDurationLenient verifies that the lenient reader accepts both
+// integer and string durations while ReadDurationBytes stays strict.
+func TestReadDurationLenient(t *testing.T) {
+	want := 90 * time.Minute
+	for _, b := range [][]byte{
+		cbor.AppendDuration(nil, want),
+		cbor.AppendString(nil, "1h30m"),
+	} {
+		got, rest, err := cbor.ReadDurationLenientBytes(b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("ReadDurationLenientBytes(%x) err: %v rest:%d", b, err, len(rest))
+		}
+		if got != want {
+			t.Fatalf("ReadDurationLenientBytes(%x) = %v want %v", b, got, want)
+		}
+	}
+	if _, _, err := cbor.ReadDurationBytes(cbor.AppendString(nil, "1h30m")); err == nil {
+		t.Fatalf("ReadDurationBytes accepted a string duration")
+	}
+	if _, _, err := cbor.ReadDurationLenientBytes(cbor.AppendString(nil, "soon")); err == nil {
+		t.Fatalf("expected error for unparsable duration string")
+	}
+}
+
+// TestReadBoolLenient checks that integers 0 and 1 decode as booleans
+// alongside the native encodings, while ReadBoolBytes still rejects them.
+func TestReadBoolLenient(t *testing.T) {
+	cases := []struct {
+		hex  string
+		want bool
+	}{
+		{"f4", false},
+		{"f5", true},
+		{"00", false},
+		{"01", true},
+	}
+	for _, tc := range cases {
+		got, rest, err := cbor.ReadBoolLenientBytes(mustHex(t, tc.hex))
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("ReadBoolLenientBytes(%s) err: %v rest:%d", tc.hex, err, len(rest))
+		}
+		if got != tc.want {
+			t.Fatalf("ReadBoolLenientBytes(%s) = %v want %v", tc.hex, got, tc.want)
+		}
+	}
+	for _, h := range []string{"02", "20", "f6"} {
+		if _, _, err := cbor.ReadBoolLenientBytes(mustHex(t, h)); err == nil {
+			t.Fatalf("ReadBoolLenientBytes(%s) accepted a non-boolean", h)
+		}
+	}
+	if _, _, err := cbor.ReadBoolBytes(mustHex(t, "01")); err == nil {
+		t.Fatalf("ReadBoolBytes accepted an integer")
+	}
+}
+
+// TestAppendSlice verifies the generic slice helper writes the array
+// header and propagates element errors.
+func TestAppendSlice(t *testing.T) {
+	got, err := cbor.AppendSlice(nil, []uint16{1, 500}, func(b []byte, v uint16) ([]byte, error) {
+		return cbor.AppendUint16(b, v), nil
+	})
+	if err != nil {
+		t.Fatalf("AppendSlice error: %v", err)
+	}
+	if want := mustHex(t, "82011901f4"); !bytesEqual(got, want) {
+		t.Fatalf("AppendSlice = %x want %x", got, want)
+	}
+
+	errBad := errors.New("bad element")
+	_, err = cbor.AppendSlice(nil, []string{"ok", "bad"}, func(b []byte, v string) ([]byte, error) {
+		if v == "bad" {
+			return b, errBad
+		}
+		return cbor.AppendString(b, v), nil
+	})
+	if !errors.Is(err, errBad) {
+		t.Fatalf("expected element error, got %v", err)
+	}
+}
+
+// TestWriterRichTypes verifies that the Writer methods for tags, times,
+// durations, bignums, half floats and simple values match their Append*
+// counterparts.
+func TestWriterRichTypes(t *testing.T) {
+	ti := time.Unix(1363896240, 500000000)
+	neg, _ := new(big.Int).SetString("-18446744073709551617", 10)
+
+	var want []byte
+	want = cbor.AppendTag(want, 32)
+	want = cbor.AppendString(want, "http://example.com")
+	want = cbor.AppendTime(want, ti)
+	want = cbor.AppendDuration(want, time.Second)
+	want = cbor.AppendBigInt(want, neg)
+	want = cbor.AppendFloat16(want, 1.5)
+	want = cbor.AppendSimpleValue(want, 16)
+
+	bb := cbor.GetByteBuffer()
+	defer cbor.PutByteBuffer(bb)
+	w := cbor.NewWriter(bb)
+	for _, err := range []error{
+		w.WriteTag(32),
+		w.WriteString("http://example.com"),
+		w.WriteTime(ti),
+		w.WriteDuration(time.Second),
+		w.WriteBigInt(neg),
+		w.WriteFloat16(1.5),
+		w.WriteSimpleValue(16),
+	} {
+		if err != nil {
+			t.Fatalf("Writer error: %v", err)
+		}
+	}
+	if !bytesEqual(w.Bytes(), want) {
+		t.Fatalf("Writer output %x want %x", w.Bytes(), want)
+	}
+}
+
+// TestHeader64 verifies the uint64 header helpers match the uint32 ones
+// in range and use the 8-byte length form beyond it.
+func TestHeader64(t *testing.T) {
+	if got, want := cbor.AppendMapHeader64(nil, 1000), cbor.AppendMapHeader(nil, 1000); !bytesEqual(got, want) {
+		t.Fatalf("AppendMapHeader64(1000) = %x want %x", got, want)
+	}
+	if got, want := cbor.AppendArrayHeader64(nil, math.MaxUint32), cbor.AppendArrayHeader(nil, math.MaxUint32); !bytesEqual(got, want) {
+		t.Fatalf("AppendArrayHeader64(MaxUint32) = %x want %x", got, want)
+	}
+	if got, want := cbor.AppendMapHeader64(nil, 1<<32), mustHex(t, "bb0000000100000000"); !bytesEqual(got, want) {
+		t.Fatalf("AppendMapHeader64(1<<32) = %x want %x", got, want)
+	}
+	if got, want := cbor.AppendArrayHeader64(nil, math.MaxUint64), mustHex(t, "9bffffffffffffffff"); !bytesEqual(got, want) {
+		t.Fatalf("AppendArrayHeader64(MaxUint64) = %x want %x", got, want)
+	}
+}
+
+// TestReadAll verifies the pooled ReadAll returns an independent copy of
+// the input and surfaces read errors with the partial data.
+func TestReadAll(t *testing.T) {
+	src := bytes.Repeat([]byte("cbor"), 20000) // spans several 32KB chunks
+	got, err := cbor.ReadAll(iotest.HalfReader(bytes.NewReader(src)))
+	if err != nil {
+		t.Fatalf("ReadAll error: %v", err)
+	}
+	if !bytesEqual(got, src) {
+		t.Fatalf("ReadAll returned %d bytes, want %d", len(got), len(src))
+	}
+
+	// The result must not alias a pooled buffer that is later reused.
+	bb := cbor.GetByteBuffer()
+	bb.AppendString("overwrite")
+	cbor.PutByteBuffer(bb)
+	if !bytesEqual(got, src) {
+		t.Fatalf("ReadAll result changed after pool reuse")
+	}
+
+	errRead := errors.New("read failed")
+	got, err = cbor.ReadAll(io.MultiReader(bytes.NewReader([]byte{1, 2}), iotest.ErrReader(errRead)))
+	if !errors.Is(err, errRead) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	if !bytesEqual(got, []byte{1, 2}) {
+		t.Fatalf("partial data = %x", got)
+	}
+}
+
+// TestByteBufferTakeBytes verifies TakeBytes hands out an independent copy
+// and leaves the buffer empty for reuse.
+func TestByteBufferTakeBytes(t *testing.T) {
+	bb := cbor.GetByteBuffer()
+	bb.AppendString("hello")
+	want := []byte{0x65, 'h', 'e', 'l', 'l', 'o'}
+	got := bb.TakeBytes()
+	if !bytesEqual(got, want) {
+		t.Fatalf("TakeBytes = %x want %x", got, want)
+	}
+	if bb.Len() != 0 {
+		t.Fatalf("Len after TakeBytes = %d want 0", bb.Len())
+	}
+
+	// Writing to the buffer again, even after pooling, must not touch got.
+	bb.AppendString("world")
+	cbor.PutByteBuffer(bb)
+	if !bytesEqual(got, want) {
+		t.Fatalf("TakeBytes result changed after buffer reuse: %x", got)
+	}
+
+	empty := cbor.GetByteBuffer()
+	defer cbor.PutByteBuffer(empty)
+	if b := empty.TakeBytes(); b == nil || len(b) != 0 {
+		t.Fatalf("TakeBytes on empty buffer = %#v want empty non-nil slice", b)
+	}
+}
+
 // bytesEqual is a small helper to compare two byte slices without allocating.
 func bytesEqual(a, b []byte) bool {
 	if len(a) != len(b) {
@@ -226,3 +1164,96 @@ func bytesEqual(a, b []byte) bool {
 	}
 	return true
 }
+
+// TestReaderReadFloat16 checks that Reader.ReadFloat16 advances the
+// buffer and, in strict mode, rejects half floats with a different
+// canonical encoding.
+func TestReaderReadFloat16(t *testing.T) {
+	r := cbor.NewReaderBytes(mustHex(t, "f93e00f97c00"))
+	r.SetStrictDecode(true)
+	for _, want := range []float32{1.5, float32(math.Inf(1))} {
+		v, err := r.ReadFloat16()
+		if err != nil {
+			t.Fatalf("ReadFloat16 error: %v", err)
+		}
+		if v != want {
+			t.Fatalf("got %v want %v", v, want)
+		}
+	}
+	if len(r.Remaining()) != 0 {
+		t.Fatalf("leftover: %x", r.Remaining())
+	}
+
+	// -0 and a NaN with a payload are half floats, but not canonical ones.
+	for _, h := range []string{"f98000", "f97e01"} {
+		r = cbor.NewReaderBytes(mustHex(t, h))
+		r.SetStrictDecode(true)
+		if _, err := r.ReadFloat16(); !errors.Is(err, cbor.ErrNonCanonicalFloat) {
+			t.Fatalf("%s: expected ErrNonCanonicalFloat, got %v", h, err)
+		}
+		r = cbor.NewReaderBytes(mustHex(t, h))
+		if _, err := r.ReadFloat16(); err != nil {
+			t.Fatalf("%s: lenient ReadFloat16 error: %v", h, err)
+		}
+	}
+
+	if _, err := cbor.NewReaderBytes(mustHex(t, "fa3fc00000")).ReadFloat16(); err == nil {
+		t.Fatalf("expected error for a single-precision float")
+	}
+}
+
+// TestMaxDepthError checks that nesting past the recursion limit reports
+// the depth reached and still matches ErrMaxDepthExceeded.
+func TestMaxDepthError(t *testing.T) {
+	// 100002 nested single-element arrays around a 0.
+	const n = 100002
+	msg := make([]byte, n+1)
+	for i := 0; i < n; i++ {
+		msg[i] = 0x81
+	}
+	_, err := cbor.Skip(msg)
+	if !errors.Is(err, cbor.ErrMaxDepthExceeded) {
+		t.Fatalf("expected ErrMaxDepthExceeded, got %v", err)
+	}
+	var de cbor.MaxDepthError
+	if !errors.As(err, &de) || de.Depth != 100001 {
+		t.Fatalf("expected MaxDepthError at depth 100001, got %#v", err)
+	}
+	if want := "cbor: max depth exceeded at depth 100001"; err.Error() != want {
+		t.Fatalf("Error() = %q want %q", err.Error(), want)
+	}
+}
+
+// TestStringInterning checks that ReadStringBytes returns the cached
+// string for repeated input once interning is enabled, and that the
+// cache stops growing at its configured size.
+func TestStringInterning(t *testing.T) {
+	read := func(s string) string {
+		t.Helper()
+		got, _, err := cbor.ReadStringBytes(cbor.AppendString(nil, s))
+		if err != nil || got != s {
+			t.Fatalf("ReadStringBytes(%q) = %q, %v", s, got, err)
+		}
+		return got
+	}
+	same := func(a, b string) bool { return unsafe.StringData(a) == unsafe.StringData(b) }
+
+	if same(read("stream"), read("stream")) {
+		t.Fatalf("strings shared before interning was enabled")
+	}
+
+	cbor.EnableStringInterning(1)
+	defer cbor.EnableStringInterning(0)
+	if !same(read("stream"), read("stream")) {
+		t.Fatalf("repeated string was not interned")
+	}
+	// The cache is full, so other strings are not retained.
+	if same(read("consumer"), read("consumer")) {
+		t.Fatalf("cache grew beyond its maximum size")
+	}
+
+	cbor.EnableStringInterning(0)
+	if same(read("stream"), read("stream")) {
+		t.Fatalf("strings shared after interning was disabled")
+	}
+}
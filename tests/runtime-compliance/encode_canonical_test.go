@@ -2,6 +2,7 @@ package tests
 
 import (
 	"encoding/hex"
+	"math"
 	"testing"
 
 	cbor "github.com/synadia-labs/cbor.go/runtime"
@@ -96,3 +97,32 @@ func TestCanonicalFloatEncoding(t *testing.T) {
 		t.Fatalf("1/3 not encoded as float64, got %x", b)
 	}
 }
+
+func TestCanonicalNaNEncoding(t *testing.T) {
+	want := "f97e00"
+	if got := hex.EncodeToString(cbor.AppendNaN(nil)); got != want {
+		t.Fatalf("AppendNaN = %s want %s", got, want)
+	}
+	if got := hex.EncodeToString([]byte(cbor.NaNFloat16)); got != want {
+		t.Fatalf("NaNFloat16 = %s want %s", got, want)
+	}
+	// Any NaN payload canonicalizes to the same bytes.
+	if got := hex.EncodeToString(cbor.AppendFloatCanonical(nil, math.Float64frombits(0x7ff8000000000123))); got != want {
+		t.Fatalf("AppendFloatCanonical(NaN) = %s want %s", got, want)
+	}
+	// AppendNaN appends without clobbering existing content.
+	if got := hex.EncodeToString(cbor.AppendNaN([]byte{0x82})); got != "82"+want {
+		t.Fatalf("AppendNaN with prefix = %s", got)
+	}
+}
+
+func TestNegativeZeroCanonical(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	if got := hex.EncodeToString(cbor.AppendFloatCanonical(nil, negZero)); got != "f90000" {
+		t.Fatalf("AppendFloatCanonical(-0) = %s want f90000", got)
+	}
+	// The float32 -0 widens to the same float64 and must normalize too.
+	if got := hex.EncodeToString(cbor.AppendFloatCanonical(nil, float64(float32(negZero)))); got != "f90000" {
+		t.Fatalf("AppendFloatCanonical(float32 -0) = %s want f90000", got)
+	}
+}
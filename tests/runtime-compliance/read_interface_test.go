@@ -0,0 +1,429 @@
+package tests
+
+import (
+	"errors"
+	"math/big"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+	"unsafe"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func TestReadInterfaceScalarsAndContainers(t *testing.T) {
+	cases := []struct {
+		name string
+		hex  string
+		want any
+	}{
+		{"uint", "1903e8", uint64(1000)},
+		{"negint", "3903e7", int64(-1000)},
+		{"bytes", "43010203", []byte{1, 2, 3}},
+		{"text", "6161", "a"},
+		{"array", "83010203", []any{uint64(1), uint64(2), uint64(3)}},
+		{"array_indefinite", "9f0102ff", []any{uint64(1), uint64(2)}},
+		{"map_str", "a2616101616202", map[string]any{"a": uint64(1), "b": uint64(2)}},
+		{"map_indefinite", "bf6161f5ff", map[string]any{"a": true}},
+		{"map_mixed", "a2616101020a", map[any]any{"a": uint64(1), uint64(2): uint64(10)}},
+		{"null", "f6", nil},
+		{"undefined", "f7", nil},
+		{"float16", "f93e00", float32(1.5)},
+		{"float64", "fb3ff199999999999a", 1.1},
+		{"simple", "f0", cbor.SimpleValue(16)},
+		{"tag_uri", "d82076687474703a2f2f7777772e6578616d706c652e636f6d", "http://www.example.com"},
+		{"tag_unknown", "d82163616263", cbor.Tag{Number: 33, Content: "abc"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, rest, err := cbor.ReadInterface(mustHex(t, tc.hex))
+			if err != nil {
+				t.Fatalf("ReadInterface error: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("leftover: %d", len(rest))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %#v want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadInterfaceBuiltinTags(t *testing.T) {
+	ti := time.Unix(1363896240, 0)
+	got, _, err := cbor.ReadInterface(cbor.AppendTime(nil, ti))
+	if err != nil {
+		t.Fatalf("ReadInterface(tag 1) error: %v", err)
+	}
+	if tv, ok := got.(time.Time); !ok || !tv.Equal(ti) {
+		t.Fatalf("tag 1: got %#v want %v", got, ti)
+	}
+
+	// 0("2013-03-21T20:04:00Z"), RFC 8949 appendix A.
+	got, _, err = cbor.ReadInterface(mustHex(t, "c074323031332d30332d32315432303a30343a30305a"))
+	if err != nil {
+		t.Fatalf("ReadInterface(tag 0) error: %v", err)
+	}
+	if tv, ok := got.(time.Time); !ok || !tv.Equal(ti) {
+		t.Fatalf("tag 0: got %#v want %v", got, ti)
+	}
+	// Text that is not RFC 3339 stays a Tag.
+	got, _, err = cbor.ReadInterface(mustHex(t, "c063796573"))
+	if err != nil {
+		t.Fatalf("ReadInterface(tag 0, bad text) error: %v", err)
+	}
+	if tg, ok := got.(cbor.Tag); !ok || tg.Number != 0 || tg.Content != "yes" {
+		t.Fatalf("tag 0, bad text: got %#v", got)
+	}
+
+	// -18446744073709551617 as a negative bignum.
+	got, _, err = cbor.ReadInterface(mustHex(t, "c349010000000000000000"))
+	if err != nil {
+		t.Fatalf("ReadInterface(tag 3) error: %v", err)
+	}
+	want, _ := new(big.Int).SetString("-18446744073709551617", 10)
+	if bi, ok := got.(*big.Int); !ok || bi.Cmp(want) != 0 {
+		t.Fatalf("tag 3: got %#v want %v", got, want)
+	}
+
+	// -2^64 does not fit in int64.
+	got, _, err = cbor.ReadInterface(mustHex(t, "3bffffffffffffffff"))
+	if err != nil {
+		t.Fatalf("ReadInterface(-2^64) error: %v", err)
+	}
+	want, _ = new(big.Int).SetString("-18446744073709551616", 10)
+	if bi, ok := got.(*big.Int); !ok || bi.Cmp(want) != 0 {
+		t.Fatalf("-2^64: got %#v want %v", got, want)
+	}
+}
+
+func TestReadInterfaceTagRegistry(t *testing.T) {
+	type point struct{ X, Y uint64 }
+	var reg cbor.TagRegistry
+	reg.Register(40000, func(inner any) (any, error) {
+		arr, ok := inner.([]any)
+		if !ok || len(arr) != 2 {
+			return nil, errors.New("bad point")
+		}
+		return point{arr[0].(uint64), arr[1].(uint64)}, nil
+	})
+	// Registered handlers take precedence over built-ins.
+	reg.Register(1, func(inner any) (any, error) { return inner, nil })
+
+	// [40000([1, 2]), 1(5)]
+	msg := mustHex(t, "82d99c40820102c105")
+	got, rest, err := cbor.ReadInterfaceWithRegistry(msg, &reg)
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithRegistry error: %v", err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("leftover: %d", len(rest))
+	}
+	want := []any{point{1, 2}, uint64(5)}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+
+	// Handler errors are propagated.
+	if _, _, err := cbor.ReadInterfaceWithRegistry(mustHex(t, "d99c4001"), &reg); err == nil {
+		t.Fatalf("expected handler error")
+	}
+
+	// Without the registry the tag falls through to the generic Tag value.
+	got, _, err = cbor.ReadInterfaceWithRegistry(mustHex(t, "d99c4001"), nil)
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithRegistry(nil) error: %v", err)
+	}
+	if !reflect.DeepEqual(got, cbor.Tag{Number: 40000, Content: uint64(1)}) {
+		t.Fatalf("unexpected fallback value %#v", got)
+	}
+}
+
+func TestReadInterfaceDefaultTagRegistry(t *testing.T) {
+	const tag = 40001
+	cbor.DefaultTagRegistry.Register(tag, func(inner any) (any, error) {
+		return "seen:" + inner.(string), nil
+	})
+	got, _, err := cbor.ReadInterface(mustHex(t, "d99c416178"))
+	if err != nil {
+		t.Fatalf("ReadInterface error: %v", err)
+	}
+	if got != "seen:x" {
+		t.Fatalf("got %#v", got)
+	}
+}
+
+func TestReadInterfaceRoundTrip(t *testing.T) {
+	msg := mustHex(t, "a3616183010203616243010203616dd9a0006178")
+	v, _, err := cbor.ReadInterface(msg)
+	if err != nil {
+		t.Fatalf("ReadInterface error: %v", err)
+	}
+	out, err := cbor.AppendMapDeterministicStrInterface(nil, v.(map[string]any))
+	if err != nil {
+		t.Fatalf("AppendMapDeterministic error: %v", err)
+	}
+	if !bytesEqual(out, msg) {
+		t.Fatalf("round trip mismatch: got %x want %x", out, msg)
+	}
+}
+
+func TestReadInterfacePreserveNumbers(t *testing.T) {
+	var u, i, f32, f64 cbor.Number
+	u.AsUint(1000)
+	i.AsInt(-1000)
+	f32.AsFloat32(1.5)
+	f64.AsFloat64(1.1)
+
+	// [1000, -1000, 1.5 (half), 1.1 (double)]
+	msg := mustHex(t, "841903e83903e7f93e00fb3ff199999999999a")
+	got, _, err := cbor.ReadInterfaceWithOptions(msg, cbor.ReadInterfaceOptions{PreserveNumbers: true})
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithOptions error: %v", err)
+	}
+	want := []any{u, i, f32, f64}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+
+	// Number values and pointers re-encode through AppendInterface.
+	out, err := cbor.AppendInterface(nil, got)
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "841903e83903e7fa3fc00000fb3ff199999999999a"); !bytesEqual(out, want) {
+		t.Fatalf("AppendInterface = %x want %x", out, want)
+	}
+	if out, err := cbor.AppendInterface(nil, &u); err != nil || !bytesEqual(out, mustHex(t, "1903e8")) {
+		t.Fatalf("AppendInterface(*Number) = %x, %v", out, err)
+	}
+
+	// Without the option the usual Go types are returned.
+	got, _, err = cbor.ReadInterface(msg)
+	if err != nil {
+		t.Fatalf("ReadInterface error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []any{uint64(1000), int64(-1000), float32(1.5), 1.1}) {
+		t.Fatalf("got %#v", got)
+	}
+}
+
+func TestReadInterfaceURI(t *testing.T) {
+	u, err := url.Parse("https://example.com/a?b=c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cbor.AppendURI(nil, u.String())
+	for _, v := range []any{u, *u} {
+		b, err := cbor.AppendInterface(nil, v)
+		if err != nil {
+			t.Fatalf("AppendInterface(%T) error: %v", v, err)
+		}
+		if !bytesEqual(b, want) {
+			t.Fatalf("AppendInterface(%T) = %x want %x", v, b, want)
+		}
+	}
+	if b, err := cbor.AppendInterface(nil, (*url.URL)(nil)); err != nil || !bytesEqual(b, []byte{0xf6}) {
+		t.Fatalf("AppendInterface(nil *url.URL) = %x, %v", b, err)
+	}
+
+	got, _, err := cbor.ReadInterface(want)
+	if err != nil {
+		t.Fatalf("ReadInterface error: %v", err)
+	}
+	if got != u.String() {
+		t.Fatalf("got %#v want %q", got, u.String())
+	}
+
+	got, _, err = cbor.ReadInterfaceWithOptions(want, cbor.ReadInterfaceOptions{DecodeURIAsURL: true})
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithOptions error: %v", err)
+	}
+	if gu, ok := got.(*url.URL); !ok || *gu != *u {
+		t.Fatalf("got %#v want %v", got, u)
+	}
+
+	if _, _, err := cbor.ReadInterfaceWithOptions(cbor.AppendURI(nil, "%zz"), cbor.ReadInterfaceOptions{DecodeURIAsURL: true}); err == nil {
+		t.Fatalf("expected error for an unparsable URI")
+	}
+}
+
+func TestReadInterfaceZeroCopyKeys(t *testing.T) {
+	opts := cbor.ReadInterfaceOptions{ZeroCopyKeys: true}
+	// {"outer": {"k": 1}}
+	msg := mustHex(t, "a1656f75746572a1616b01")
+	got, _, err := cbor.ReadInterfaceWithOptions(msg, opts)
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithOptions error: %v", err)
+	}
+	want := map[string]any{"outer": map[string]any{"k": uint64(1)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+	inner := got.(map[string]any)["outer"].(map[string]any)
+	for k := range inner {
+		if unsafe.StringData(k) != &msg[9] {
+			t.Fatalf("key %q does not alias the input", k)
+		}
+	}
+
+	// Integer and indefinite-length keys take the usual path:
+	// {2: "v", (_ "a", "b"): 3}
+	msg = mustHex(t, "a20261767f61616162ff03")
+	got, _, err = cbor.ReadInterfaceWithOptions(msg, opts)
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithOptions error: %v", err)
+	}
+	if want := map[any]any{uint64(2): "v", "ab": uint64(3)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+}
+
+func TestReadInterfaceRejectNull(t *testing.T) {
+	opts := cbor.ReadInterfaceOptions{RejectNull: true}
+	for _, h := range []string{
+		"f6",       // null
+		"f7",       // undefined
+		"a1616bf6", // {"k": null}
+		"8201f6",   // [1, null]
+		"d9d9f7f6", // 55799(null)
+		"a1f6616b", // {null: "k"}
+	} {
+		msg := mustHex(t, h)
+		if _, o, err := cbor.ReadInterfaceWithOptions(msg, opts); !errors.Is(err, cbor.ErrUnexpectedNull) || len(o) != len(msg) {
+			t.Fatalf("%s: err = %v, rest = %x; want ErrUnexpectedNull and the input", h, err, o)
+		}
+	}
+
+	// Without the option null decodes as nil.
+	got, _, err := cbor.ReadInterfaceWithOptions(mustHex(t, "8201f6"), cbor.ReadInterfaceOptions{})
+	if err != nil {
+		t.Fatalf("ReadInterfaceWithOptions error: %v", err)
+	}
+	if want := []any{uint64(1), nil}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+}
+
+func TestReadInterfaceComplexArrays(t *testing.T) {
+	c := complex(1.5, -2)
+	b, err := cbor.AppendInterface(nil, c)
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "82fb3ff8000000000000fbc000000000000000"); !bytesEqual(b, want) {
+		t.Fatalf("AppendInterface(complex128) = %x want %x", b, want)
+	}
+	b64, err := cbor.AppendInterface(nil, complex64(c))
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "82fa3fc00000fac0000000"); !bytesEqual(b64, want) {
+		t.Fatalf("AppendInterface(complex64) = %x want %x", b64, want)
+	}
+
+	opts := cbor.ReadInterfaceOptions{DecodeComplexArrays: true}
+	// The float16 form [1.5, -2.0] decodes too.
+	for _, in := range [][]byte{b, b64, mustHex(t, "82f93e00f9c000")} {
+		got, rest, err := cbor.ReadInterfaceWithOptions(in, opts)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("%x: ReadInterfaceWithOptions = %v, rest %x, err %v", in, got, rest, err)
+		}
+		if got != c {
+			t.Fatalf("%x: got %#v want %v", in, got, c)
+		}
+	}
+
+	// Arrays that are not a pair of floats, and pairs read without the
+	// option, stay []any.
+	for _, tc := range []struct {
+		in   []byte
+		opts cbor.ReadInterfaceOptions
+		want []any
+	}{
+		{mustHex(t, "820102"), opts, []any{uint64(1), uint64(2)}},
+		{mustHex(t, "82f93e0001"), opts, []any{float32(1.5), uint64(1)}},
+		{mustHex(t, "83f93e00f93e00f93e00"), opts, []any{float32(1.5), float32(1.5), float32(1.5)}},
+		{b64, cbor.ReadInterfaceOptions{}, []any{float32(1.5), float32(-2)}},
+	} {
+		got, _, err := cbor.ReadInterfaceWithOptions(tc.in, tc.opts)
+		if err != nil {
+			t.Fatalf("%x: ReadInterfaceWithOptions error: %v", tc.in, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%x: got %#v want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+type pathError struct{ path string }
+
+func (e *pathError) Error() string { return "bad path " + e.path }
+
+func TestReadInterfaceErrorTag(t *testing.T) {
+	b, err := cbor.AppendInterface(nil, errors.New("boom"))
+	if err != nil {
+		t.Fatalf("AppendInterface error: %v", err)
+	}
+	if want := mustHex(t, "64626f6f6d"); !bytesEqual(b, want) {
+		t.Fatalf("AppendInterface(error) = %x want %x", b, want)
+	}
+	for _, e := range []error{nil, (*pathError)(nil)} {
+		b, err := cbor.AppendInterface(nil, e)
+		if err != nil {
+			t.Fatalf("AppendInterface(%#v) error: %v", e, err)
+		}
+		if want := []byte{0xf6}; !bytesEqual(b, want) {
+			t.Fatalf("AppendInterface(%#v) = %x want %x", e, b, want)
+		}
+	}
+
+	// 0xd9ffff tags the message with 65535.
+	in := mustHex(t, "d9ffff64626f6f6d")
+	opts := cbor.ReadInterfaceOptions{ErrorTag: 65535}
+	got, rest, err := cbor.ReadInterfaceWithOptions(in, opts)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("ReadInterfaceWithOptions = %v, rest %x, err %v", got, rest, err)
+	}
+	var ce cbor.CBORError
+	if e, ok := got.(error); !ok || !errors.As(e, &ce) || ce.Error() != "boom" {
+		t.Fatalf("got %#v want CBORError(\"boom\")", got)
+	}
+
+	// Without the option, or with non-text content, the item stays a Tag.
+	for _, tc := range []struct {
+		in   []byte
+		opts cbor.ReadInterfaceOptions
+		want any
+	}{
+		{in, cbor.ReadInterfaceOptions{}, cbor.Tag{Number: 65535, Content: "boom"}},
+		{mustHex(t, "d9ffff01"), opts, cbor.Tag{Number: 65535, Content: uint64(1)}},
+	} {
+		got, _, err := cbor.ReadInterfaceWithOptions(tc.in, tc.opts)
+		if err != nil {
+			t.Fatalf("%x: ReadInterfaceWithOptions error: %v", tc.in, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%x: got %#v want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestReadInterfaceHugeLengths checks that length headers beyond the
+// input are reported as short input, including those that do not fit
+// an int on 32-bit platforms.
+func TestReadInterfaceHugeLengths(t *testing.T) {
+	cases := []struct{ name, hex string }{
+		{"array", "9a7fffffff00"},
+		{"array_uint32", "9affffffff00"},
+		{"map", "ba7fffffff6161"},
+		{"map_uint32", "baffffffff6161"},
+	}
+	for _, tc := range cases {
+		if _, _, err := cbor.ReadInterface(mustHex(t, tc.hex)); !errors.Is(err, cbor.ErrShortBytes) {
+			t.Fatalf("%s: err = %v, want ErrShortBytes", tc.name, err)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package tests
 
 import (
 	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"net"
 	"regexp"
 	"testing"
 	"time"
@@ -148,6 +152,42 @@ func TestUUIDEmbeddedSelfDescribe(t *testing.T) {
 	}
 }
 
+func TestTag260NetworkAddresses(t *testing.T) {
+	ip := net.ParseIP("2001:db8::1")
+	b := cbor.AppendIP(nil, ip)
+	got, rest, err := cbor.ReadIPBytes(b)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("ip err: %v rest:%d", err, len(rest))
+	}
+	if !got.Equal(ip) {
+		t.Fatalf("ip mismatch: %v != %v", got, ip)
+	}
+	// IPv4 in 16-byte form is written as 4 bytes.
+	if b = cbor.AppendIP(nil, net.IPv4(10, 0, 0, 1)); !bytesEqual(b, mustHex(t, "d90104440a000001")) {
+		t.Fatalf("ipv4 encoding: %x", b)
+	}
+
+	mac, _ := net.ParseMAC("02:00:5e:10:00:00:00:01")
+	b = cbor.AppendHardwareAddr(nil, mac)
+	gotMAC, rest, err := cbor.ReadHardwareAddrBytes(b)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("mac err: %v rest:%d", err, len(rest))
+	}
+	if !bytes.Equal(gotMAC, mac) {
+		t.Fatalf("mac mismatch: %v != %v", gotMAC, mac)
+	}
+	// A 4-byte payload is a valid IP but not a MAC address.
+	if _, _, err := cbor.ReadHardwareAddrBytes(cbor.AppendIP(nil, net.IPv4(10, 0, 0, 1))); err == nil {
+		t.Fatalf("expected error for 4-byte hardware address")
+	}
+	// Wrong tag.
+	b = cbor.AppendTag(nil, 261)
+	b = cbor.AppendBytes(b, []byte{10, 0, 0, 1})
+	if _, _, err := cbor.ReadIPBytes(b); err == nil {
+		t.Fatalf("expected error for tag 261")
+	}
+}
+
 func TestTagNegativeCases(t *testing.T) {
 	// Wrong tag for UUID should fail.
 	// Encode tag(32) with a 16-byte payload and attempt to read as UUID.
@@ -190,3 +230,103 @@ func TestTagNegativeCases(t *testing.T) {
 		t.Fatalf("expected error for malformed bigfloat, got nil")
 	}
 }
+
+func TestDecimalFromFloat(t *testing.T) {
+	cases := []struct {
+		in        string
+		precision int
+		exp       int64
+		mant      int64
+	}{
+		{"273.15", 2, -2, 27315},
+		{"-1.125", 2, -2, -113}, // halves round away from zero
+		{"2.5", 0, 0, 3},
+		{"-2.5", 0, 0, -3},
+		{"1234", -2, 2, 12},
+		{"0", 3, -3, 0},
+	}
+	for _, tc := range cases {
+		f, _, err := big.ParseFloat(tc.in, 10, 200, big.ToNearestEven)
+		if err != nil {
+			t.Fatalf("ParseFloat(%s): %v", tc.in, err)
+		}
+		b := cbor.AppendDecimalFromFloat(nil, f, tc.precision)
+		exp, mant, rest, err := cbor.ReadDecimalFractionBytes(b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("%s: read error %v, rest %d", tc.in, err, len(rest))
+		}
+		if exp != tc.exp || mant.Int64() != tc.mant {
+			t.Fatalf("%s at %d: got [%d, %s] want [%d, %d]", tc.in, tc.precision, exp, mant, tc.exp, tc.mant)
+		}
+	}
+
+	if b := cbor.AppendDecimalFromFloat(nil, nil, 2); !bytes.Equal(b, []byte{0xf6}) {
+		t.Fatalf("nil: got %x", b)
+	}
+	inf := cbor.AppendDecimalFromFloat(nil, new(big.Float).SetInf(true), 2)
+	if f, _, err := cbor.ReadFloat64Bytes(inf); err != nil || !math.IsInf(f, -1) {
+		t.Fatalf("-Inf: got %x (%v)", inf, err)
+	}
+}
+
+func TestTypedArrays(t *testing.T) {
+	// uint64 big-endian: tag 67 around 16 bytes.
+	b := cbor.AppendTypedArrayUint64(nil, []uint64{1, 1 << 40})
+	want := []byte{0xd8, 0x43, 0x50, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 0, 0, 0, 0, 0}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("uint64: got %x want %x", b, want)
+	}
+	u64, rest, err := cbor.ReadTypedArrayUint64Bytes(b)
+	if err != nil || len(rest) != 0 || len(u64) != 2 || u64[0] != 1 || u64[1] != 1<<40 {
+		t.Fatalf("uint64 read: %v %v %d", u64, err, len(rest))
+	}
+
+	// uint16 little-endian (tag 69) and the plain array form.
+	u16, _, err := cbor.ReadTypedArrayUint16Bytes([]byte{0xd8, 0x45, 0x44, 0x01, 0x00, 0x00, 0x01})
+	if err != nil || len(u16) != 2 || u16[0] != 1 || u16[1] != 256 {
+		t.Fatalf("uint16 LE read: %v %v", u16, err)
+	}
+	u32, _, err := cbor.ReadTypedArrayUint32Bytes([]byte{0x82, 0x01, 0x19, 0x01, 0x00})
+	if err != nil || len(u32) != 2 || u32[0] != 1 || u32[1] != 256 {
+		t.Fatalf("uint32 array read: %v %v", u32, err)
+	}
+	if b := cbor.AppendTypedArrayUint32(nil, nil); !bytes.Equal(b, []byte{0xd8, 0x42, 0x40}) {
+		t.Fatalf("empty uint32: got %x", b)
+	}
+
+	// Wrong tag, truncated element, out-of-range array element.
+	_, _, err = cbor.ReadTypedArrayUint64Bytes([]byte{0xd8, 0x42, 0x40})
+	var tagErr cbor.InvalidTagError
+	if !errors.As(err, &tagErr) || tagErr.Got != 66 || err.Error() != "cbor: expected tag 67 or 71 but got 66" {
+		t.Fatalf("tag 66 read as uint64: %v", err)
+	}
+	if _, _, err := cbor.ReadTypedArrayUint32Bytes([]byte{0xd8, 0x42, 0x43, 0, 0, 1}); err == nil {
+		t.Fatalf("expected error for partial element")
+	}
+	if _, _, err := cbor.ReadTypedArrayUint16Bytes([]byte{0x81, 0x1a, 0x00, 0x01, 0x00, 0x00}); err == nil {
+		t.Fatalf("expected overflow error")
+	}
+}
+
+func TestReadTagsBytes(t *testing.T) {
+	b := cbor.AppendSelfDescribeCBOR(nil)
+	b = cbor.AppendTime(b, time.Unix(1700000000, 0))
+	tags, rest, err := cbor.ReadTagsBytes(b)
+	if err != nil || len(tags) != 2 || tags[0] != 55799 || tags[1] != 1 {
+		t.Fatalf("tags = %v, err %v", tags, err)
+	}
+	if v, rest, err := cbor.ReadInt64Bytes(rest); err != nil || v != 1700000000 || len(rest) != 0 {
+		t.Fatalf("tagged item = %d, err %v, rest %d", v, err, len(rest))
+	}
+
+	untagged := cbor.AppendInt64(nil, 7)
+	tags, rest, err = cbor.ReadTagsBytes(untagged)
+	if err != nil || len(tags) != 0 || !bytes.Equal(rest, untagged) {
+		t.Fatalf("untagged: tags %v, err %v", tags, err)
+	}
+
+	// A tag must be followed by the item it applies to.
+	if _, _, err := cbor.ReadTagsBytes([]byte{0xd9, 0xd9, 0xf7, 0xc1}); err == nil {
+		t.Fatalf("expected error for tags without an item")
+	}
+}
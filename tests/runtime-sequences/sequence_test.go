@@ -56,3 +56,28 @@ func TestCBORSequenceBytesHelpers(t *testing.T) {
 		t.Fatalf("ForEachSequenceBytes expected 2 items, got %d", i)
 	}
 }
+
+func TestForEachSequenceBytesUntil(t *testing.T) {
+	// The trailing 0x1c is malformed; stopping before it must not fail.
+	seq := cbor.AppendSequence(nil, cbor.AppendInt64(nil, 1), cbor.AppendInt64(nil, 2), []byte{0x1c})
+
+	var seen []int64
+	err := cbor.ForEachSequenceBytesUntil(seq, func(item []byte) (bool, error) {
+		v, _, err := cbor.ReadInt64Bytes(item)
+		if err != nil {
+			return false, err
+		}
+		seen = append(seen, v)
+		return v == 2, nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachSequenceBytesUntil error: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 2 {
+		t.Fatalf("seen = %v, want [1 2]", seen)
+	}
+
+	if err := cbor.ForEachSequenceBytesUntil(seq, func([]byte) (bool, error) { return false, nil }); err == nil {
+		t.Fatalf("expected error for malformed trailing item")
+	}
+}
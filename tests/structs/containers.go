@@ -9,3 +9,14 @@ type Containers struct {
 	Map    map[string]Scalars  `cbor:"map"`
 	PtrMap map[string]*Scalars `cbor:"ptr_map"`
 }
+
+// IntKeys exercises maps keyed by narrow integer types, whose decoded
+// keys are bounds-checked against the Go key type.
+type IntKeys struct {
+	U32 map[uint32]string  `cbor:"u32"`
+	U16 map[uint16]int     `cbor:"u16"`
+	U8  map[uint8]bool     `cbor:"u8"`
+	I32 map[int32]*Scalars `cbor:"i32"`
+	I16 map[int16]float64  `cbor:"i16"`
+	I8  map[int8]uint64    `cbor:"i8"`
+}
@@ -1,11 +1,16 @@
 // Code generated by cborgen DO NOT EDIT.
 
+//go:generate go run github.com/synadia-labs/cbor.go/cborgen -i .
+
 package structs
 
 import cbor "github.com/synadia-labs/cbor.go/runtime"
 
 func (x Containers) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("items") + cbor.ArrayHeaderSize + len(x.Items)*0 + cbor.StringPrefixSize + len("map") + cbor.MapHeaderSize + len(x.Map)*(cbor.StringPrefixSize+0)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("items")+cbor.SliceMsgsize(x.Items))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ptrs")+cbor.PtrSliceMsgsize(x.Ptrs))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("map")+cbor.MapHeaderSize+len(x.Map)*(cbor.StringPrefixSize+0)+cbor.MapKeysLen(x.Map))
 	return
 }
 
@@ -224,7 +229,7 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 			}
 			for iItems := uint32(0); iItems < sz; iItems++ {
 				var tmp Scalars
-				v, err = (&tmp).UnmarshalCBOR(v)
+				v, err = (&tmp).DecodeTrusted(v)
 				if err != nil {
 					return b, err
 				}
@@ -249,7 +254,7 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 				if x.Ptrs[iPtrs] == nil {
 					x.Ptrs[iPtrs] = new(Scalars)
 				}
-				v, err = x.Ptrs[iPtrs].UnmarshalCBOR(v)
+				v, err = x.Ptrs[iPtrs].DecodeTrusted(v)
 				if err != nil {
 					return b, err
 				}
@@ -273,7 +278,7 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 					return b, err
 				}
 				var tmp Scalars
-				v, err = (&tmp).UnmarshalCBOR(v)
+				v, err = (&tmp).DecodeTrusted(v)
 				if err != nil {
 					return b, err
 				}
@@ -298,7 +303,7 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 					return b, err
 				}
 				tmp := new(Scalars)
-				v, err = tmp.UnmarshalCBOR(v)
+				v, err = tmp.DecodeTrusted(v)
 				if err != nil {
 					return b, err
 				}
@@ -319,3 +324,449 @@ func (x *Containers) DecodeTrusted(b []byte) ([]byte, error) {
 func (x *Containers) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
+
+func (x IntKeys) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u32")+cbor.MapHeaderSize+len(x.U32)*(cbor.Uint32Size+cbor.StringPrefixSize)+cbor.MapValuesLen(x.U32))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u16")+cbor.MapHeaderSize+len(x.U16)*(cbor.Uint16Size+cbor.IntSize))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u8")+cbor.MapHeaderSize+len(x.U8)*(cbor.Uint8Size+cbor.BoolSize))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i16")+cbor.MapHeaderSize+len(x.I16)*(cbor.Int16Size+cbor.Float64Size))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i8")+cbor.MapHeaderSize+len(x.I8)*(cbor.Int8Size+cbor.Uint64Size))
+	return
+}
+
+func (x *IntKeys) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 6)
+	var err error
+
+	b = cbor.AppendString(b, "u32")
+	b = cbor.AppendMapHeader(b, uint32(len(x.U32)))
+	for k, v := range x.U32 {
+		b = cbor.AppendUint32(b, k)
+		b = cbor.AppendString(b, v)
+	}
+
+	b = cbor.AppendString(b, "u16")
+	b = cbor.AppendMapHeader(b, uint32(len(x.U16)))
+	for k, v := range x.U16 {
+		b = cbor.AppendUint16(b, k)
+		b = cbor.AppendInt(b, v)
+	}
+
+	b = cbor.AppendString(b, "u8")
+	b = cbor.AppendMapHeader(b, uint32(len(x.U8)))
+	for k, v := range x.U8 {
+		b = cbor.AppendUint8(b, k)
+		b = cbor.AppendBool(b, v)
+	}
+
+	b = cbor.AppendString(b, "i32")
+	b = cbor.AppendMapHeader(b, uint32(len(x.I32)))
+	for k, v := range x.I32 {
+		b = cbor.AppendInt32(b, k)
+		if v == nil {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = v.MarshalCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	b = cbor.AppendString(b, "i16")
+	b = cbor.AppendMapHeader(b, uint32(len(x.I16)))
+	for k, v := range x.I16 {
+		b = cbor.AppendInt16(b, k)
+		b = cbor.AppendFloat64(b, v)
+	}
+
+	b = cbor.AppendString(b, "i8")
+	b = cbor.AppendMapHeader(b, uint32(len(x.I8)))
+	for k, v := range x.I8 {
+		b = cbor.AppendInt8(b, k)
+		b = cbor.AppendUint64(b, v)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *IntKeys) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "u32":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.U32 == nil && sz > 0 {
+				x.U32 = make(map[uint32]string, sz)
+			} else if x.U32 != nil {
+				clear(x.U32)
+			}
+			for iU32 := uint32(0); iU32 < sz; iU32++ {
+				var key uint32
+				key, v, err = cbor.ReadUint32Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.U32[key] = tmp
+			}
+		case "u16":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.U16 == nil && sz > 0 {
+				x.U16 = make(map[uint16]int, sz)
+			} else if x.U16 != nil {
+				clear(x.U16)
+			}
+			for iU16 := uint32(0); iU16 < sz; iU16++ {
+				var key uint16
+				key, v, err = cbor.ReadUint16Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.U16[key] = tmp
+			}
+		case "u8":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.U8 == nil && sz > 0 {
+				x.U8 = make(map[uint8]bool, sz)
+			} else if x.U8 != nil {
+				clear(x.U8)
+			}
+			for iU8 := uint32(0); iU8 < sz; iU8++ {
+				var key uint8
+				key, v, err = cbor.ReadUint8Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp bool
+				tmp, v, err = cbor.ReadBoolBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.U8[key] = tmp
+			}
+		case "i32":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.I32 == nil && sz > 0 {
+				x.I32 = make(map[int32]*Scalars, sz)
+			} else if x.I32 != nil {
+				clear(x.I32)
+			}
+			for iI32 := uint32(0); iI32 < sz; iI32++ {
+				var key int32
+				key, v, err = cbor.ReadInt32Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cbor.IsNil(v) {
+					v, err = cbor.ReadNilBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.I32[key] = nil
+					continue
+				}
+				tmp := new(Scalars)
+				v, err = tmp.UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+				x.I32[key] = tmp
+			}
+		case "i16":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.I16 == nil && sz > 0 {
+				x.I16 = make(map[int16]float64, sz)
+			} else if x.I16 != nil {
+				clear(x.I16)
+			}
+			for iI16 := uint32(0); iI16 < sz; iI16++ {
+				var key int16
+				key, v, err = cbor.ReadInt16Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp float64
+				tmp, v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.I16[key] = tmp
+			}
+		case "i8":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.I8 == nil && sz > 0 {
+				x.I8 = make(map[int8]uint64, sz)
+			} else if x.I8 != nil {
+				clear(x.I8)
+			}
+			for iI8 := uint32(0); iI8 < sz; iI8++ {
+				var key int8
+				key, v, err = cbor.ReadInt8Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.I8[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *IntKeys) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "u32":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.U32 == nil && sz > 0 {
+				x.U32 = make(map[uint32]string, sz)
+			} else if x.U32 != nil {
+				clear(x.U32)
+			}
+			for iU32 := uint32(0); iU32 < sz; iU32++ {
+				var key uint32
+				key, v, err = cbor.ReadUint32Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.U32[key] = tmp
+			}
+		case "u16":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.U16 == nil && sz > 0 {
+				x.U16 = make(map[uint16]int, sz)
+			} else if x.U16 != nil {
+				clear(x.U16)
+			}
+			for iU16 := uint32(0); iU16 < sz; iU16++ {
+				var key uint16
+				key, v, err = cbor.ReadUint16Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.U16[key] = tmp
+			}
+		case "u8":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.U8 == nil && sz > 0 {
+				x.U8 = make(map[uint8]bool, sz)
+			} else if x.U8 != nil {
+				clear(x.U8)
+			}
+			for iU8 := uint32(0); iU8 < sz; iU8++ {
+				var key uint8
+				key, v, err = cbor.ReadUint8Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp bool
+				tmp, v, err = cbor.ReadBoolBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.U8[key] = tmp
+			}
+		case "i32":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.I32 == nil && sz > 0 {
+				x.I32 = make(map[int32]*Scalars, sz)
+			} else if x.I32 != nil {
+				clear(x.I32)
+			}
+			for iI32 := uint32(0); iI32 < sz; iI32++ {
+				var key int32
+				key, v, err = cbor.ReadInt32Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cbor.IsNil(v) {
+					v, err = cbor.ReadNilBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.I32[key] = nil
+					continue
+				}
+				val := new(Scalars)
+				v, err = val.DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+				x.I32[key] = val
+			}
+		case "i16":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.I16 == nil && sz > 0 {
+				x.I16 = make(map[int16]float64, sz)
+			} else if x.I16 != nil {
+				clear(x.I16)
+			}
+			for iI16 := uint32(0); iI16 < sz; iI16++ {
+				var key int16
+				key, v, err = cbor.ReadInt16Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp float64
+				tmp, v, err = cbor.ReadFloat64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.I16[key] = tmp
+			}
+		case "i8":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.I8 == nil && sz > 0 {
+				x.I8 = make(map[int8]uint64, sz)
+			} else if x.I8 != nil {
+				clear(x.I8)
+			}
+			for iI8 := uint32(0); iI8 < sz; iI8++ {
+				var key int8
+				key, v, err = cbor.ReadInt8Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp uint64
+				tmp, v, err = cbor.ReadUint64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.I8[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *IntKeys) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
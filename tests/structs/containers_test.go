@@ -1,6 +1,8 @@
 package structs
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -118,3 +120,93 @@ func TestContainersRoundTripSafeAndTrusted(t *testing.T) {
 		})
 	}
 }
+
+type intKeysDecoder struct {
+	name   string
+	decode func(dst *IntKeys, b []byte) ([]byte, error)
+}
+
+var intKeysDecoders = []intKeysDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*IntKeys).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*IntKeys).DecodeTrusted,
+	},
+}
+
+func TestIntKeysRoundTrip(t *testing.T) {
+	orig := &IntKeys{
+		U32: map[uint32]string{0: "zero", 1 << 31: "big"},
+		U16: map[uint16]int{65535: -1},
+		U8:  map[uint8]bool{255: true},
+		I32: map[int32]*Scalars{-1 << 31: {S: "min"}, 7: nil},
+		I16: map[int16]float64{-300: 1.5},
+		I8:  map[int8]uint64{-128: 1, 127: 2},
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range intKeysDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst IntKeys
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if !reflect.DeepEqual(dst.U32, orig.U32) || !reflect.DeepEqual(dst.U16, orig.U16) ||
+				!reflect.DeepEqual(dst.U8, orig.U8) || !reflect.DeepEqual(dst.I16, orig.I16) ||
+				!reflect.DeepEqual(dst.I8, orig.I8) {
+				t.Fatalf("%s scalar maps mismatch: got %+v want %+v", tc.name, dst, orig)
+			}
+			if len(dst.I32) != 2 || dst.I32[7] != nil || dst.I32[-1<<31] == nil || dst.I32[-1<<31].S != "min" {
+				t.Fatalf("%s I32 mismatch: got %+v", tc.name, dst.I32)
+			}
+		})
+	}
+}
+
+func TestIntKeysRejectOutOfRangeKeys(t *testing.T) {
+	cases := []struct {
+		name  string
+		field string
+		key   []byte
+		uint  bool
+	}{
+		{"u32", "u32", cbor.AppendUint64(nil, 1<<32), true},
+		{"u16", "u16", cbor.AppendUint64(nil, 1<<16), true},
+		{"u8", "u8", cbor.AppendUint64(nil, 256), true},
+		{"u8_negative", "u8", cbor.AppendInt64(nil, -1), false},
+		{"i32", "i32", cbor.AppendInt64(nil, -1<<31-1), false},
+		{"i16", "i16", cbor.AppendInt64(nil, 1<<15), false},
+		{"i8", "i8", cbor.AppendInt64(nil, -129), false},
+	}
+	for _, c := range cases {
+		b := cbor.AppendMapHeader(nil, 1)
+		b = cbor.AppendString(b, c.field)
+		b = cbor.AppendMapHeader(b, 1)
+		b = append(b, c.key...)
+		b = cbor.AppendNil(b)
+		for _, tc := range intKeysDecoders {
+			var dst IntKeys
+			_, err := tc.decode(&dst, b)
+			if err == nil {
+				t.Fatalf("%s/%s: expected error for out-of-range key", c.name, tc.name)
+			}
+			var uo cbor.UintOverflow
+			var io cbor.IntOverflow
+			if c.uint && !errors.As(err, &uo) {
+				t.Fatalf("%s/%s: expected UintOverflow, got %v", c.name, tc.name, err)
+			}
+			if !c.uint && c.name != "u8_negative" && !errors.As(err, &io) {
+				t.Fatalf("%s/%s: expected IntOverflow, got %v", c.name, tc.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package structs
+
+import (
+	"encoding/json"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+// Codec is a user-defined interface satisfied by generated types; fields
+// of this type are encoded and decoded through its own methods.
+type Codec interface {
+	MarshalCBOR([]byte) ([]byte, error)
+	UnmarshalCBOR([]byte) ([]byte, error)
+}
+
+// Interfaces exercises fields typed as non-empty interfaces, both named
+// and inline.
+type Interfaces struct {
+	Codec  Codec                       `cbor:"codec"`
+	Opt    Codec                       `cbor:"opt,omitempty"`
+	Inline interface{ cbor.Marshaler } `cbor:"inline"`
+}
+
+// Batch holds slices of interface values, encoded element by element
+// through MarshalCBOR. Decoding skips them.
+type Batch struct {
+	ID     int                           `cbor:"id"`
+	Items  []Codec                       `cbor:"items"`
+	Parts  []cbor.Marshaler              `cbor:"parts,omitempty"`
+	Inline []interface{ cbor.Marshaler } `cbor:"inline"`
+}
+
+// Lookalikes holds interfaces that resemble the runtime's but do not
+// have its MarshalCBOR/UnmarshalCBOR methods, so they fall back to
+// AppendInterface and are skipped on decode.
+type Lookalikes struct {
+	JSON  interface{ json.Marshaler }           `cbor:"json"`
+	Names interface{ MarshalCBOR() string }     `cbor:"names"`
+	Pair  interface{ UnmarshalCBOR(int) error } `cbor:"pair"`
+}
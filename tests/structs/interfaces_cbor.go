@@ -0,0 +1,487 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+func (x *Interfaces) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	count := uint32(2)
+	if x.Opt != nil {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+
+	b = cbor.AppendString(b, "codec")
+	if x.Codec == nil {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = x.Codec.MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if x.Opt != nil {
+
+		b = cbor.AppendString(b, "opt")
+		if x.Opt == nil {
+			b = cbor.AppendNil(b)
+		} else {
+			b, err = x.Opt.MarshalCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	b = cbor.AppendString(b, "inline")
+	if x.Inline == nil {
+		b = cbor.AppendNil(b)
+	} else {
+		b, err = x.Inline.MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Interfaces) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "codec":
+
+			if cbor.IsNil(v) {
+				x.Codec = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				if x.Codec == nil {
+					return b, cbor.ErrNilUnmarshaler
+				}
+				v, err = x.Codec.UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "opt":
+
+			if cbor.IsNil(v) {
+				x.Opt = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				if x.Opt == nil {
+					return b, cbor.ErrNilUnmarshaler
+				}
+				v, err = x.Opt.UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "inline":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Interfaces) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "codec":
+
+			if cbor.IsNil(v) {
+				x.Codec = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				if x.Codec == nil {
+					return b, cbor.ErrNilUnmarshaler
+				}
+				v, err = x.Codec.UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "opt":
+
+			if cbor.IsNil(v) {
+				x.Opt = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				if x.Opt == nil {
+					return b, cbor.ErrNilUnmarshaler
+				}
+				v, err = x.Opt.UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "inline":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Interfaces) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Batch) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("items")+cbor.ArrayHeaderSize+len(x.Items)*0)
+	return
+}
+
+func (x *Batch) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(3)
+	if len(x.Parts) != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendInt(b, x.ID)
+
+	b = cbor.AppendString(b, "items")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Items)))
+	for _, v := range x.Items {
+		if v == nil {
+			b = cbor.AppendNil(b)
+			continue
+		}
+		b, err = v.MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+	if len(x.Parts) != 0 {
+
+		b = cbor.AppendString(b, "parts")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Parts)))
+		for _, v := range x.Parts {
+			if v == nil {
+				b = cbor.AppendNil(b)
+				continue
+			}
+			b, err = v.MarshalCBOR(b)
+			if err != nil {
+				return b, err
+			}
+		}
+	}
+
+	b = cbor.AppendString(b, "inline")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Inline)))
+	for _, v := range x.Inline {
+		if v == nil {
+			b = cbor.AppendNil(b)
+			continue
+		}
+		b, err = v.MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Batch) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "id":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "items":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "parts":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "inline":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Batch) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "items":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "parts":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "inline":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Batch) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x *Lookalikes) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.AppendMapHeader(b, uint32(3))
+	var err error
+	b = cbor.AppendString(b, "json")
+	b, err = cbor.AppendInterface(b, x.JSON)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "names")
+	b, err = cbor.AppendInterface(b, x.Names)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "pair")
+	b, err = cbor.AppendInterface(b, x.Pair)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Lookalikes) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "json":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "names":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "pair":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Lookalikes) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "json":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "names":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		case "pair":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Lookalikes) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
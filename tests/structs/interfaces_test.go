@@ -0,0 +1,164 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+type interfacesDecoder struct {
+	name   string
+	decode func(dst *Interfaces, b []byte) ([]byte, error)
+}
+
+var interfacesDecoders = []interfacesDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Interfaces).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Interfaces).DecodeTrusted,
+	},
+}
+
+func TestInterfacesRoundTrip(t *testing.T) {
+	orig := &Interfaces{
+		Codec:  &Person{Name: "alice", Age: 30},
+		Inline: &Person{Name: "bob"},
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	for _, dec := range interfacesDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			// The concrete value must be in place before decoding.
+			got := &Interfaces{Codec: &Person{}}
+			rest, err := dec.decode(got, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", dec.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover: %d", dec.name, len(rest))
+			}
+			p, ok := got.Codec.(*Person)
+			if !ok || p.Name != "alice" || p.Age != 30 {
+				t.Fatalf("%s Codec mismatch: %#v", dec.name, got.Codec)
+			}
+			if got.Opt != nil {
+				t.Fatalf("%s Opt should be nil, got %#v", dec.name, got.Opt)
+			}
+			// Inline only declares MarshalCBOR, so it is skipped on decode.
+			if got.Inline != nil {
+				t.Fatalf("%s Inline should be nil, got %#v", dec.name, got.Inline)
+			}
+		})
+	}
+}
+
+func TestInterfacesNil(t *testing.T) {
+	b, err := (&Interfaces{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// Opt is omitted; Codec and Inline are encoded as null.
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"codec": null, "inline": null}`; diag != want {
+		t.Fatalf("diag mismatch: got %s want %s", diag, want)
+	}
+
+	for _, dec := range interfacesDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			got := &Interfaces{Codec: &Person{}}
+			if _, err := dec.decode(got, b); err != nil {
+				t.Fatalf("%s error: %v", dec.name, err)
+			}
+			if got.Codec != nil {
+				t.Fatalf("%s Codec should be reset to nil, got %#v", dec.name, got.Codec)
+			}
+		})
+	}
+}
+
+func TestInterfacesNilTarget(t *testing.T) {
+	b, err := (&Interfaces{Codec: &Person{Name: "alice"}}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, dec := range interfacesDecoders {
+		t.Run(dec.name, func(t *testing.T) {
+			var got Interfaces
+			_, err := dec.decode(&got, b)
+			if !errors.Is(err, cbor.ErrNilUnmarshaler) {
+				t.Fatalf("%s expected ErrNilUnmarshaler, got %v", dec.name, err)
+			}
+		})
+	}
+}
+
+func TestBatchInterfaceSlices(t *testing.T) {
+	in := &Batch{
+		ID:     1,
+		Items:  []Codec{&Person{Name: "alice"}, nil},
+		Parts:  []cbor.Marshaler{Level(1)},
+		Inline: []interface{ cbor.Marshaler }{nil},
+	}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	alice, err := (&Person{Name: "alice"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	aliceDiag, _, _ := cbor.DiagBytes(alice)
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	want := `{"id": 1, "items": [` + aliceDiag + `, null], "parts": ["high"], "inline": [null]}`
+	if diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+
+	for _, decode := range []func(*Batch, []byte) ([]byte, error){(*Batch).DecodeSafe, (*Batch).DecodeTrusted} {
+		var out Batch
+		rest, err := decode(&out, b)
+		if err != nil || len(rest) != 0 {
+			t.Fatalf("decode = %x, %v", rest, err)
+		}
+		if out.ID != 1 || out.Items != nil {
+			t.Fatalf("decode = %+v, want ID 1 and skipped slices", out)
+		}
+	}
+
+	b, err = (&Batch{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if diag, _, _ := cbor.DiagBytes(b); diag != `{"id": 0, "items": [], "inline": []}` {
+		t.Fatalf("empty diag = %s", diag)
+	}
+}
+
+func TestLookalikesFallBack(t *testing.T) {
+	b, err := (&Lookalikes{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if diag, _, _ := cbor.DiagBytes(b); diag != `{"json": null, "names": null, "pair": null}` {
+		t.Fatalf("diag = %s", diag)
+	}
+	for _, decode := range []func(*Lookalikes, []byte) ([]byte, error){(*Lookalikes).DecodeSafe, (*Lookalikes).DecodeTrusted} {
+		var out Lookalikes
+		if rest, err := decode(&out, b); err != nil || len(rest) != 0 {
+			t.Fatalf("decode = %x, %v", rest, err)
+		}
+	}
+}
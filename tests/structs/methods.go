@@ -0,0 +1,77 @@
+package structs
+
+import (
+	"fmt"
+	"math/big"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+// Level is encoded as its name by hand-written methods.
+type Level uint8
+
+var levelNames = []string{"low", "high"}
+
+func (l Level) MarshalCBOR(b []byte) ([]byte, error) {
+	if int(l) >= len(levelNames) {
+		return b, fmt.Errorf("invalid level %d", l)
+	}
+	return cbor.AppendString(b, levelNames[l]), nil
+}
+
+func (l *Level) UnmarshalCBOR(b []byte) ([]byte, error) {
+	s, o, err := cbor.ReadStringBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i, name := range levelNames {
+		if name == s {
+			*l = Level(i)
+			return o, nil
+		}
+	}
+	return b, fmt.Errorf("invalid level %q", s)
+}
+
+// Reading has fields whose types cborgen has no built-in encoding for,
+// local and from another package. They are encoded through the types'
+// own methods, which the generated code checks for at compile time.
+type Reading struct {
+	Level Level        `cbor:"level"`
+	Value cbor.Number  `cbor:"value"`
+	Peak  *cbor.Number `cbor:"peak,omitempty"`
+}
+
+// Ledger has a field of a type from another package that cborgen
+// cannot know to have MarshalCBOR/UnmarshalCBOR methods, so it is
+// encoded with AppendInterface and skipped on decode.
+type Ledger struct {
+	Name  string   `cbor:"name"`
+	Total *big.Int `cbor:"total"`
+}
+
+// Blob is a byte string whose CBORSize reports its exact encoded size.
+type Blob []byte
+
+func (p Blob) MarshalCBOR(b []byte) ([]byte, error) {
+	return cbor.AppendBytes(b, p), nil
+}
+
+func (p *Blob) UnmarshalCBOR(b []byte) ([]byte, error) {
+	v, o, err := cbor.ReadBytesBytes(b, nil)
+	if err != nil {
+		return b, err
+	}
+	*p = v
+	return o, nil
+}
+
+func (p Blob) CBORSize() int {
+	return cbor.BytesPrefixSize + len(p)
+}
+
+// Upload sizes its Blob field with Blob.CBORSize in Msgsize.
+type Upload struct {
+	Name string `cbor:"name"`
+	Data Blob   `cbor:"data"`
+}
@@ -0,0 +1,380 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+func (x *Reading) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	count := uint32(2)
+	if x.Peak != nil {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "level")
+	b, err = x.Level.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "value")
+	b, err = x.Value.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	if x.Peak != nil {
+		b = cbor.AppendString(b, "peak")
+		b, err = cbor.AppendPtrMarshaler(b, x.Peak)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Reading) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "level":
+
+			v, err = x.Level.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "value":
+
+			v, err = x.Value.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "peak":
+
+			if x.Peak == nil {
+				x.Peak = new(cbor.Number)
+			}
+			v, err = x.Peak.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Reading) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "level":
+
+			v, err = x.Level.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "value":
+
+			v, err = x.Value.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "peak":
+
+			if x.Peak == nil {
+				x.Peak = new(cbor.Number)
+			}
+			v, err = x.Peak.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Reading) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// Compile-time checks for Reading fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*Level)(nil)       // Level
+	_ cbor.Unmarshaler = (*Level)(nil)       // Level
+	_ cbor.Marshaler   = (*cbor.Number)(nil) // Value
+	_ cbor.Unmarshaler = (*cbor.Number)(nil) // Value
+	_ cbor.Marshaler   = (*cbor.Number)(nil) // Peak
+	_ cbor.Unmarshaler = (*cbor.Number)(nil) // Peak
+)
+
+func (x Ledger) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	return
+}
+
+func (x *Ledger) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "total")
+	b, err = cbor.AppendInterface(b, x.Total)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Ledger) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "total":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Ledger) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "total":
+
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Ledger) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Upload) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("data")+x.Data.CBORSize())
+	return
+}
+
+func (x *Upload) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "data")
+	b, err = x.Data.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Upload) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "data":
+
+			v, err = x.Data.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Upload) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "data":
+
+			v, err = x.Data.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Upload) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// Compile-time checks for Upload fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*Blob)(nil) // Data
+	_ cbor.Unmarshaler = (*Blob)(nil) // Data
+)
@@ -0,0 +1,87 @@
+package structs
+
+import (
+	"math/big"
+	"testing"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func TestReadingMethodFields(t *testing.T) {
+	var value, peak cbor.Number
+	value.AsFloat64(20.5)
+	peak.AsUint(30)
+	in := Reading{Level: 1, Value: value, Peak: &peak}
+
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"level": "high", "value": 20.5, "peak": 30}`; diag != want {
+		t.Fatalf("diag = %s want %s", diag, want)
+	}
+
+	for _, decode := range []func(*Reading, []byte) ([]byte, error){(*Reading).DecodeSafe, (*Reading).DecodeTrusted} {
+		var out Reading
+		rest, err := decode(&out, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("leftover: %d", len(rest))
+		}
+		if out.Level != in.Level || out.Value != in.Value || out.Peak == nil || *out.Peak != peak {
+			t.Fatalf("got %+v want %+v", out, in)
+		}
+	}
+
+	if _, err := (&Reading{Level: 2}).MarshalCBOR(nil); err == nil {
+		t.Fatalf("expected error from Level.MarshalCBOR")
+	}
+}
+
+func TestUploadMsgsizeUsesCBORSize(t *testing.T) {
+	for _, n := range []int{0, 100, 100000} {
+		in := Upload{Name: "f", Data: make(Blob, n)}
+		b, err := in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		if got := in.Msgsize(); got < len(b) {
+			t.Fatalf("Msgsize() = %d for %d-byte Data, encoded %d bytes", got, n, len(b))
+		}
+		var out Upload
+		if _, err := out.DecodeTrusted(b); err != nil || len(out.Data) != n {
+			t.Fatalf("decode = %d bytes, %v", len(out.Data), err)
+		}
+	}
+}
+
+func TestLedgerBigIntFallsBack(t *testing.T) {
+	in := Ledger{Name: "l", Total: new(big.Int).Lsh(big.NewInt(1), 64)}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"name": "l", "total": 2(h'010000000000000000')}`; diag != want {
+		t.Fatalf("diag = %s want %s", diag, want)
+	}
+
+	for _, decode := range []func(*Ledger, []byte) ([]byte, error){(*Ledger).DecodeSafe, (*Ledger).DecodeTrusted} {
+		var out Ledger
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if out.Name != "l" || out.Total != nil {
+			t.Fatalf("decode = %+v, want the name and no total", out)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package structs
+
+import "net"
+
+// NetAddrs exercises net.IP and net.HardwareAddr fields, which are
+// encoded as tag 260 network addresses.
+type NetAddrs struct {
+	V4  net.IP           `cbor:"v4"`
+	V6  net.IP           `cbor:"v6"`
+	MAC net.HardwareAddr `cbor:"mac"`
+	Opt net.IP           `cbor:"opt,omitempty"`
+}
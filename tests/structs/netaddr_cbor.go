@@ -0,0 +1,230 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"net"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func (x NetAddrs) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("v4")+cbor.IPSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("v6")+cbor.IPSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("mac")+cbor.HardwareAddrSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("opt")+cbor.IPSize)
+	return
+}
+
+func (x *NetAddrs) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(3)
+	if len(x.Opt) != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "v4")
+	b = cbor.AppendIP(b, x.V4)
+	b = cbor.AppendString(b, "v6")
+	b = cbor.AppendIP(b, x.V6)
+	b = cbor.AppendString(b, "mac")
+	b = cbor.AppendHardwareAddr(b, x.MAC)
+	if len(x.Opt) != 0 {
+		b = cbor.AppendString(b, "opt")
+		b = cbor.AppendIP(b, x.Opt)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *NetAddrs) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "v4":
+
+			if cbor.IsNil(v) {
+				x.V4 = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.IP
+				tmp, v, err = cbor.ReadIPBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.V4 = tmp
+			}
+		case "v6":
+
+			if cbor.IsNil(v) {
+				x.V6 = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.IP
+				tmp, v, err = cbor.ReadIPBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.V6 = tmp
+			}
+		case "mac":
+
+			if cbor.IsNil(v) {
+				x.MAC = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.HardwareAddr
+				tmp, v, err = cbor.ReadHardwareAddrBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.MAC = tmp
+			}
+		case "opt":
+
+			if cbor.IsNil(v) {
+				x.Opt = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.IP
+				tmp, v, err = cbor.ReadIPBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Opt = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *NetAddrs) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "v4":
+
+			if cbor.IsNil(v) {
+				x.V4 = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.IP
+				tmp, v, err = cbor.ReadIPBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.V4 = tmp
+			}
+		case "v6":
+
+			if cbor.IsNil(v) {
+				x.V6 = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.IP
+				tmp, v, err = cbor.ReadIPBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.V6 = tmp
+			}
+		case "mac":
+
+			if cbor.IsNil(v) {
+				x.MAC = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.HardwareAddr
+				tmp, v, err = cbor.ReadHardwareAddrBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.MAC = tmp
+			}
+		case "opt":
+
+			if cbor.IsNil(v) {
+				x.Opt = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var tmp net.IP
+				tmp, v, err = cbor.ReadIPBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Opt = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *NetAddrs) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
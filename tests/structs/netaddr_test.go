@@ -0,0 +1,85 @@
+package structs
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+type netAddrsDecoder struct {
+	name   string
+	decode func(dst *NetAddrs, b []byte) ([]byte, error)
+}
+
+var netAddrsDecoders = []netAddrsDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*NetAddrs).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*NetAddrs).DecodeTrusted,
+	},
+}
+
+func TestNetAddrsRoundTrip(t *testing.T) {
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	orig := &NetAddrs{
+		V4:  net.ParseIP("192.0.2.1"),
+		V6:  net.ParseIP("2001:db8::1"),
+		MAC: mac,
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	// IPv4 addresses use the 4-byte form: tag 260, h'c0000201'.
+	if !bytes.Contains(b, []byte{0xd9, 0x01, 0x04, 0x44, 0xc0, 0x00, 0x02, 0x01}) {
+		t.Fatalf("expected 4-byte tagged IPv4 address in %x", b)
+	}
+	for _, tc := range netAddrsDecoders {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst NetAddrs
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if !dst.V4.Equal(orig.V4) || !dst.V6.Equal(orig.V6) || dst.MAC.String() != orig.MAC.String() || dst.Opt != nil {
+				t.Fatalf("%s mismatch: got %+v want %+v", tc.name, dst, orig)
+			}
+		})
+	}
+}
+
+func TestNetAddrsNilAndInvalid(t *testing.T) {
+	b, err := (&NetAddrs{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range netAddrsDecoders {
+		dst := NetAddrs{V4: net.IPv4(1, 2, 3, 4)}
+		if _, err := tc.decode(&dst, b); err != nil {
+			t.Fatalf("%s error: %v", tc.name, err)
+		}
+		if dst.V4 != nil || dst.V6 != nil || dst.MAC != nil {
+			t.Fatalf("%s: expected nil addresses, got %+v", tc.name, dst)
+		}
+	}
+
+	// A 5-byte address is neither IPv4 nor IPv6.
+	bad := cbor.AppendMapHeader(nil, 1)
+	bad = cbor.AppendString(bad, "v4")
+	bad = cbor.AppendTag(bad, 260)
+	bad = cbor.AppendBytes(bad, []byte{1, 2, 3, 4, 5})
+	for _, tc := range netAddrsDecoders {
+		var dst NetAddrs
+		if _, err := tc.decode(&dst, bad); err == nil {
+			t.Fatalf("%s: expected error for 5-byte address", tc.name)
+		}
+	}
+}
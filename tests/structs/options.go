@@ -0,0 +1,246 @@
+package structs
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+// Options exercises per-field cbor tag options that change how a
+// field is represented on the wire.
+type Options struct {
+	Seq     int64         `cbor:"seq,uint"`
+	Count   int32         `cbor:",uint"`
+	Small   int8          `cbor:"small,uint"`
+	Timeout time.Duration `cbor:"timeout,string"`
+	ID      int64         `cbor:"id,string"`
+	Port    uint16        `cbor:"port,string"`
+}
+
+// Flow encodes every field, even those marked omitempty, because of the
+// struct-level ",flow" option.
+type Flow struct {
+	_     struct{} `cbor:",flow"`
+	Name  string   `cbor:"name,omitempty"`
+	Count int      `cbor:"count,omitempty"`
+	Tags  []string `cbor:"tags,omitempty"`
+}
+
+// Envelope carries a pre-encoded CBOR item in Payload, embedded verbatim
+// rather than as a byte string.
+type Envelope struct {
+	Kind    string `cbor:"kind"`
+	Payload []byte `cbor:"payload,rawcbor"`
+}
+
+// Extended splices the pre-encoded map entries held in Extra into its own
+// map, after the keyed fields.
+type Extended struct {
+	Name  string `cbor:"name"`
+	Extra []byte `cbor:",flatten"`
+}
+
+// Digest carries a hash as hex text rather than a byte string.
+type Digest struct {
+	Algo string `cbor:"algo"`
+	Sum  []byte `cbor:"sum,hex"`
+}
+
+// Tracked carries a UUID encoded as tag 37 rather than a byte string.
+type Tracked struct {
+	ID   [16]byte `cbor:"id,uuid"`
+	Name string   `cbor:"name"`
+}
+
+// Point is encoded as a positional array, [x, y, label], because of the
+// struct-level ",toarray" option.
+type Point struct {
+	_     struct{} `cbor:",toarray"`
+	X     int64    `cbor:"x"`
+	Y     int64    `cbor:"y"`
+	Label string   `cbor:"label,omitempty"`
+	Tags  []string `cbor:"tags"`
+}
+
+// Loose accepts map keys in any case, e.g. "Name" or "NAME" for "name",
+// because of the struct-level ",nocase" option.
+type Loose struct {
+	_     struct{} `cbor:",nocase"`
+	Name  string   `cbor:"name"`
+	Count int      `cbor:"count,omitempty"`
+}
+
+// Bounded declares field constraints checked by its generated Validate
+// method.
+type Bounded struct {
+	Percent int      `cbor:"percent,min=0,max=100"`
+	Ratio   float64  `cbor:"ratio,min=0,max=1"`
+	Name    string   `cbor:"name,minlen=1"`
+	Tags    []string `cbor:"tags,omitempty,maxlen=3"`
+	Owner   string   `cbor:"owner,omitempty,required"`
+}
+
+// Explicit always writes every key, with null standing in for fields
+// that hold their zero value.
+type Explicit struct {
+	Name    string        `cbor:"name,null"`
+	Count   int           `cbor:"count,null"`
+	Tags    []string      `cbor:"tags,null"`
+	Timeout time.Duration `cbor:"timeout,string,skipnull"`
+}
+
+// Quota holds counts that must never be negative: encoding fails for a
+// negative value and decoding rejects negative integers.
+type Quota struct {
+	Limit int64 `cbor:"limit,unsigned"`
+	Used  int   `cbor:"used,unsigned"`
+}
+
+// Profile gained Email in schema version 2 and Nick in version 3;
+// EncodeVersion leaves them out for older readers.
+type Profile struct {
+	Name  string `cbor:"name"`
+	Email string `cbor:"email,version=2"`
+	Nick  string `cbor:"nick,omitempty,version=3"`
+}
+
+// SeqTable keeps its hot sequence number columns as typed arrays.
+type SeqTable struct {
+	Seqs  []uint64 `cbor:"seqs,compact"`
+	Sizes []uint32 `cbor:"sizes,compact,omitempty"`
+	Ports []uint16 `cbor:"ports,compact"`
+}
+
+// Scratch is opted out of generation; no methods are emitted for it.
+type Scratch struct {
+	_    struct{} `cbor:",ignore"`
+	Name string   `cbor:"name"`
+}
+
+// Sample is keyed by integers: fields take their position as key
+// unless they set one with key=N.
+type Sample struct {
+	_      struct{}          `cbor:",keytype=int"`
+	Sensor string            `cbor:"sensor"`
+	Value  int64             `cbor:"value,omitempty"`
+	Labels map[string]string `cbor:"labels,omitempty"`
+	Note   string            `cbor:"note,null"`
+	Unit   string            `cbor:"unit,key=10"`
+}
+
+// Endpoint keeps Host and Port for older peers; new code sets URL.
+type Endpoint struct {
+	URL  string `cbor:"url"`
+	Host string `cbor:"host,omitempty,deprecated=use URL instead"`
+	Port int    `cbor:"port,omitempty,deprecated"`
+}
+
+// Relayed keeps map entries it does not know about in Rest, so a
+// proxy can pass newer fields through unchanged.
+type Relayed struct {
+	ID   string   `cbor:"id"`
+	Kind string   `cbor:"kind,omitempty"`
+	Rest cbor.Raw `cbor:",fallthrough"`
+}
+
+// ConsumerSpec and StreamSpec are the payloads a Command can carry.
+type ConsumerSpec struct {
+	Stream  string `cbor:"stream"`
+	Durable string `cbor:"durable"`
+}
+
+type StreamSpec struct {
+	Stream   string   `cbor:"stream"`
+	Subjects []string `cbor:"subjects,omitempty"`
+	Replicas int      `cbor:"replicas,omitempty"`
+}
+
+// Command holds either spec; decoding picks the type by the keys only
+// one of them has ("durable", or "subjects"/"replicas").
+type Command struct {
+	Op   string `cbor:"op"`
+	Spec any    `cbor:"spec,oneof=ConsumerSpec,StreamSpec"`
+}
+
+// Guarded shares its counters between goroutines; the locks, exported
+// or not, are not part of the encoding.
+type Guarded struct {
+	sync.Mutex
+	Lock  sync.RWMutex
+	mu    sync.Mutex
+	Hits  int64  `cbor:"hits"`
+	Owner string `cbor:"owner,omitempty"`
+}
+
+// Pool nests lock-holding structs by pointer and in a slice. Their
+// pointer-receiver Msgsize is not used for its own.
+type Pool struct {
+	Primary *Guarded  `cbor:"primary"`
+	Members []Guarded `cbor:"members"`
+}
+
+// Webhook is only ever received, so it gets decoders but no MarshalCBOR.
+type Webhook struct {
+	_      struct{}          `cbor:",readonly"`
+	Event  string            `cbor:"event"`
+	Labels map[string]string `cbor:"labels,omitempty"`
+}
+
+// AuditRecord is only ever written, so it gets MarshalCBOR and Msgsize
+// but no decoders.
+type AuditRecord struct {
+	_      struct{} `cbor:",writeonly"`
+	Actor  string   `cbor:"actor"`
+	Action string   `cbor:"action"`
+	Seq    uint64   `cbor:"seq,omitempty"`
+}
+
+// Stamp gets a generated MarshalCBOR but decodes through the
+// hand-written UnmarshalCBOR below.
+type Stamp struct {
+	_  struct{} `cbor:",toarray,writeonly"`
+	By string   `cbor:"by"`
+	At uint64   `cbor:"at"`
+}
+
+func (s *Stamp) UnmarshalCBOR(b []byte) ([]byte, error) {
+	n, o, err := cbor.ReadArrayHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	if n != 2 {
+		return b, fmt.Errorf("stamp: expected 2 elements, got %d", n)
+	}
+	if s.By, o, err = cbor.ReadStringBytes(o); err != nil {
+		return b, err
+	}
+	if s.At, o, err = cbor.ReadUint64Bytes(o); err != nil {
+		return b, err
+	}
+	return o, nil
+}
+
+// Signed holds writeonly Stamps, which it decodes with their
+// UnmarshalCBOR since they have no DecodeSafe or DecodeTrusted.
+type Signed struct {
+	Body   string `cbor:"body"`
+	Stamp  Stamp  `cbor:"stamp"`
+	Cosign *Stamp `cbor:"cosign,omitempty"`
+}
+
+// Link carries URLs as tag 32 URIs.
+type Link struct {
+	Href url.URL  `cbor:"href"`
+	Next *url.URL `cbor:"next"`
+}
+
+// Timestamps picks the wire form of each time.Time field.
+type Timestamps struct {
+	Default time.Time `cbor:"default"`
+	Epoch   time.Time `cbor:"epoch,epoch"`
+	Text    time.Time `cbor:"text,rfc3339"`
+	Millis  time.Time `cbor:"millis,epochms"`
+}
@@ -0,0 +1,4102 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func (x Options) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("seq")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("Count")+cbor.Int32Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("small")+cbor.Int8Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("timeout")+cbor.DurationSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.StringPrefixSize+20)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("port")+cbor.StringPrefixSize+20)
+	return
+}
+
+func (x *Options) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(6))
+	b = cbor.AppendString(b, "seq")
+	b = cbor.AppendUint64(b, uint64(x.Seq))
+	b = cbor.AppendString(b, "Count")
+	b = cbor.AppendUint64(b, uint64(x.Count))
+	b = cbor.AppendString(b, "small")
+	b = cbor.AppendUint64(b, uint64(x.Small))
+	b = cbor.AppendString(b, "timeout")
+	b = cbor.AppendString(b, x.Timeout.String())
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendString(b, strconv.FormatInt(int64(x.ID), 10))
+	b = cbor.AppendString(b, "port")
+	b = cbor.AppendString(b, strconv.FormatUint(uint64(x.Port), 10))
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Options) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "seq":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt64 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Seq = int64(tmp)
+		case "Count":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt32 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 32}
+			}
+			x.Count = int32(tmp)
+		case "small":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt8 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 8}
+			}
+			x.Small = int8(tmp)
+		case "timeout":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Timeout, err = time.ParseDuration(tmp)
+			if err != nil {
+				return b, err
+			}
+		case "id":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			var n int64
+			n, err = strconv.ParseInt(tmp, 10, 64)
+			if err != nil {
+				return b, err
+			}
+			x.ID = int64(n)
+		case "port":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			var n uint64
+			n, err = strconv.ParseUint(tmp, 10, 16)
+			if err != nil {
+				return b, err
+			}
+			x.Port = uint16(n)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Options) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "seq":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt64 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Seq = int64(tmp)
+		case "Count":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt32 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 32}
+			}
+			x.Count = int32(tmp)
+		case "small":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt8 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 8}
+			}
+			x.Small = int8(tmp)
+		case "timeout":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Timeout, err = time.ParseDuration(cbor.UnsafeString(tmpBytes))
+			if err != nil {
+				return b, err
+			}
+		case "id":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			var n int64
+			n, err = strconv.ParseInt(cbor.UnsafeString(tmpBytes), 10, 64)
+			if err != nil {
+				return b, err
+			}
+			x.ID = int64(n)
+		case "port":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			var n uint64
+			n, err = strconv.ParseUint(cbor.UnsafeString(tmpBytes), 10, 16)
+			if err != nil {
+				return b, err
+			}
+			x.Port = uint16(n)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Options) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Flow) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("count")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tags")+cbor.ArrayHeaderSize+len(x.Tags)*cbor.StringPrefixSize+cbor.StringsLen(x.Tags))
+	return
+}
+
+func (x *Flow) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(3))
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "count")
+	b = cbor.AppendInt(b, x.Count)
+
+	b = cbor.AppendString(b, "tags")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+	for _, v := range x.Tags {
+		b = cbor.AppendString(b, v)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Flow) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "count":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Count = tmp
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Flow) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "count":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Count = tmp
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Flow) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Envelope) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("kind")+cbor.StringPrefixSize+len(x.Kind))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("payload")+cbor.BytesPrefixSize+len(x.Payload))
+	return
+}
+
+func (x *Envelope) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	b = cbor.AppendString(b, "kind")
+	b = cbor.AppendString(b, x.Kind)
+	b = cbor.AppendString(b, "payload")
+	b = cbor.AppendRaw(b, x.Payload)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Envelope) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "kind":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Kind = tmp
+		case "payload":
+
+			var next []byte
+			next, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+			if cbor.IsNil(v) {
+				x.Payload = nil
+			} else {
+				x.Payload = append(x.Payload[:0], v[:len(v)-len(next)]...)
+			}
+			v = next
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Envelope) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "kind":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Kind = cbor.UnsafeString(tmpBytes)
+		case "payload":
+
+			var next []byte
+			next, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+			if cbor.IsNil(v) {
+				x.Payload = nil
+			} else {
+				x.Payload = v[: len(v)-len(next) : len(v)-len(next)]
+			}
+			v = next
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Envelope) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Extended) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, len(x.Extra))
+	return
+}
+
+func (x *Extended) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	{
+		n, err := cbor.CountMapPairs(x.Extra)
+		if err != nil {
+			return b, err
+		}
+		count += n
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+
+	b = append(b, x.Extra...)
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Extended) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Extended) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Extended) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Digest) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("algo")+cbor.StringPrefixSize+len(x.Algo))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("sum")+cbor.StringPrefixSize+2*len(x.Sum))
+	return
+}
+
+func (x *Digest) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	b = cbor.AppendString(b, "algo")
+	b = cbor.AppendString(b, x.Algo)
+	b = cbor.AppendString(b, "sum")
+	b = cbor.AppendHexString(b, x.Sum)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Digest) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "algo":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Algo = tmp
+		case "sum":
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadHexStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Sum = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Digest) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "algo":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Algo = cbor.UnsafeString(tmpBytes)
+		case "sum":
+
+			var tmp []byte
+			tmp, v, err = cbor.ReadHexStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Sum = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Digest) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Tracked) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.UUIDSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	return
+}
+
+func (x *Tracked) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendUUID(b, x.ID)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Tracked) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "id":
+
+			var tmp [16]byte
+			tmp, v, err = cbor.ReadUUIDBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Tracked) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+
+			var tmp [16]byte
+			tmp, v, err = cbor.ReadUUIDBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Tracked) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Point) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("x")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("y")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("label")+cbor.StringPrefixSize+len(x.Label))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tags")+cbor.ArrayHeaderSize+len(x.Tags)*cbor.StringPrefixSize+cbor.StringsLen(x.Tags))
+	return
+}
+
+func (x *Point) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendArrayHeader(b, 4)
+	b = cbor.AppendInt64(b, x.X)
+	b = cbor.AppendInt64(b, x.Y)
+	b = cbor.AppendString(b, x.Label)
+
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+	for _, v := range x.Tags {
+		b = cbor.AppendString(b, v)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Point) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if cbor.NextType(b) == cbor.ArrayType {
+		sz, rest, err := cbor.ReadArrayHeaderBytes(b)
+		if err != nil {
+			return b, err
+		}
+		for i := uint32(0); i < sz; i++ {
+			v := rest
+			switch i {
+			case 0:
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.X = tmp
+			case 1:
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Y = tmp
+			case 2:
+
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Label = tmp
+			case 3:
+
+				var sz uint32
+				sz, v, err = cbor.ReadArrayHeaderBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Tags) >= int(sz) {
+					x.Tags = x.Tags[:sz]
+				} else {
+					x.Tags = make([]string, sz)
+				}
+				if sz > 0 {
+					_ = x.Tags[sz-1]
+				}
+				for iTags := uint32(0); iTags < sz; iTags++ {
+					var tmp string
+					tmp, v, err = cbor.ReadStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Tags[iTags] = tmp
+				}
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+		}
+		return rest, nil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "x":
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.X = tmp
+		case "y":
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Y = tmp
+		case "label":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Label = tmp
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Point) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	if cbor.NextType(b) == cbor.ArrayType {
+		sz, rest, err := cbor.ReadArrayHeaderBytes(b)
+		if err != nil {
+			return b, err
+		}
+		for i := uint32(0); i < sz; i++ {
+			v := rest
+			switch i {
+			case 0:
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.X = tmp
+			case 1:
+
+				var tmp int64
+				tmp, v, err = cbor.ReadInt64Bytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Y = tmp
+			case 2:
+
+				var tmpBytes []byte
+				tmpBytes, v, err = cbor.ReadStringZC(v)
+				if err != nil {
+					return b, err
+				}
+				x.Label = cbor.UnsafeString(tmpBytes)
+			case 3:
+
+				var sz uint32
+				sz, v, err = cbor.ReadArrayHeaderBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Tags) >= int(sz) {
+					x.Tags = x.Tags[:sz]
+				} else {
+					x.Tags = make([]string, sz)
+				}
+				if sz > 0 {
+					_ = x.Tags[sz-1]
+				}
+				for iTags := uint32(0); iTags < sz; iTags++ {
+					var tmp string
+					tmp, v, err = cbor.ReadStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Tags[iTags] = tmp
+				}
+			default:
+				v, err = cbor.Skip(v)
+				if err != nil {
+					return b, err
+				}
+			}
+			rest = v
+		}
+		return rest, nil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "x":
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.X = tmp
+		case "y":
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Y = tmp
+		case "label":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Label = cbor.UnsafeString(tmpBytes)
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Point) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Loose) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("count")+cbor.IntSize)
+	return
+}
+
+func (x *Loose) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if x.Count != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	if x.Count != 0 {
+		b = cbor.AppendString(b, "count")
+		b = cbor.AppendInt(b, x.Count)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Loose) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		key = cbor.FoldKey(key, "name", "count")
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "count":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Count = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Loose) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		key = cbor.FoldKey(key, "name", "count")
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "count":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Count = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Loose) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Bounded) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("percent")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ratio")+cbor.Float64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tags")+cbor.ArrayHeaderSize+len(x.Tags)*cbor.StringPrefixSize+cbor.StringsLen(x.Tags))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("owner")+cbor.StringPrefixSize+len(x.Owner))
+	return
+}
+
+func (x *Bounded) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(3)
+	if len(x.Tags) != 0 {
+		count++
+	}
+	if x.Owner != "" {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "percent")
+	b = cbor.AppendInt(b, x.Percent)
+	b = cbor.AppendString(b, "ratio")
+	b = cbor.AppendFloat64(b, x.Ratio)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	if len(x.Tags) != 0 {
+
+		b = cbor.AppendString(b, "tags")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+		for _, v := range x.Tags {
+			b = cbor.AppendString(b, v)
+		}
+	}
+	if x.Owner != "" {
+		b = cbor.AppendString(b, "owner")
+		b = cbor.AppendString(b, x.Owner)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Bounded) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "percent":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Percent = tmp
+		case "ratio":
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Ratio = tmp
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		case "owner":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Owner = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Bounded) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "percent":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Percent = tmp
+		case "ratio":
+
+			var tmp float64
+			tmp, v, err = cbor.ReadFloat64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Ratio = tmp
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "tags":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Tags) >= int(sz) {
+				x.Tags = x.Tags[:sz]
+			} else {
+				x.Tags = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Tags[sz-1]
+			}
+			for iTags := uint32(0); iTags < sz; iTags++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Tags[iTags] = tmp
+			}
+		case "owner":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Owner = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Bounded) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// Validate checks the constraints declared in the field tags of Bounded
+// and returns a cbor.ValidationError for the first one violated.
+func (x *Bounded) Validate() error {
+	if x.Percent < 0 {
+		return cbor.ValidationError{Field: "percent", Rule: "min=0"}
+	}
+	if x.Percent > 100 {
+		return cbor.ValidationError{Field: "percent", Rule: "max=100"}
+	}
+	if x.Ratio < 0 {
+		return cbor.ValidationError{Field: "ratio", Rule: "min=0"}
+	}
+	if x.Ratio > 1 {
+		return cbor.ValidationError{Field: "ratio", Rule: "max=1"}
+	}
+	if len(x.Name) < 1 {
+		return cbor.ValidationError{Field: "name", Rule: "minlen=1"}
+	}
+	if len(x.Tags) > 3 {
+		return cbor.ValidationError{Field: "tags", Rule: "maxlen=3"}
+	}
+	if !(x.Owner != "") {
+		return cbor.ValidationError{Field: "owner", Rule: "required"}
+	}
+	return nil
+}
+
+func (x Explicit) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("count")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("tags")+cbor.ArrayHeaderSize+len(x.Tags)*cbor.StringPrefixSize+cbor.StringsLen(x.Tags))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("timeout")+cbor.DurationSize)
+	return
+}
+
+func (x *Explicit) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(4))
+
+	b = cbor.AppendString(b, "name")
+	if x.Name != "" {
+		b = cbor.AppendString(b, x.Name)
+	} else {
+		b = cbor.AppendNil(b)
+	}
+
+	b = cbor.AppendString(b, "count")
+	if x.Count != 0 {
+		b = cbor.AppendInt(b, x.Count)
+	} else {
+		b = cbor.AppendNil(b)
+	}
+
+	b = cbor.AppendString(b, "tags")
+	if len(x.Tags) != 0 {
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Tags)))
+		for _, v := range x.Tags {
+			b = cbor.AppendString(b, v)
+		}
+	} else {
+		b = cbor.AppendNil(b)
+	}
+
+	b = cbor.AppendString(b, "timeout")
+	if x.Timeout != 0 {
+		b = cbor.AppendString(b, x.Timeout.String())
+	} else {
+		b = cbor.AppendNil(b)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Explicit) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			if cbor.IsNil(v) {
+				var zero string
+				x.Name = zero
+				v = v[1:]
+			} else {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Name = tmp
+			}
+		case "count":
+
+			if cbor.IsNil(v) {
+				var zero int
+				x.Count = zero
+				v = v[1:]
+			} else {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Count = tmp
+			}
+		case "tags":
+
+			if cbor.IsNil(v) {
+				var zero []string
+				x.Tags = zero
+				v = v[1:]
+			} else {
+				var sz uint32
+				sz, v, err = cbor.ReadArrayHeaderBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Tags) >= int(sz) {
+					x.Tags = x.Tags[:sz]
+				} else {
+					x.Tags = make([]string, sz)
+				}
+				if sz > 0 {
+					_ = x.Tags[sz-1]
+				}
+				for iTags := uint32(0); iTags < sz; iTags++ {
+					var tmp string
+					tmp, v, err = cbor.ReadStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Tags[iTags] = tmp
+				}
+			}
+		case "timeout":
+
+			if cbor.IsNil(v) {
+				var zero time.Duration
+				x.Timeout = zero
+				v = v[1:]
+			} else {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Timeout, err = time.ParseDuration(tmp)
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Explicit) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			if cbor.IsNil(v) {
+				var zero string
+				x.Name = zero
+				v = v[1:]
+			} else {
+				var tmpBytes []byte
+				tmpBytes, v, err = cbor.ReadStringZC(v)
+				if err != nil {
+					return b, err
+				}
+				x.Name = cbor.UnsafeString(tmpBytes)
+			}
+		case "count":
+
+			if cbor.IsNil(v) {
+				var zero int
+				x.Count = zero
+				v = v[1:]
+			} else {
+				var tmp int
+				tmp, v, err = cbor.ReadIntBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Count = tmp
+			}
+		case "tags":
+
+			if cbor.IsNil(v) {
+				var zero []string
+				x.Tags = zero
+				v = v[1:]
+			} else {
+				var sz uint32
+				sz, v, err = cbor.ReadArrayHeaderBytes(v)
+				if err != nil {
+					return b, err
+				}
+				if cap(x.Tags) >= int(sz) {
+					x.Tags = x.Tags[:sz]
+				} else {
+					x.Tags = make([]string, sz)
+				}
+				if sz > 0 {
+					_ = x.Tags[sz-1]
+				}
+				for iTags := uint32(0); iTags < sz; iTags++ {
+					var tmp string
+					tmp, v, err = cbor.ReadStringBytes(v)
+					if err != nil {
+						return b, err
+					}
+					x.Tags[iTags] = tmp
+				}
+			}
+		case "timeout":
+
+			if cbor.IsNil(v) {
+				var zero time.Duration
+				x.Timeout = zero
+				v = v[1:]
+			} else {
+				var tmpBytes []byte
+				tmpBytes, v, err = cbor.ReadStringZC(v)
+				if err != nil {
+					return b, err
+				}
+				x.Timeout, err = time.ParseDuration(cbor.UnsafeString(tmpBytes))
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Explicit) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Quota) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("limit")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("used")+cbor.IntSize)
+	return
+}
+
+func (x *Quota) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+
+	b = cbor.AppendString(b, "limit")
+	if x.Limit < 0 {
+		return b, cbor.ErrNegativeUnsigned
+	}
+	b = cbor.AppendUint64(b, uint64(x.Limit))
+
+	b = cbor.AppendString(b, "used")
+	if x.Used < 0 {
+		return b, cbor.ErrNegativeUnsigned
+	}
+	b = cbor.AppendUint64(b, uint64(x.Used))
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Quota) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "limit":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt64 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Limit = int64(tmp)
+		case "used":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Used = int(tmp)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Quota) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "limit":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt64 {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Limit = int64(tmp)
+		case "used":
+
+			var tmp uint64
+			tmp, v, err = cbor.ReadUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			if tmp > math.MaxInt {
+				return b, cbor.UintOverflow{Value: tmp, FailedBitsize: 64}
+			}
+			x.Used = int(tmp)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Quota) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Profile) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("email")+cbor.StringPrefixSize+len(x.Email))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("nick")+cbor.StringPrefixSize+len(x.Nick))
+	return
+}
+
+// MarshalCBOR encodes every field of Profile, as EncodeVersion does
+// for the latest schema version.
+func (x *Profile) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.EncodeVersion(b, math.MaxInt)
+}
+
+// EncodeVersion encodes Profile for readers of schema version v,
+// leaving out fields tagged with a later cbor:",version=N".
+func (x *Profile) EncodeVersion(b []byte, v int) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if v >= 2 {
+		count++
+	}
+	if v >= 3 && x.Nick != "" {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	if v >= 2 {
+		b = cbor.AppendString(b, "email")
+		b = cbor.AppendString(b, x.Email)
+	}
+	if v >= 3 && x.Nick != "" {
+		b = cbor.AppendString(b, "nick")
+		b = cbor.AppendString(b, x.Nick)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Profile) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "email":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Email = tmp
+		case "nick":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Nick = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Profile) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "email":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Email = cbor.UnsafeString(tmpBytes)
+		case "nick":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Nick = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Profile) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x SeqTable) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("seqs")+cbor.TypedArrayPrefixSize+8*len(x.Seqs))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("sizes")+cbor.TypedArrayPrefixSize+4*len(x.Sizes))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ports")+cbor.TypedArrayPrefixSize+2*len(x.Ports))
+	return
+}
+
+func (x *SeqTable) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(2)
+	if len(x.Sizes) != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "seqs")
+	b = cbor.AppendTypedArrayUint64(b, x.Seqs)
+	if len(x.Sizes) != 0 {
+		b = cbor.AppendString(b, "sizes")
+		b = cbor.AppendTypedArrayUint32(b, x.Sizes)
+	}
+	b = cbor.AppendString(b, "ports")
+	b = cbor.AppendTypedArrayUint16(b, x.Ports)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *SeqTable) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "seqs":
+
+			var tmp []uint64
+			tmp, v, err = cbor.ReadTypedArrayUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seqs = tmp
+		case "sizes":
+
+			var tmp []uint32
+			tmp, v, err = cbor.ReadTypedArrayUint32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Sizes = tmp
+		case "ports":
+
+			var tmp []uint16
+			tmp, v, err = cbor.ReadTypedArrayUint16Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Ports = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *SeqTable) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "seqs":
+
+			var tmp []uint64
+			tmp, v, err = cbor.ReadTypedArrayUint64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Seqs = tmp
+		case "sizes":
+
+			var tmp []uint32
+			tmp, v, err = cbor.ReadTypedArrayUint32Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Sizes = tmp
+		case "ports":
+
+			var tmp []uint16
+			tmp, v, err = cbor.ReadTypedArrayUint16Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Ports = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *SeqTable) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Sample) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("sensor")+cbor.StringPrefixSize+len(x.Sensor))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("value")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("labels")+cbor.MapHeaderSize+len(x.Labels)*(cbor.StringPrefixSize+cbor.StringPrefixSize)+cbor.MapKeysLen(x.Labels)+cbor.MapValuesLen(x.Labels))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("note")+cbor.StringPrefixSize+len(x.Note))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("unit")+cbor.StringPrefixSize+len(x.Unit))
+	return
+}
+
+func (x *Sample) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(3)
+	if x.Value != 0 {
+		count++
+	}
+	if len(x.Labels) != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendUint64(b, 0)
+	b = cbor.AppendString(b, x.Sensor)
+	if x.Value != 0 {
+		b = cbor.AppendUint64(b, 1)
+		b = cbor.AppendInt64(b, x.Value)
+	}
+	if len(x.Labels) != 0 {
+		b = cbor.AppendUint64(b, 2)
+
+		b = cbor.AppendMapHeader(b, uint32(len(x.Labels)))
+		for k, v := range x.Labels {
+			b = cbor.AppendString(b, k)
+			b = cbor.AppendString(b, v)
+		}
+	}
+	b = cbor.AppendUint64(b, 3)
+
+	if x.Note != "" {
+		b = cbor.AppendString(b, x.Note)
+	} else {
+		b = cbor.AppendNil(b)
+	}
+	b = cbor.AppendUint64(b, 10)
+	b = cbor.AppendString(b, x.Unit)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Sample) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadUint64Bytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case 0:
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Sensor = tmp
+		case 1:
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Value = tmp
+		case 2:
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Labels == nil && sz > 0 {
+				x.Labels = make(map[string]string, sz)
+			} else if x.Labels != nil {
+				clear(x.Labels)
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[key] = tmp
+			}
+		case 3:
+
+			if cbor.IsNil(v) {
+				var zero string
+				x.Note = zero
+				v = v[1:]
+			} else {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Note = tmp
+			}
+		case 10:
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Unit = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Sample) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadUint64Bytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case 0:
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Sensor = cbor.UnsafeString(tmpBytes)
+		case 1:
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Value = tmp
+		case 2:
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Labels == nil && sz > 0 {
+				x.Labels = make(map[string]string, sz)
+			} else if x.Labels != nil {
+				clear(x.Labels)
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[key] = tmp
+			}
+		case 3:
+
+			if cbor.IsNil(v) {
+				var zero string
+				x.Note = zero
+				v = v[1:]
+			} else {
+				var tmpBytes []byte
+				tmpBytes, v, err = cbor.ReadStringZC(v)
+				if err != nil {
+					return b, err
+				}
+				x.Note = cbor.UnsafeString(tmpBytes)
+			}
+		case 10:
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Unit = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Sample) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Endpoint) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("url")+cbor.StringPrefixSize+len(x.URL))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("host")+cbor.StringPrefixSize+len(x.Host))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("port")+cbor.IntSize)
+	return
+}
+
+func (x *Endpoint) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if x.Host != "" {
+		count++
+	}
+	if x.Port != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "url")
+	b = cbor.AppendString(b, x.URL)
+	// Deprecated: use URL instead
+	if x.Host != "" {
+		b = cbor.AppendString(b, "host")
+		b = cbor.AppendString(b, x.Host)
+	}
+	// Deprecated: kept for backward compatibility only
+	if x.Port != 0 {
+		b = cbor.AppendString(b, "port")
+		b = cbor.AppendInt(b, x.Port)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Endpoint) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "url":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.URL = tmp
+		// Deprecated: use URL instead
+		case "host":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Host = tmp
+		// Deprecated: kept for backward compatibility only
+		case "port":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Port = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Endpoint) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "url":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.URL = cbor.UnsafeString(tmpBytes)
+		// Deprecated: use URL instead
+		case "host":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Host = cbor.UnsafeString(tmpBytes)
+		// Deprecated: kept for backward compatibility only
+		case "port":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Port = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Endpoint) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Relayed) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.StringPrefixSize+len(x.ID))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("kind")+cbor.StringPrefixSize+len(x.Kind))
+	s = cbor.AddSize(s, len(x.Rest))
+	return
+}
+
+func (x *Relayed) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	overflowN, overflow, overflowErr := cbor.MapEntriesBytes(x.Rest)
+	if overflowErr != nil {
+		return b, overflowErr
+	}
+
+	count := uint32(1)
+	if x.Kind != "" {
+		count++
+	}
+	count += overflowN
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "id")
+	b = cbor.AppendString(b, x.ID)
+	if x.Kind != "" {
+		b = cbor.AppendString(b, "kind")
+		b = cbor.AppendString(b, x.Kind)
+	}
+
+	b = append(b, overflow...)
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Relayed) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	var overflow []byte
+	var overflowN uint32
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "id":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = tmp
+		case "kind":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Kind = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+			overflow = append(overflow, rest[:len(rest)-len(v)]...)
+			overflowN++
+		}
+		rest = v
+	}
+	x.Rest = nil
+	if overflowN > 0 {
+		x.Rest = append(cbor.AppendMapHeader(make([]byte, 0, cbor.MapHeaderSize+len(overflow)), overflowN), overflow...)
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Relayed) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	var overflow []byte
+	var overflowN uint32
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "id":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.ID = cbor.UnsafeString(tmpBytes)
+		case "kind":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Kind = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+			overflow = append(overflow, rest[:len(rest)-len(v)]...)
+			overflowN++
+		}
+		rest = v
+	}
+	x.Rest = nil
+	if overflowN > 0 {
+		x.Rest = append(cbor.AppendMapHeader(make([]byte, 0, cbor.MapHeaderSize+len(overflow)), overflowN), overflow...)
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Relayed) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x ConsumerSpec) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("stream")+cbor.StringPrefixSize+len(x.Stream))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("durable")+cbor.StringPrefixSize+len(x.Durable))
+	return
+}
+
+func (x *ConsumerSpec) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	b = cbor.AppendString(b, "stream")
+	b = cbor.AppendString(b, x.Stream)
+	b = cbor.AppendString(b, "durable")
+	b = cbor.AppendString(b, x.Durable)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *ConsumerSpec) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "stream":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Stream = tmp
+		case "durable":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Durable = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *ConsumerSpec) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "stream":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Stream = cbor.UnsafeString(tmpBytes)
+		case "durable":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Durable = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *ConsumerSpec) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x StreamSpec) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("stream")+cbor.StringPrefixSize+len(x.Stream))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("subjects")+cbor.ArrayHeaderSize+len(x.Subjects)*cbor.StringPrefixSize+cbor.StringsLen(x.Subjects))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("replicas")+cbor.IntSize)
+	return
+}
+
+func (x *StreamSpec) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if len(x.Subjects) != 0 {
+		count++
+	}
+	if x.Replicas != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "stream")
+	b = cbor.AppendString(b, x.Stream)
+	if len(x.Subjects) != 0 {
+
+		b = cbor.AppendString(b, "subjects")
+		b = cbor.AppendArrayHeader(b, uint32(len(x.Subjects)))
+		for _, v := range x.Subjects {
+			b = cbor.AppendString(b, v)
+		}
+	}
+	if x.Replicas != 0 {
+		b = cbor.AppendString(b, "replicas")
+		b = cbor.AppendInt(b, x.Replicas)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *StreamSpec) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "stream":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Stream = tmp
+		case "subjects":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Subjects) >= int(sz) {
+				x.Subjects = x.Subjects[:sz]
+			} else {
+				x.Subjects = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Subjects[sz-1]
+			}
+			for iSubjects := uint32(0); iSubjects < sz; iSubjects++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Subjects[iSubjects] = tmp
+			}
+		case "replicas":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Replicas = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *StreamSpec) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "stream":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Stream = cbor.UnsafeString(tmpBytes)
+		case "subjects":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Subjects) >= int(sz) {
+				x.Subjects = x.Subjects[:sz]
+			} else {
+				x.Subjects = make([]string, sz)
+			}
+			if sz > 0 {
+				_ = x.Subjects[sz-1]
+			}
+			for iSubjects := uint32(0); iSubjects < sz; iSubjects++ {
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Subjects[iSubjects] = tmp
+			}
+		case "replicas":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Replicas = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *StreamSpec) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Command) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("op")+cbor.StringPrefixSize+len(x.Op))
+	return
+}
+
+func (x *Command) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "op")
+	b = cbor.AppendString(b, x.Op)
+
+	b = cbor.AppendString(b, "spec")
+	switch v := x.Spec.(type) {
+	case nil:
+		b = cbor.AppendNil(b)
+	case *ConsumerSpec:
+		b, err = v.MarshalCBOR(b)
+	case ConsumerSpec:
+		b, err = v.MarshalCBOR(b)
+	case *StreamSpec:
+		b, err = v.MarshalCBOR(b)
+	case StreamSpec:
+		b, err = v.MarshalCBOR(b)
+	default:
+		err = cbor.ErrUnknownVariant
+	}
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Command) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "op":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Op = tmp
+		case "spec":
+
+			if cbor.IsNil(v) {
+				x.Spec = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var which int
+				which, err = cbor.MatchMapKeys(v, []string{"durable"}, []string{"subjects", "replicas"})
+				if err != nil {
+					return b, err
+				}
+				switch which {
+				case 0:
+					tmp := new(ConsumerSpec)
+					v, err = tmp.UnmarshalCBOR(v)
+					if err != nil {
+						return b, err
+					}
+					x.Spec = tmp
+				case 1:
+					tmp := new(StreamSpec)
+					v, err = tmp.UnmarshalCBOR(v)
+					if err != nil {
+						return b, err
+					}
+					x.Spec = tmp
+				default:
+					return b, cbor.ErrUnknownVariant
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Command) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "op":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Op = cbor.UnsafeString(tmpBytes)
+		case "spec":
+
+			if cbor.IsNil(v) {
+				x.Spec = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var which int
+				which, err = cbor.MatchMapKeys(v, []string{"durable"}, []string{"subjects", "replicas"})
+				if err != nil {
+					return b, err
+				}
+				switch which {
+				case 0:
+					tmp := new(ConsumerSpec)
+					v, err = tmp.DecodeTrusted(v)
+					if err != nil {
+						return b, err
+					}
+					x.Spec = tmp
+				case 1:
+					tmp := new(StreamSpec)
+					v, err = tmp.DecodeTrusted(v)
+					if err != nil {
+						return b, err
+					}
+					x.Spec = tmp
+				default:
+					return b, cbor.ErrUnknownVariant
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Command) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x *Guarded) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("hits")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("owner")+cbor.StringPrefixSize+len(x.Owner))
+	return
+}
+
+func (x *Guarded) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if x.Owner != "" {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "hits")
+	b = cbor.AppendInt64(b, x.Hits)
+	if x.Owner != "" {
+		b = cbor.AppendString(b, "owner")
+		b = cbor.AppendString(b, x.Owner)
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Guarded) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "hits":
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Hits = tmp
+		case "owner":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Owner = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Guarded) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "hits":
+
+			var tmp int64
+			tmp, v, err = cbor.ReadInt64Bytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Hits = tmp
+		case "owner":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Owner = cbor.UnsafeString(tmpBytes)
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Guarded) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Pool) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("members")+cbor.ArrayHeaderSize+len(x.Members)*0)
+	return
+}
+
+func (x *Pool) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	var err error
+	b = cbor.AppendString(b, "primary")
+	b, err = cbor.AppendPtrMarshaler(b, x.Primary)
+	if err != nil {
+		return b, err
+	}
+
+	b = cbor.AppendString(b, "members")
+	b = cbor.AppendArrayHeader(b, uint32(len(x.Members)))
+	for i := range x.Members {
+		b, err = x.Members[i].MarshalCBOR(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Pool) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "primary":
+
+			if x.Primary == nil {
+				x.Primary = new(Guarded)
+			}
+			v, err = x.Primary.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "members":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Members) >= int(sz) {
+				x.Members = x.Members[:sz]
+			} else {
+				x.Members = make([]Guarded, sz)
+			}
+			for iMembers := uint32(0); iMembers < sz; iMembers++ {
+				x.Members[iMembers] = Guarded{}
+				v, err = x.Members[iMembers].UnmarshalCBOR(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Pool) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "primary":
+
+			if x.Primary == nil {
+				x.Primary = new(Guarded)
+			}
+			v, err = x.Primary.DecodeTrusted(v)
+			if err != nil {
+				return b, err
+			}
+		case "members":
+
+			var sz uint32
+			sz, v, err = cbor.ReadArrayHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if cap(x.Members) >= int(sz) {
+				x.Members = x.Members[:sz]
+			} else {
+				x.Members = make([]Guarded, sz)
+			}
+			for iMembers := uint32(0); iMembers < sz; iMembers++ {
+				x.Members[iMembers] = Guarded{}
+				v, err = x.Members[iMembers].DecodeTrusted(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Pool) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Webhook) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "event":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Event = tmp
+		case "labels":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Labels == nil && sz > 0 {
+				x.Labels = make(map[string]string, sz)
+			} else if x.Labels != nil {
+				clear(x.Labels)
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Webhook) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "event":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Event = cbor.UnsafeString(tmpBytes)
+		case "labels":
+
+			var sz uint32
+			sz, v, err = cbor.ReadMapHeaderBytes(v)
+			if err != nil {
+				return b, err
+			}
+			if x.Labels == nil && sz > 0 {
+				x.Labels = make(map[string]string, sz)
+			} else if x.Labels != nil {
+				clear(x.Labels)
+			}
+			for iLabels := uint32(0); iLabels < sz; iLabels++ {
+				var key string
+				key, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var tmp string
+				tmp, v, err = cbor.ReadStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				x.Labels[key] = tmp
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Webhook) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x AuditRecord) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("actor")+cbor.StringPrefixSize+len(x.Actor))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("action")+cbor.StringPrefixSize+len(x.Action))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("seq")+cbor.Uint64Size)
+	return
+}
+
+func (x *AuditRecord) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(2)
+	if x.Seq != 0 {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	b = cbor.AppendString(b, "actor")
+	b = cbor.AppendString(b, x.Actor)
+	b = cbor.AppendString(b, "action")
+	b = cbor.AppendString(b, x.Action)
+	if x.Seq != 0 {
+		b = cbor.AppendString(b, "seq")
+		b = cbor.AppendUint64(b, x.Seq)
+	}
+
+	return b, nil
+}
+
+func (x Stamp) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("by")+cbor.StringPrefixSize+len(x.By))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("at")+cbor.Uint64Size)
+	return
+}
+
+func (x *Stamp) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendArrayHeader(b, 2)
+	b = cbor.AppendString(b, x.By)
+	b = cbor.AppendUint64(b, x.At)
+
+	return b, nil
+}
+
+func (x Signed) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("body")+cbor.StringPrefixSize+len(x.Body))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("stamp")+x.Stamp.Msgsize())
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("cosign")+cbor.PtrMsgsize(x.Cosign))
+	return
+}
+
+func (x *Signed) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(2)
+	if x.Cosign != nil {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+	var err error
+	b = cbor.AppendString(b, "body")
+	b = cbor.AppendString(b, x.Body)
+	b = cbor.AppendString(b, "stamp")
+	b, err = x.Stamp.MarshalCBOR(b)
+	if err != nil {
+		return b, err
+	}
+	if x.Cosign != nil {
+		b = cbor.AppendString(b, "cosign")
+		b, err = cbor.AppendPtrMarshaler(b, x.Cosign)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Signed) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "body":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Body = tmp
+		case "stamp":
+
+			v, err = x.Stamp.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "cosign":
+
+			if x.Cosign == nil {
+				x.Cosign = new(Stamp)
+			}
+			v, err = x.Cosign.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Signed) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "body":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Body = cbor.UnsafeString(tmpBytes)
+		case "stamp":
+
+			v, err = x.Stamp.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		case "cosign":
+
+			if x.Cosign == nil {
+				x.Cosign = new(Stamp)
+			}
+			v, err = x.Cosign.UnmarshalCBOR(v)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Signed) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// Compile-time checks for Signed fields encoded through their
+// type's own MarshalCBOR/UnmarshalCBOR methods.
+var (
+	_ cbor.Marshaler   = (*Stamp)(nil) // Stamp
+	_ cbor.Unmarshaler = (*Stamp)(nil) // Stamp
+	_ cbor.Marshaler   = (*Stamp)(nil) // Cosign
+	_ cbor.Unmarshaler = (*Stamp)(nil) // Cosign
+)
+
+func (x *Link) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.AppendMapHeader(b, uint32(2))
+	b = cbor.AppendString(b, "href")
+	b = cbor.AppendURL(b, &x.Href)
+	b = cbor.AppendString(b, "next")
+	b = cbor.AppendURL(b, x.Next)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Link) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "href":
+
+			var s string
+			s, v, err = cbor.ReadURIStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			var u *url.URL
+			u, err = url.Parse(s)
+			if err != nil {
+				return b, err
+			}
+			x.Href = *u
+		case "next":
+
+			if cbor.IsNil(v) {
+				x.Next = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var s string
+				s, v, err = cbor.ReadURIStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var u *url.URL
+				u, err = url.Parse(s)
+				if err != nil {
+					return b, err
+				}
+				x.Next = u
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Link) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "href":
+
+			var s string
+			s, v, err = cbor.ReadURIStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			var u *url.URL
+			u, err = url.Parse(s)
+			if err != nil {
+				return b, err
+			}
+			x.Href = *u
+		case "next":
+
+			if cbor.IsNil(v) {
+				x.Next = nil
+				v, err = cbor.ReadNilBytes(v)
+				if err != nil {
+					return b, err
+				}
+			} else {
+				var s string
+				s, v, err = cbor.ReadURIStringBytes(v)
+				if err != nil {
+					return b, err
+				}
+				var u *url.URL
+				u, err = url.Parse(s)
+				if err != nil {
+					return b, err
+				}
+				x.Next = u
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Link) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Timestamps) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("default")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("epoch")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("text")+cbor.RFC3339TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("millis")+cbor.Int64Size)
+	return
+}
+
+func (x *Timestamps) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, uint32(4))
+	b = cbor.AppendString(b, "default")
+	b = cbor.AppendTime(b, x.Default)
+	b = cbor.AppendString(b, "epoch")
+	b = cbor.AppendTime(b, x.Epoch)
+	b = cbor.AppendString(b, "text")
+	b = cbor.AppendRFC3339Time(b, x.Text)
+	b = cbor.AppendString(b, "millis")
+	b = cbor.AppendUnixMilli(b, x.Millis)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Timestamps) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "default":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Default = tmp
+		case "epoch":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Epoch = tmp
+		case "text":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadRFC3339TimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Text = tmp
+		case "millis":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadUnixMilliBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Millis = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Timestamps) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "default":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Default = tmp
+		case "epoch":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadTimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Epoch = tmp
+		case "text":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadRFC3339TimeBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Text = tmp
+		case "millis":
+
+			var tmp time.Time
+			tmp, v, err = cbor.ReadUnixMilliBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Millis = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Timestamps) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
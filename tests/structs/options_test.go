@@ -0,0 +1,1147 @@
+package structs
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synadia-labs/cbor.go/cborgen/core"
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+type optionsDecoder struct {
+	name   string
+	decode func(dst *Options, b []byte) ([]byte, error)
+}
+
+var optionsDecoders = []optionsDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Options).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Options).DecodeTrusted,
+	},
+}
+
+func TestOptionsUintRoundTrip(t *testing.T) {
+	orig := &Options{Seq: 1 << 40, Count: 7, Small: 100, Timeout: time.Second}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+
+	// Every ",uint" field must be encoded with the unsigned major type.
+	_, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		t.Fatalf("ReadMapHeaderBytes error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		var key string
+		key, rest, err = cbor.ReadStringBytes(rest)
+		if err != nil {
+			t.Fatalf("key %d: %v", i, err)
+		}
+		if typ := cbor.NextType(rest); typ != cbor.UintType {
+			t.Fatalf("field %q encoded as %s, want uint", key, typ)
+		}
+		rest, err = cbor.Skip(rest)
+		if err != nil {
+			t.Fatalf("skip %q: %v", key, err)
+		}
+	}
+
+	for _, tc := range optionsDecoders {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Options
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst != *orig {
+				t.Fatalf("%s mismatch: got %+v, want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+}
+
+func TestOptionsUintOverflow(t *testing.T) {
+	// {"small": 200} does not fit into int8.
+	b := cbor.AppendMapHeader(nil, 1)
+	b = cbor.AppendString(b, "small")
+	b = cbor.AppendUint64(b, 200)
+
+	for _, tc := range optionsDecoders {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Options
+			_, err := tc.decode(&dst, b)
+			var ovf cbor.UintOverflow
+			if !errors.As(err, &ovf) || ovf.FailedBitsize != 8 {
+				t.Fatalf("%s: expected UintOverflow for int8, got %v", tc.name, err)
+			}
+		})
+	}
+}
+
+func TestOptionsDurationString(t *testing.T) {
+	orig := &Options{Timeout: 90*time.Minute + 500*time.Millisecond}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `"timeout": "1h30m0.5s"`; !strings.Contains(diag, want) {
+		t.Fatalf("diag %s does not contain %s", diag, want)
+	}
+
+	for _, tc := range optionsDecoders {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Options
+			if _, err := tc.decode(&dst, b); err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if dst.Timeout != orig.Timeout {
+				t.Fatalf("%s Timeout = %v, want %v", tc.name, dst.Timeout, orig.Timeout)
+			}
+		})
+	}
+
+	// Unparseable durations are rejected.
+	bad := cbor.AppendMapHeader(nil, 1)
+	bad = cbor.AppendString(bad, "timeout")
+	bad = cbor.AppendString(bad, "soon")
+	for _, tc := range optionsDecoders {
+		var dst Options
+		if _, err := tc.decode(&dst, bad); err == nil {
+			t.Fatalf("%s: expected error for invalid duration", tc.name)
+		}
+	}
+}
+
+func TestOptionsIntString(t *testing.T) {
+	orig := &Options{ID: -9007199254740993, Port: 4222}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	for _, want := range []string{`"id": "-9007199254740993"`, `"port": "4222"`} {
+		if !strings.Contains(diag, want) {
+			t.Fatalf("diag %s does not contain %s", diag, want)
+		}
+	}
+
+	for _, tc := range optionsDecoders {
+		var dst Options
+		if _, err := tc.decode(&dst, b); err != nil {
+			t.Fatalf("%s error: %v", tc.name, err)
+		}
+		if dst.ID != orig.ID || dst.Port != orig.Port {
+			t.Fatalf("%s = %+v, want ID %d Port %d", tc.name, dst, orig.ID, orig.Port)
+		}
+	}
+
+	// Text that is not a number, or overflows the field, is rejected.
+	for _, bad := range [][2]string{{"id", "12a"}, {"port", "65536"}, {"port", "-1"}} {
+		msg := cbor.AppendMapHeader(nil, 1)
+		msg = cbor.AppendString(msg, bad[0])
+		msg = cbor.AppendString(msg, bad[1])
+		for _, tc := range optionsDecoders {
+			var dst Options
+			if _, err := tc.decode(&dst, msg); err == nil {
+				t.Fatalf("%s: expected error for %s=%q", tc.name, bad[0], bad[1])
+			}
+		}
+	}
+}
+
+func TestFlowEncodesEmptyFields(t *testing.T) {
+	b, err := (&Flow{}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"name": "", "count": 0, "tags": []}`; diag != want {
+		t.Fatalf("diag mismatch: got %s want %s", diag, want)
+	}
+
+	orig := Flow{Name: "n", Count: 3, Tags: []string{"a"}}
+	b, err = orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Flow
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst.Name != orig.Name || dst.Count != orig.Count || len(dst.Tags) != 1 || dst.Tags[0] != "a" {
+		t.Fatalf("round trip mismatch: got %+v want %+v", dst, orig)
+	}
+}
+
+type envelopeDecoder struct {
+	name   string
+	decode func(dst *Envelope, b []byte) ([]byte, error)
+}
+
+var envelopeDecoders = []envelopeDecoder{
+	{
+		name:   "DecodeSafe",
+		decode: (*Envelope).DecodeSafe,
+	},
+	{
+		name:   "DecodeTrusted",
+		decode: (*Envelope).DecodeTrusted,
+	},
+}
+
+func TestEnvelopeRawCBOR(t *testing.T) {
+	payload := cbor.AppendMapHeader(nil, 1)
+	payload = cbor.AppendString(payload, "durable")
+	payload = cbor.AppendBool(payload, true)
+	orig := &Envelope{Kind: "consumer", Payload: payload}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `"payload": {"durable": true}`; !strings.Contains(diag, want) {
+		t.Fatalf("diag %s does not contain %s", diag, want)
+	}
+
+	for _, tc := range envelopeDecoders {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var dst Envelope
+			rest, err := tc.decode(&dst, b)
+			if err != nil {
+				t.Fatalf("%s error: %v", tc.name, err)
+			}
+			if len(rest) != 0 {
+				t.Fatalf("%s leftover bytes: %d", tc.name, len(rest))
+			}
+			if dst.Kind != orig.Kind || string(dst.Payload) != string(orig.Payload) {
+				t.Fatalf("%s mismatch: got %+v, want %+v", tc.name, dst, *orig)
+			}
+		})
+	}
+
+	// An empty payload is written as null and decodes back to nil.
+	b, err = (&Envelope{Kind: "empty"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, tc := range envelopeDecoders {
+		dst := Envelope{Payload: []byte{0x01}}
+		if _, err := tc.decode(&dst, b); err != nil {
+			t.Fatalf("%s error: %v", tc.name, err)
+		}
+		if dst.Payload != nil {
+			t.Fatalf("%s: expected nil payload, got %x", tc.name, dst.Payload)
+		}
+	}
+}
+
+func TestEnvelopeRawCBORSafeCopies(t *testing.T) {
+	b, err := (&Envelope{Kind: "k", Payload: cbor.AppendUint64(nil, 7)}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Envelope
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	for i := range b {
+		b[i] = 0xff
+	}
+	if len(dst.Payload) != 1 || dst.Payload[0] != 0x07 {
+		t.Fatalf("DecodeSafe payload aliases input: %x", dst.Payload)
+	}
+}
+
+func TestExtendedFlatten(t *testing.T) {
+	extra := cbor.AppendString(nil, "retries")
+	extra = cbor.AppendUint64(extra, 3)
+	extra = cbor.AppendString(extra, "debug")
+	extra = cbor.AppendBool(extra, true)
+	b, err := (&Extended{Name: "svc", Extra: extra}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"name": "svc", "retries": 3, "debug": true}`; diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+
+	// The spliced entries are unknown keys on decode and are skipped.
+	for _, decode := range []func(*Extended, []byte) ([]byte, error){(*Extended).DecodeSafe, (*Extended).DecodeTrusted} {
+		var dst Extended
+		rest, err := decode(&dst, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 || dst.Name != "svc" || dst.Extra != nil {
+			t.Fatalf("unexpected decode result %+v (leftover %d)", dst, len(rest))
+		}
+	}
+
+	// A key without a value cannot be spliced into the map.
+	_, err = (&Extended{Name: "svc", Extra: cbor.AppendString(nil, "dangling")}).MarshalCBOR(nil)
+	if !errors.Is(err, cbor.ErrShortBytes) {
+		t.Fatalf("expected ErrShortBytes, got %v", err)
+	}
+}
+
+func TestDigestHex(t *testing.T) {
+	orig := &Digest{Algo: "sha1", Sum: []byte{0x0a, 0x1b, 0xff}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"algo": "sha1", "sum": "0a1bff"}`; diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Digest, []byte) ([]byte, error){(*Digest).DecodeSafe, (*Digest).DecodeTrusted} {
+		var dst Digest
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Algo != orig.Algo || string(dst.Sum) != string(orig.Sum) {
+			t.Fatalf("decode = %+v, want %+v", dst, orig)
+		}
+
+		bad := cbor.AppendMapHeader(nil, 1)
+		bad = cbor.AppendString(bad, "sum")
+		bad = cbor.AppendString(bad, "0a1")
+		if _, err := decode(&dst, bad); err == nil {
+			t.Fatalf("expected error for odd-length hex")
+		}
+	}
+}
+
+func TestTrackedUUID(t *testing.T) {
+	orig := &Tracked{
+		ID:   [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+		Name: "job",
+	}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `"id": 37(h'123e4567e89b12d3a456426614174000')`; !strings.Contains(diag, want) {
+		t.Fatalf("diag %s does not contain %s", diag, want)
+	}
+	if len(b) > orig.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), orig.Msgsize())
+	}
+
+	for _, decode := range []func(*Tracked, []byte) ([]byte, error){(*Tracked).DecodeSafe, (*Tracked).DecodeTrusted} {
+		var dst Tracked
+		rest, err := decode(&dst, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 || dst != *orig {
+			t.Fatalf("got %+v (leftover %d), want %+v", dst, len(rest), *orig)
+		}
+	}
+
+	// A plain byte string is not a UUID.
+	bad := cbor.AppendMapHeader(nil, 1)
+	bad = cbor.AppendString(bad, "id")
+	bad = cbor.AppendBytes(bad, orig.ID[:])
+	var dst Tracked
+	if _, err := dst.DecodeSafe(bad); err == nil {
+		t.Fatalf("expected error decoding untagged id")
+	}
+}
+
+func TestPointToArray(t *testing.T) {
+	orig := &Point{X: 3, Y: -4, Tags: []string{"a"}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	// Positional encoding ignores omitempty.
+	if want := `[3, -4, "", ["a"]]`; diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+
+	// Decoding accepts the positional form and the keyed map form.
+	keyed := cbor.AppendMapHeader(nil, 3)
+	keyed = cbor.AppendString(keyed, "y")
+	keyed = cbor.AppendInt64(keyed, -4)
+	keyed = cbor.AppendString(keyed, "x")
+	keyed = cbor.AppendInt64(keyed, 3)
+	keyed = cbor.AppendString(keyed, "tags")
+	keyed = cbor.AppendArrayHeader(keyed, 1)
+	keyed = cbor.AppendString(keyed, "a")
+	for _, msg := range [][]byte{b, keyed} {
+		for _, decode := range []func(*Point, []byte) ([]byte, error){(*Point).DecodeSafe, (*Point).DecodeTrusted} {
+			var dst Point
+			rest, err := decode(&dst, msg)
+			if err != nil {
+				t.Fatalf("decode(%x) error: %v", msg, err)
+			}
+			if len(rest) != 0 || dst.X != 3 || dst.Y != -4 || dst.Label != "" || len(dst.Tags) != 1 || dst.Tags[0] != "a" {
+				t.Fatalf("decode(%x) = %+v (leftover %d)", msg, dst, len(rest))
+			}
+		}
+	}
+
+	// Extra trailing elements are skipped.
+	extra := append(cbor.AppendArrayHeader(nil, 5), b[1:]...)
+	extra = cbor.AppendBool(extra, true)
+	var dst Point
+	if rest, err := dst.DecodeSafe(extra); err != nil || len(rest) != 0 || dst.X != 3 {
+		t.Fatalf("decode with extra element: %+v, %v", dst, err)
+	}
+}
+
+func TestLooseNoCase(t *testing.T) {
+	for _, keys := range [][2]string{{"name", "count"}, {"Name", "COUNT"}, {"nAmE", "Count"}} {
+		msg := cbor.AppendMapHeader(nil, 3)
+		msg = cbor.AppendString(msg, keys[0])
+		msg = cbor.AppendString(msg, "x")
+		msg = cbor.AppendString(msg, keys[1])
+		msg = cbor.AppendInt(msg, 7)
+		msg = cbor.AppendString(msg, "other")
+		msg = cbor.AppendBool(msg, true)
+		for _, decode := range []func(*Loose, []byte) ([]byte, error){(*Loose).DecodeSafe, (*Loose).DecodeTrusted} {
+			var dst Loose
+			rest, err := decode(&dst, msg)
+			if err != nil {
+				t.Fatalf("%v: decode error: %v", keys, err)
+			}
+			if len(rest) != 0 || dst.Name != "x" || dst.Count != 7 {
+				t.Fatalf("%v: decode = %+v (leftover %d)", keys, dst, len(rest))
+			}
+		}
+	}
+
+	// Encoding still uses the declared names.
+	b, err := (&Loose{Name: "x"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"name": "x"}`; diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+}
+
+func TestBoundedValidate(t *testing.T) {
+	valid := Bounded{Percent: 50, Ratio: 0.5, Name: "n", Owner: "o"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate(valid) = %v", err)
+	}
+	cases := []struct {
+		mutate func(*Bounded)
+		field  string
+		rule   string
+	}{
+		{func(b *Bounded) { b.Percent = -1 }, "percent", "min=0"},
+		{func(b *Bounded) { b.Percent = 101 }, "percent", "max=100"},
+		{func(b *Bounded) { b.Ratio = 1.5 }, "ratio", "max=1"},
+		{func(b *Bounded) { b.Name = "" }, "name", "minlen=1"},
+		{func(b *Bounded) { b.Tags = []string{"a", "b", "c", "d"} }, "tags", "maxlen=3"},
+		{func(b *Bounded) { b.Owner = "" }, "owner", "required"},
+	}
+	for _, tc := range cases {
+		b := valid
+		tc.mutate(&b)
+		err := b.Validate()
+		var verr cbor.ValidationError
+		if !errors.As(err, &verr) || verr.Field != tc.field || verr.Rule != tc.rule {
+			t.Fatalf("Validate() = %v, want %s violating %s", err, tc.field, tc.rule)
+		}
+	}
+}
+
+func TestExplicitNull(t *testing.T) {
+	cases := []struct {
+		in   Explicit
+		diag string
+	}{
+		{Explicit{}, `{"name": null, "count": null, "tags": null, "timeout": null}`},
+		{Explicit{Name: "a", Count: 2, Tags: []string{"x"}, Timeout: time.Second}, `{"name": "a", "count": 2, "tags": ["x"], "timeout": "1s"}`},
+	}
+	for _, tc := range cases {
+		b, err := tc.in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		diag, _, err := cbor.DiagBytes(b)
+		if err != nil {
+			t.Fatalf("DiagBytes error: %v", err)
+		}
+		if diag != tc.diag {
+			t.Fatalf("diag %s, want %s", diag, tc.diag)
+		}
+		for _, decode := range []func(*Explicit, []byte) ([]byte, error){(*Explicit).DecodeSafe, (*Explicit).DecodeTrusted} {
+			dst := Explicit{Name: "stale", Count: 9, Tags: []string{"stale"}, Timeout: time.Hour}
+			if _, err := decode(&dst, b); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if dst.Name != tc.in.Name || dst.Count != tc.in.Count || len(dst.Tags) != len(tc.in.Tags) || dst.Timeout != tc.in.Timeout {
+				t.Fatalf("decode = %+v, want %+v", dst, tc.in)
+			}
+		}
+	}
+}
+
+func TestQuotaUnsigned(t *testing.T) {
+	orig := &Quota{Limit: 10, Used: 3}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, decode := range []func(*Quota, []byte) ([]byte, error){(*Quota).DecodeSafe, (*Quota).DecodeTrusted} {
+		var dst Quota
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst != *orig {
+			t.Fatalf("decode = %+v, want %+v", dst, *orig)
+		}
+
+		bad := cbor.AppendMapHeader(nil, 1)
+		bad = cbor.AppendString(bad, "used")
+		bad = cbor.AppendInt64(bad, -1)
+		if _, err := decode(&dst, bad); err == nil {
+			t.Fatalf("expected error for negative value")
+		}
+	}
+
+	if _, err := (&Quota{Used: -1}).MarshalCBOR(nil); !errors.Is(err, cbor.ErrNegativeUnsigned) {
+		t.Fatalf("MarshalCBOR error = %v, want ErrNegativeUnsigned", err)
+	}
+}
+
+func TestProfileEncodeVersion(t *testing.T) {
+	p := &Profile{Name: "a", Email: "a@example.com", Nick: "ace"}
+	cases := []struct {
+		version int
+		diag    string
+	}{
+		{1, `{"name": "a"}`},
+		{2, `{"name": "a", "email": "a@example.com"}`},
+		{3, `{"name": "a", "email": "a@example.com", "nick": "ace"}`},
+	}
+	for _, tc := range cases {
+		b, err := p.EncodeVersion(nil, tc.version)
+		if err != nil {
+			t.Fatalf("EncodeVersion(%d) error: %v", tc.version, err)
+		}
+		diag, _, err := cbor.DiagBytes(b)
+		if err != nil {
+			t.Fatalf("DiagBytes error: %v", err)
+		}
+		if diag != tc.diag {
+			t.Fatalf("EncodeVersion(%d) diag %s, want %s", tc.version, diag, tc.diag)
+		}
+	}
+
+	b, err := (&Profile{Name: "a", Email: "e"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Profile
+	if _, err := dst.DecodeSafe(b); err != nil {
+		t.Fatalf("DecodeSafe error: %v", err)
+	}
+	if dst != (Profile{Name: "a", Email: "e"}) {
+		t.Fatalf("decode = %+v", dst)
+	}
+}
+
+func TestSeqTableCompact(t *testing.T) {
+	orig := &SeqTable{Seqs: []uint64{1, 2}, Ports: []uint16{4222}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > orig.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), orig.Msgsize())
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	want := `{"seqs": 67(h'00000000000000010000000000000002'), "ports": 65(h'107e')}`
+	if diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+
+	// A peer without typed array support writes plain arrays.
+	plain := cbor.AppendMapHeader(nil, 2)
+	plain = cbor.AppendString(plain, "seqs")
+	plain = cbor.AppendArrayHeader(plain, 2)
+	plain = cbor.AppendUint64(plain, 1)
+	plain = cbor.AppendUint64(plain, 2)
+	plain = cbor.AppendString(plain, "ports")
+	plain = cbor.AppendArrayHeader(plain, 1)
+	plain = cbor.AppendUint16(plain, 4222)
+
+	for _, decode := range []func(*SeqTable, []byte) ([]byte, error){(*SeqTable).DecodeSafe, (*SeqTable).DecodeTrusted} {
+		for _, in := range [][]byte{b, plain} {
+			var dst SeqTable
+			if _, err := decode(&dst, in); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if len(dst.Seqs) != 2 || dst.Seqs[1] != 2 || len(dst.Sizes) != 0 || len(dst.Ports) != 1 || dst.Ports[0] != 4222 {
+				t.Fatalf("decode = %+v", dst)
+			}
+		}
+	}
+}
+
+func TestScratchIgnored(t *testing.T) {
+	if _, ok := any(&Scratch{}).(cbor.Marshaler); ok {
+		t.Fatalf("Scratch has generated MarshalCBOR despite cbor:\",ignore\"")
+	}
+	if _, ok := any(&Scratch{}).(cbor.Unmarshaler); ok {
+		t.Fatalf("Scratch has generated UnmarshalCBOR despite cbor:\",ignore\"")
+	}
+}
+
+func TestReadingIntKeys(t *testing.T) {
+	orig := &Sample{Sensor: "t1", Labels: map[string]string{"room": "a"}, Unit: "C"}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > orig.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), orig.Msgsize())
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	want := `{0: "t1", 2: {"room": "a"}, 3: null, 10: "C"}`
+	if diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+
+	for _, decode := range []func(*Sample, []byte) ([]byte, error){(*Sample).DecodeSafe, (*Sample).DecodeTrusted} {
+		var dst Sample
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Sensor != "t1" || dst.Labels["room"] != "a" || dst.Unit != "C" {
+			t.Fatalf("decode = %+v", dst)
+		}
+
+		// Unknown integer keys are skipped; string keys are rejected.
+		in := cbor.AppendMapHeader(nil, 2)
+		in = cbor.AppendUint64(in, 7)
+		in = cbor.AppendBool(in, true)
+		in = cbor.AppendUint64(in, 1)
+		in = cbor.AppendInt64(in, -5)
+		dst = Sample{}
+		if _, err := decode(&dst, in); err != nil || dst.Value != -5 {
+			t.Fatalf("decode = %+v, %v", dst, err)
+		}
+		in = cbor.AppendMapHeader(nil, 1)
+		in = cbor.AppendString(in, "sensor")
+		in = cbor.AppendString(in, "t1")
+		if _, err := decode(&dst, in); err == nil {
+			t.Fatalf("expected error for string key")
+		}
+	}
+}
+
+// TestEndpointDeprecated checks that deprecated fields still round-trip
+// and that their generated cases carry the Deprecated comment.
+func TestEndpointDeprecated(t *testing.T) {
+	orig := &Endpoint{URL: "nats://a:4222", Host: "a", Port: 4222}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst Endpoint
+	if _, err := dst.UnmarshalCBOR(b); err != nil || dst != *orig {
+		t.Fatalf("decode = %+v, %v", dst, err)
+	}
+
+	src, err := os.ReadFile("options_cbor.go")
+	if err != nil {
+		t.Fatalf("read options_cbor.go: %v", err)
+	}
+	file := string(src)
+	start := strings.Index(file, "func (x *Endpoint) MarshalCBOR")
+	if start == -1 {
+		t.Fatalf("Endpoint methods not found")
+	}
+	file = file[start:]
+	if n := strings.Count(file, "// Deprecated: use URL instead\n\t\tcase \"host\":"); n != 2 {
+		t.Fatalf("found %d deprecated host decode cases, want 2", n)
+	}
+	if !strings.Contains(file, "// Deprecated: use URL instead\n\tif x.Host != \"\" {") {
+		t.Fatalf("encode of Host is missing its Deprecated comment")
+	}
+	if end := strings.Index(file, "UnmarshalCBOR"); strings.Count(file[:end], "use URL instead") != 3 {
+		t.Fatalf("Deprecated comment emitted for fields other than Host")
+	}
+}
+
+func TestRelayedFallthrough(t *testing.T) {
+	in := cbor.AppendMapHeader(nil, 4)
+	in = cbor.AppendString(in, "id")
+	in = cbor.AppendString(in, "r1")
+	in = cbor.AppendString(in, "ttl")
+	in = cbor.AppendUint64(in, 30)
+	in = cbor.AppendString(in, "kind")
+	in = cbor.AppendString(in, "ping")
+	in = cbor.AppendString(in, "hops")
+	in = cbor.AppendArrayHeader(in, 1)
+	in = cbor.AppendString(in, "a")
+
+	for _, decode := range []func(*Relayed, []byte) ([]byte, error){(*Relayed).DecodeSafe, (*Relayed).DecodeTrusted} {
+		var dst Relayed
+		if _, err := decode(&dst, in); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.ID != "r1" || dst.Kind != "ping" {
+			t.Fatalf("decode = %+v", dst)
+		}
+		diag, _, err := cbor.DiagBytes(dst.Rest)
+		if err != nil {
+			t.Fatalf("DiagBytes(Rest) error: %v", err)
+		}
+		if want := `{"ttl": 30, "hops": ["a"]}`; diag != want {
+			t.Fatalf("Rest = %s, want %s", diag, want)
+		}
+
+		out, err := dst.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		if len(out) > dst.Msgsize() {
+			t.Fatalf("encoded %d bytes, Msgsize %d", len(out), dst.Msgsize())
+		}
+		diag, _, err = cbor.DiagBytes(out)
+		if err != nil {
+			t.Fatalf("DiagBytes error: %v", err)
+		}
+		if want := `{"id": "r1", "kind": "ping", "ttl": 30, "hops": ["a"]}`; diag != want {
+			t.Fatalf("diag = %s, want %s", diag, want)
+		}
+
+		// Decoding a map without unknown keys clears Rest.
+		known, _ := (&Relayed{ID: "r2"}).MarshalCBOR(nil)
+		if _, err := decode(&dst, known); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Rest != nil {
+			t.Fatalf("Rest = %x, want nil", dst.Rest)
+		}
+	}
+
+	bad := &Relayed{ID: "r3", Rest: cbor.Raw(cbor.AppendString(nil, "x"))}
+	if _, err := bad.MarshalCBOR(nil); err == nil {
+		t.Fatalf("MarshalCBOR with non-map Rest succeeded")
+	}
+}
+
+func TestCommandOneOf(t *testing.T) {
+	cases := []struct {
+		in   Command
+		diag string
+	}{
+		{Command{Op: "add", Spec: &ConsumerSpec{Stream: "S", Durable: "d"}}, `{"op": "add", "spec": {"stream": "S", "durable": "d"}}`},
+		{Command{Op: "add", Spec: StreamSpec{Stream: "S", Replicas: 3}}, `{"op": "add", "spec": {"stream": "S", "replicas": 3}}`},
+		{Command{Op: "noop"}, `{"op": "noop", "spec": null}`},
+	}
+	for _, tc := range cases {
+		b, err := tc.in.MarshalCBOR(nil)
+		if err != nil {
+			t.Fatalf("MarshalCBOR error: %v", err)
+		}
+		diag, _, err := cbor.DiagBytes(b)
+		if err != nil {
+			t.Fatalf("DiagBytes error: %v", err)
+		}
+		if diag != tc.diag {
+			t.Fatalf("diag = %s, want %s", diag, tc.diag)
+		}
+		for _, decode := range []func(*Command, []byte) ([]byte, error){(*Command).DecodeSafe, (*Command).DecodeTrusted} {
+			var dst Command
+			if _, err := decode(&dst, b); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			switch want := tc.in.Spec.(type) {
+			case *ConsumerSpec:
+				if got, ok := dst.Spec.(*ConsumerSpec); !ok || *got != *want {
+					t.Fatalf("Spec = %#v, want %#v", dst.Spec, want)
+				}
+			case StreamSpec:
+				if got, ok := dst.Spec.(*StreamSpec); !ok || got.Stream != want.Stream || got.Replicas != want.Replicas {
+					t.Fatalf("Spec = %#v, want %#v", dst.Spec, want)
+				}
+			case nil:
+				if dst.Spec != nil {
+					t.Fatalf("Spec = %#v, want nil", dst.Spec)
+				}
+			}
+		}
+	}
+
+	if _, err := (&Command{Spec: "other"}).MarshalCBOR(nil); !errors.Is(err, cbor.ErrUnknownVariant) {
+		t.Fatalf("MarshalCBOR(string) err = %v, want ErrUnknownVariant", err)
+	}
+	// A map with only the shared "stream" key matches neither type.
+	b, _ := (&Command{Spec: &StreamSpec{Stream: "S"}}).MarshalCBOR(nil)
+	for _, decode := range []func(*Command, []byte) ([]byte, error){(*Command).DecodeSafe, (*Command).DecodeTrusted} {
+		var dst Command
+		if _, err := decode(&dst, b); !errors.Is(err, cbor.ErrUnknownVariant) {
+			t.Fatalf("decode err = %v, want ErrUnknownVariant", err)
+		}
+	}
+}
+
+func TestGuardedSkipsLocks(t *testing.T) {
+	g := &Guarded{Hits: 3}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, err := g.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > g.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), g.Msgsize())
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"hits": 3}`; diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Guarded, []byte) ([]byte, error){(*Guarded).DecodeSafe, (*Guarded).DecodeTrusted} {
+		var dst Guarded
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Hits != 3 {
+			t.Fatalf("Hits = %d, want 3", dst.Hits)
+		}
+	}
+}
+
+func TestWebhookReadOnly(t *testing.T) {
+	if _, ok := any(&Webhook{}).(cbor.Marshaler); ok {
+		t.Fatalf("Webhook has generated MarshalCBOR despite cbor:\",readonly\"")
+	}
+	in := cbor.AppendMapHeader(nil, 2)
+	in = cbor.AppendString(in, "event")
+	in = cbor.AppendString(in, "push")
+	in = cbor.AppendString(in, "labels")
+	in = cbor.AppendMapStrStr(in, map[string]string{"repo": "cbor"})
+	for _, decode := range []func(*Webhook, []byte) ([]byte, error){(*Webhook).DecodeSafe, (*Webhook).DecodeTrusted} {
+		var dst Webhook
+		if _, err := decode(&dst, in); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Event != "push" || dst.Labels["repo"] != "cbor" {
+			t.Fatalf("decode = %+v", dst)
+		}
+	}
+}
+
+func TestAuditRecordWriteOnly(t *testing.T) {
+	if _, ok := any(&AuditRecord{}).(cbor.Unmarshaler); ok {
+		t.Fatalf("AuditRecord has generated UnmarshalCBOR despite cbor:\",writeonly\"")
+	}
+	in := AuditRecord{Actor: "ops", Action: "purge", Seq: 7}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"actor": "ops", "action": "purge", "seq": 7}`; diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+	if len(b) > in.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), in.Msgsize())
+	}
+}
+
+func TestSignedDecodesWriteOnlyStamps(t *testing.T) {
+	in := Signed{Body: "ok", Stamp: Stamp{By: "ops", At: 7}, Cosign: &Stamp{By: "qa", At: 9}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if len(b) > in.Msgsize() {
+		t.Fatalf("Msgsize = %d, encoded %d bytes", in.Msgsize(), len(b))
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"body": "ok", "stamp": ["ops", 7], "cosign": ["qa", 9]}`; diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Signed, []byte) ([]byte, error){(*Signed).DecodeSafe, (*Signed).DecodeTrusted} {
+		var out Signed
+		if _, err := decode(&out, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if out.Body != in.Body || out.Stamp != in.Stamp || out.Cosign == nil || *out.Cosign != *in.Cosign {
+			t.Fatalf("decode = %+v", out)
+		}
+	}
+}
+
+func TestOneOfRejectsWriteOnly(t *testing.T) {
+	src := "package ev\n\ntype Event interface{}\n\n" +
+		"type Start struct {\n\tAt int `cbor:\"at\"`\n}\n\n" +
+		"type Stop struct {\n\t_ struct{} `cbor:\",writeonly\"`\n\tCode int `cbor:\"code\"`\n}\n\n" +
+		"type Log struct {\n\tEvent Event `cbor:\"event,oneof=Start,Stop\"`\n}\n"
+	dir := t.TempDir()
+	in := filepath.Join(dir, "ev.go")
+	if err := os.WriteFile(in, []byte(src), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	_, err := core.Generate(in, filepath.Join(dir, "ev_cbor.go"), core.Options{})
+	if err == nil || !strings.Contains(err.Error(), `option "oneof": Stop is writeonly`) {
+		t.Fatalf("Generate error = %v", err)
+	}
+}
+
+func TestLinkURL(t *testing.T) {
+	href, _ := url.Parse("https://example.com/a?b=c")
+	next, _ := url.Parse("https://example.com/2")
+	in := Link{Href: *href, Next: next}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"href": 32("https://example.com/a?b=c"), "next": 32("https://example.com/2")}`; diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Link, []byte) ([]byte, error){(*Link).DecodeSafe, (*Link).DecodeTrusted} {
+		var dst Link
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Href.String() != href.String() || dst.Next == nil || dst.Next.String() != next.String() {
+			t.Fatalf("decode = %+v", dst)
+		}
+	}
+
+	b, err = (&Link{Href: *href}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	diag, _, err = cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if !strings.HasSuffix(diag, `"next": null}`) {
+		t.Fatalf("diag = %s, want null next", diag)
+	}
+	for _, decode := range []func(*Link, []byte) ([]byte, error){(*Link).DecodeSafe, (*Link).DecodeTrusted} {
+		dst := Link{Next: next}
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Next != nil {
+			t.Fatalf("Next = %v, want nil", dst.Next)
+		}
+	}
+}
+
+func TestTimestampsFormats(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	in := Timestamps{Default: at, Epoch: at, Text: at, Millis: at.Add(250 * time.Millisecond)}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if len(b) > in.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), in.Msgsize())
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	want := `{"default": 1(1714979289), "epoch": 1(1714979289), "text": 0("2024-05-06T07:08:09Z"), "millis": 1714979289250}`
+	if diag != want {
+		t.Fatalf("diag = %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Timestamps, []byte) ([]byte, error){(*Timestamps).DecodeSafe, (*Timestamps).DecodeTrusted} {
+		var dst Timestamps
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if !dst.Default.Equal(at) || !dst.Epoch.Equal(at) || !dst.Text.Equal(at) || !dst.Millis.Equal(in.Millis) {
+			t.Fatalf("decode = %+v, want %+v", dst, in)
+		}
+	}
+
+	// Each field accepts only its own form.
+	bad := cbor.AppendMapHeader(nil, 1)
+	bad = cbor.AppendString(bad, "text")
+	bad = cbor.AppendTime(bad, at)
+	var dst Timestamps
+	if _, err := dst.DecodeSafe(bad); err == nil {
+		t.Fatalf("expected error decoding an epoch time into an rfc3339 field")
+	}
+}
+
+// TestFallthroughNeedsRuntimeRaw checks that cbor:",fallthrough" accepts
+// the runtime's Raw under any import name, and no other package's Raw.
+func TestFallthroughNeedsRuntimeRaw(t *testing.T) {
+	cases := []struct {
+		name, imp string
+		ok        bool
+	}{
+		{"runtime", `x "github.com/synadia-labs/cbor.go/runtime"`, true},
+		{"other", `x "github.com/tinylib/msgp/msgp"`, false},
+	}
+	for _, tc := range cases {
+		dir := t.TempDir()
+		in := filepath.Join(dir, "rest.go")
+		src := "package rest\n\nimport " + tc.imp + "\n\ntype Open struct {\n\tName string `cbor:\"name\"`\n\tRest x.Raw `cbor:\",fallthrough\"`\n}\n"
+		if err := os.WriteFile(in, []byte(src), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		_, err := core.Generate(in, filepath.Join(dir, "rest_cbor.go"), core.Options{})
+		if tc.ok && err != nil {
+			t.Fatalf("%s: Generate error = %v", tc.name, err)
+		}
+		if !tc.ok && (err == nil || !strings.Contains(err.Error(), `option "fallthrough" requires a cbor.Raw field`)) {
+			t.Fatalf("%s: Generate error = %v", tc.name, err)
+		}
+	}
+}
+
+// TestPoolNestedLocks checks that structs holding locks round-trip by
+// pointer and in a slice.
+func TestPoolNestedLocks(t *testing.T) {
+	orig := &Pool{Primary: &Guarded{Hits: 1, Owner: "a"}, Members: []Guarded{{Hits: 2}, {Hits: 3, Owner: "c"}}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"primary": {"hits": 1, "owner": "a"}, "members": [{"hits": 2}, {"hits": 3, "owner": "c"}]}`; diag != want {
+		t.Fatalf("diag = %s want %s", diag, want)
+	}
+	for _, decode := range []func(*Pool, []byte) ([]byte, error){(*Pool).DecodeSafe, (*Pool).DecodeTrusted} {
+		// Reused elements are reset before decoding.
+		dst := Pool{Members: make([]Guarded, 2, 4)}
+		dst.Members[0].Owner = "stale"
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Primary == nil || dst.Primary.Hits != 1 || dst.Primary.Owner != "a" || len(dst.Members) != 2 ||
+			dst.Members[0].Hits != 2 || dst.Members[0].Owner != "" || dst.Members[1].Hits != 3 || dst.Members[1].Owner != "c" {
+			t.Fatalf("decode = %+v", dst)
+		}
+	}
+}
+
+// TestGoGenerateDirectiveOnce checks that, generated as a directory,
+// the package has a single go:generate directive, which regenerates
+// the whole directory.
+func TestGoGenerateDirectiveOnce(t *testing.T) {
+	paths, err := filepath.Glob("*_cbor.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(src), "\n") {
+			if strings.HasPrefix(line, "//go:generate ") {
+				found = append(found, path+": "+line)
+			}
+		}
+	}
+	want := paths[0] + ": //go:generate go run github.com/synadia-labs/cbor.go/cborgen -i ."
+	if len(found) != 1 || found[0] != want {
+		t.Fatalf("directives = %q, want %q", found, want)
+	}
+}
+
+// TestMarshalerSliceNeedsRuntime checks that only slices of the
+// runtime's Marshaler are encoded element by element; a Marshaler from
+// another package goes through AppendInterface.
+func TestMarshalerSliceNeedsRuntime(t *testing.T) {
+	cases := []struct{ name, imp, want string }{
+		{"runtime", `x "github.com/synadia-labs/cbor.go/runtime"`, "v.MarshalCBOR(b)"},
+		{"other", `x "encoding/json"`, "AppendInterface(b, x.Parts)"},
+	}
+	for _, tc := range cases {
+		dir := t.TempDir()
+		in := filepath.Join(dir, "doc.go")
+		src := "package doc\n\nimport " + tc.imp + "\n\ntype Doc struct {\n\tParts []x.Marshaler `cbor:\"parts\"`\n}\n"
+		if err := os.WriteFile(in, []byte(src), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		out, err := core.Generate(in, filepath.Join(dir, "doc_cbor.go"), core.Options{})
+		if err != nil {
+			t.Fatalf("%s: Generate error = %v", tc.name, err)
+		}
+		if !strings.Contains(string(out), tc.want) {
+			t.Fatalf("%s: generated code lacks %q:\n%s", tc.name, tc.want, out)
+		}
+	}
+}
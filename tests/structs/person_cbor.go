@@ -5,7 +5,10 @@ package structs
 import cbor "github.com/synadia-labs/cbor.go/runtime"
 
 func (x Person) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("name") + cbor.StringPrefixSize + len(x.Name) + cbor.StringPrefixSize + len("age") + cbor.IntSize + cbor.StringPrefixSize + len("data") + cbor.BytesPrefixSize + len(x.Data)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("age")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("data")+cbor.BytesPrefixSize+len(x.Data))
 	return
 }
 
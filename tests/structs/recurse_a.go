@@ -0,0 +1,14 @@
+package structs
+
+// RecA and RecB are mutually recursive across files; RecA is generated
+// first but must still use RecB's DecodeTrusted.
+type RecA struct {
+	Name string `cbor:"name"`
+	B    *RecB  `cbor:"b,omitempty"`
+}
+
+// Tree is self-recursive through a slice of pointers.
+type Tree struct {
+	Name     string  `cbor:"name"`
+	Children []*Tree `cbor:"children,omitempty"`
+}
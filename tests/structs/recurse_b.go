@@ -0,0 +1,7 @@
+package structs
+
+// RecB points back at RecA; see recurse_a.go.
+type RecB struct {
+	Name string `cbor:"name"`
+	A    *RecA  `cbor:"a,omitempty"`
+}
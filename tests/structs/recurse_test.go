@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMutualRecursionRoundTrip(t *testing.T) {
+	orig := &RecA{Name: "a", B: &RecB{Name: "b", A: &RecA{Name: "c"}}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, decode := range []func(*RecA, []byte) ([]byte, error){(*RecA).DecodeSafe, (*RecA).DecodeTrusted} {
+		var dst RecA
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Name != "a" || dst.B == nil || dst.B.Name != "b" || dst.B.A == nil || dst.B.A.Name != "c" || dst.B.A.B != nil {
+			t.Fatalf("mismatch: %+v", dst)
+		}
+	}
+}
+
+// TestMutualRecursionUsesTrustedPath checks that RecA.DecodeTrusted
+// decodes its RecB field via RecB.DecodeTrusted, whose zero-copy strings
+// alias the input, even though RecB is declared in a later file.
+func TestMutualRecursionUsesTrustedPath(t *testing.T) {
+	b, err := (&RecA{Name: "a", B: &RecB{Name: "zzzz"}}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	var dst RecA
+	if _, err := dst.DecodeTrusted(b); err != nil {
+		t.Fatalf("DecodeTrusted error: %v", err)
+	}
+	i := bytes.Index(b, []byte("zzzz"))
+	copy(b[i:], "yyyy")
+	if dst.B.Name != "yyyy" {
+		t.Fatalf("nested RecB was not decoded via DecodeTrusted (Name=%q)", dst.B.Name)
+	}
+}
+
+func TestSelfRecursiveTree(t *testing.T) {
+	orig := &Tree{Name: "root", Children: []*Tree{{Name: "leaf"}, {Name: "inner", Children: []*Tree{{Name: "deep"}}}}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	for _, decode := range []func(*Tree, []byte) ([]byte, error){(*Tree).DecodeSafe, (*Tree).DecodeTrusted} {
+		var dst Tree
+		if _, err := decode(&dst, b); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(dst.Children) != 2 || dst.Children[1].Children[0].Name != "deep" {
+			t.Fatalf("mismatch: %+v", dst)
+		}
+	}
+}
+
+// TestMsgsizeCoversSiblingFileStructs checks that RecA.Msgsize accounts
+// for its RecB field even though RecB is declared in a later file.
+func TestMsgsizeCoversSiblingFileStructs(t *testing.T) {
+	orig := &RecA{Name: "a", B: &RecB{Name: string(bytes.Repeat([]byte("b"), 64))}}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > orig.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize %d", len(b), orig.Msgsize())
+	}
+}
@@ -0,0 +1,35 @@
+package structs
+
+// RefNode is shared between the fields of a Graph.
+type RefNode struct {
+	Name   string `cbor:"name"`
+	Weight int    `cbor:"weight"`
+}
+
+// Graph writes each distinct RefNode once; repeated pointers across its
+// cbor:",ref" fields are encoded as shared references.
+type Graph struct {
+	Root  *RefNode   `cbor:"root,ref"`
+	Nodes []*RefNode `cbor:"nodes,ref"`
+}
+
+// Forest holds two Graphs. It has no ref fields of its own but passes
+// its reference table to both, so a node shared between the graphs is
+// written once.
+type Forest struct {
+	Left  *Graph `cbor:"left"`
+	Right Graph  `cbor:"right"`
+}
+
+// Team and Member point at each other through ref fields, forming a
+// cycle that ends in a reference instead of recursing.
+type Team struct {
+	Name string  `cbor:"name"`
+	Lead *Member `cbor:"lead,ref"`
+}
+
+// Member belongs to a Team.
+type Member struct {
+	Name string `cbor:"name"`
+	Team *Team  `cbor:"team,ref"`
+}
@@ -0,0 +1,670 @@
+// Code generated by cborgen DO NOT EDIT.
+
+package structs
+
+import cbor "github.com/synadia-labs/cbor.go/runtime"
+
+func (x RefNode) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("weight")+cbor.IntSize)
+	return
+}
+
+func (x *RefNode) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "weight")
+	b = cbor.AppendInt(b, x.Weight)
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *RefNode) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "weight":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Weight = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *RefNode) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "weight":
+
+			var tmp int
+			tmp, v, err = cbor.ReadIntBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Weight = tmp
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *RefNode) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+func (x Graph) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("root"))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("nodes"))
+	return
+}
+
+// MarshalCBOR encodes Graph as a document of its own, with a fresh
+// reference table.
+func (x *Graph) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.MarshalCBORRefs(b, new(cbor.EncodeRefs))
+}
+
+// MarshalCBORRefs implements cbor.RefMarshaler.
+func (x *Graph) MarshalCBORRefs(b []byte, refs *cbor.EncodeRefs) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "root")
+	b, err = cbor.AppendRef(b, refs, x.Root)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "nodes")
+	b, err = cbor.AppendRefSlice(b, refs, x.Nodes)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Graph) DecodeSafe(b []byte) ([]byte, error) {
+	return x.DecodeSafeRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeSafeRefs is DecodeSafe resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Graph) DecodeSafeRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "root":
+
+			x.Root, v, err = cbor.ReadRef[RefNode](v, refs)
+			if err != nil {
+				return b, err
+			}
+		case "nodes":
+
+			x.Nodes, v, err = cbor.ReadRefSlice[RefNode](v, refs, x.Nodes)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Graph) DecodeTrusted(b []byte) ([]byte, error) {
+	return x.DecodeTrustedRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeTrustedRefs is DecodeTrusted resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Graph) DecodeTrustedRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "root":
+
+			x.Root, v, err = cbor.ReadRef[RefNode](v, refs)
+			if err != nil {
+				return b, err
+			}
+		case "nodes":
+
+			x.Nodes, v, err = cbor.ReadRefSlice[RefNode](v, refs, x.Nodes)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Graph) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// UnmarshalCBORRefs implements cbor.RefUnmarshaler using the Safe path.
+func (x *Graph) UnmarshalCBORRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	return x.DecodeSafeRefs(b, refs)
+}
+
+func (x Forest) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("left")+cbor.PtrMsgsize(x.Left))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("right")+x.Right.Msgsize())
+	return
+}
+
+// MarshalCBOR encodes Forest as a document of its own, with a fresh
+// reference table.
+func (x *Forest) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.MarshalCBORRefs(b, new(cbor.EncodeRefs))
+}
+
+// MarshalCBORRefs implements cbor.RefMarshaler.
+func (x *Forest) MarshalCBORRefs(b []byte, refs *cbor.EncodeRefs) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "left")
+	b, err = cbor.AppendPtrRefs(b, refs, x.Left)
+	if err != nil {
+		return b, err
+	}
+	b = cbor.AppendString(b, "right")
+	b, err = x.Right.MarshalCBORRefs(b, refs)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Forest) DecodeSafe(b []byte) ([]byte, error) {
+	return x.DecodeSafeRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeSafeRefs is DecodeSafe resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Forest) DecodeSafeRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "left":
+
+			if x.Left == nil {
+				x.Left = new(Graph)
+			}
+			v, err = x.Left.DecodeSafeRefs(v, refs)
+			if err != nil {
+				return b, err
+			}
+		case "right":
+
+			v, err = (&x.Right).DecodeSafeRefs(v, refs)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Forest) DecodeTrusted(b []byte) ([]byte, error) {
+	return x.DecodeTrustedRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeTrustedRefs is DecodeTrusted resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Forest) DecodeTrustedRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "left":
+
+			if x.Left == nil {
+				x.Left = new(Graph)
+			}
+			v, err = x.Left.DecodeTrustedRefs(v, refs)
+			if err != nil {
+				return b, err
+			}
+		case "right":
+
+			v, err = (&x.Right).DecodeTrustedRefs(v, refs)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Forest) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// UnmarshalCBORRefs implements cbor.RefUnmarshaler using the Safe path.
+func (x *Forest) UnmarshalCBORRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	return x.DecodeSafeRefs(b, refs)
+}
+
+func (x Team) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("lead"))
+	return
+}
+
+// MarshalCBOR encodes Team as a document of its own, with a fresh
+// reference table.
+func (x *Team) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.MarshalCBORRefs(b, new(cbor.EncodeRefs))
+}
+
+// MarshalCBORRefs implements cbor.RefMarshaler.
+func (x *Team) MarshalCBORRefs(b []byte, refs *cbor.EncodeRefs) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "lead")
+	b, err = cbor.AppendRef(b, refs, x.Lead)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Team) DecodeSafe(b []byte) ([]byte, error) {
+	return x.DecodeSafeRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeSafeRefs is DecodeSafe resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Team) DecodeSafeRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "lead":
+
+			x.Lead, v, err = cbor.ReadRef[Member](v, refs)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Team) DecodeTrusted(b []byte) ([]byte, error) {
+	return x.DecodeTrustedRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeTrustedRefs is DecodeTrusted resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Team) DecodeTrustedRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "lead":
+
+			x.Lead, v, err = cbor.ReadRef[Member](v, refs)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Team) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// UnmarshalCBORRefs implements cbor.RefUnmarshaler using the Safe path.
+func (x *Team) UnmarshalCBORRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	return x.DecodeSafeRefs(b, refs)
+}
+
+func (x Member) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("name")+cbor.StringPrefixSize+len(x.Name))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("team"))
+	return
+}
+
+// MarshalCBOR encodes Member as a document of its own, with a fresh
+// reference table.
+func (x *Member) MarshalCBOR(b []byte) ([]byte, error) {
+	return x.MarshalCBORRefs(b, new(cbor.EncodeRefs))
+}
+
+// MarshalCBORRefs implements cbor.RefMarshaler.
+func (x *Member) MarshalCBORRefs(b []byte, refs *cbor.EncodeRefs) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	b = cbor.AppendMapHeader(b, 2)
+	var err error
+	b = cbor.AppendString(b, "name")
+	b = cbor.AppendString(b, x.Name)
+	b = cbor.AppendString(b, "team")
+	b, err = cbor.AppendRef(b, refs, x.Team)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Member) DecodeSafe(b []byte) ([]byte, error) {
+	return x.DecodeSafeRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeSafeRefs is DecodeSafe resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Member) DecodeSafeRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "name":
+
+			var tmp string
+			tmp, v, err = cbor.ReadStringBytes(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = tmp
+		case "team":
+
+			x.Team, v, err = cbor.ReadRef[Team](v, refs)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Member) DecodeTrusted(b []byte) ([]byte, error) {
+	return x.DecodeTrustedRefs(b, new(cbor.DecodeRefs))
+}
+
+// DecodeTrustedRefs is DecodeTrusted resolving shared references through
+// refs, the table of the enclosing document.
+func (x *Member) DecodeTrustedRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "name":
+
+			var tmpBytes []byte
+			tmpBytes, v, err = cbor.ReadStringZC(v)
+			if err != nil {
+				return b, err
+			}
+			x.Name = cbor.UnsafeString(tmpBytes)
+		case "team":
+
+			x.Team, v, err = cbor.ReadRef[Team](v, refs)
+			if err != nil {
+				return b, err
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Member) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
+
+// UnmarshalCBORRefs implements cbor.RefUnmarshaler using the Safe path.
+func (x *Member) UnmarshalCBORRefs(b []byte, refs *cbor.DecodeRefs) ([]byte, error) {
+	return x.DecodeSafeRefs(b, refs)
+}
@@ -0,0 +1,147 @@
+package structs
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
+)
+
+func TestGraphSharedRefs(t *testing.T) {
+	a := &RefNode{Name: "a", Weight: 1}
+	b := &RefNode{Name: "b", Weight: 2}
+	orig := &Graph{Root: b, Nodes: []*RefNode{a, b, a, nil}}
+	msg, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(msg)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	want := `{"root": 28({"name": "b", "weight": 2}), "nodes": [28({"name": "a", "weight": 1}), 29(0), 29(1), null]}`
+	if diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+
+	for _, decode := range []func(*Graph, []byte) ([]byte, error){(*Graph).DecodeSafe, (*Graph).DecodeTrusted} {
+		var dst Graph
+		rest, err := decode(&dst, msg)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("leftover bytes: %d", len(rest))
+		}
+		if len(dst.Nodes) != 4 || dst.Nodes[3] != nil {
+			t.Fatalf("unexpected nodes %+v", dst.Nodes)
+		}
+		if dst.Root != dst.Nodes[1] || dst.Nodes[0] != dst.Nodes[2] {
+			t.Fatalf("shared pointers were not preserved")
+		}
+		if *dst.Root != *b || *dst.Nodes[0] != *a {
+			t.Fatalf("got root %+v, first node %+v", *dst.Root, *dst.Nodes[0])
+		}
+	}
+}
+
+func TestGraphRejectsUnknownRef(t *testing.T) {
+	// {"root": 29(0)} refers to a value that was never marked shareable.
+	msg := cbor.AppendMapHeader(nil, 1)
+	msg = cbor.AppendString(msg, "root")
+	msg = cbor.AppendTag(msg, 29)
+	msg = cbor.AppendUint64(msg, 0)
+	var dst Graph
+	if _, err := dst.DecodeSafe(msg); !errors.Is(err, cbor.ErrInvalidReference) {
+		t.Fatalf("expected ErrInvalidReference, got %v", err)
+	}
+
+	// Untagged values are still accepted.
+	plain, err := (&RefNode{Name: "p"}).MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	msg = cbor.AppendMapHeader(nil, 1)
+	msg = cbor.AppendString(msg, "root")
+	msg = append(msg, plain...)
+	if _, err := dst.DecodeSafe(msg); err != nil || dst.Root == nil || dst.Root.Name != "p" {
+		t.Fatalf("plain root: %+v, %v", dst.Root, err)
+	}
+}
+
+// TestGraphRejectsHugeNodeCount checks that a nodes header longer than
+// the input is reported as short input rather than sizing the slice,
+// including lengths that do not fit an int on 32-bit platforms.
+func TestGraphRejectsHugeNodeCount(t *testing.T) {
+	for _, n := range []uint32{3, 1<<31 - 1, 1<<32 - 1} {
+		msg := cbor.AppendMapHeader(nil, 1)
+		msg = cbor.AppendString(msg, "nodes")
+		msg = cbor.AppendArrayHeader(msg, n)
+		msg = cbor.AppendNil(msg)
+		for _, decode := range []func(*Graph, []byte) ([]byte, error){(*Graph).DecodeSafe, (*Graph).DecodeTrusted} {
+			dst := Graph{Nodes: make([]*RefNode, 0, 8)}
+			if _, err := decode(&dst, msg); !errors.Is(err, cbor.ErrShortBytes) {
+				t.Fatalf("len %d: expected ErrShortBytes, got %v", n, err)
+			}
+		}
+	}
+}
+
+func TestForestSharesRefsAcrossGraphs(t *testing.T) {
+	shared := &RefNode{Name: "s", Weight: 1}
+	orig := &Forest{
+		Left:  &Graph{Root: shared},
+		Right: Graph{Nodes: []*RefNode{shared}},
+	}
+	msg, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(msg)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	want := `{"left": {"root": 28({"name": "s", "weight": 1}), "nodes": null}, "right": {"root": null, "nodes": [29(0)]}}`
+	if diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Forest, []byte) ([]byte, error){(*Forest).DecodeSafe, (*Forest).DecodeTrusted} {
+		var dst Forest
+		if _, err := decode(&dst, msg); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if dst.Left == nil || dst.Left.Root == nil || len(dst.Right.Nodes) != 1 || dst.Right.Nodes[0] != dst.Left.Root {
+			t.Fatalf("shared node not preserved across graphs: %+v", dst)
+		}
+	}
+}
+
+func TestTeamMemberCycle(t *testing.T) {
+	team := &Team{Name: "core"}
+	lead := &Member{Name: "ada", Team: team}
+	team.Lead = lead
+	msg, err := team.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(msg)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	// The root is not itself behind a ref field, so the cycle closes
+	// at its first shared copy.
+	want := `{"name": "core", "lead": 28({"name": "ada", "team": 28({"name": "core", "lead": 29(0)})})}`
+	if diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+	for _, decode := range []func(*Team, []byte) ([]byte, error){(*Team).DecodeSafe, (*Team).DecodeTrusted} {
+		var dst Team
+		if _, err := decode(&dst, msg); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		m := dst.Lead
+		if m == nil || m.Name != "ada" || m.Team == nil || m.Team.Lead != m {
+			t.Fatalf("cycle not restored: %+v", dst)
+		}
+	}
+}
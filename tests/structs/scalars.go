@@ -33,3 +33,10 @@ type Nested struct {
 	Base Scalars  `cbor:"base"`
 	Ptr  *Scalars `cbor:"ptr,omitempty"`
 }
+
+// Timeouts exercises *time.Duration fields, which encode as null when nil.
+type Timeouts struct {
+	Idle  *time.Duration `cbor:"idle"`
+	Ack   *time.Duration `cbor:"ack,omitempty"`
+	Retry *time.Duration `cbor:"retry,omitempty"`
+}
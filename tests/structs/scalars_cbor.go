@@ -9,7 +9,27 @@ import (
 )
 
 func (x Scalars) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("s") + cbor.StringPrefixSize + len(x.S) + cbor.StringPrefixSize + len("b") + cbor.BoolSize + cbor.StringPrefixSize + len("i") + cbor.IntSize + cbor.StringPrefixSize + len("i8") + cbor.Int8Size + cbor.StringPrefixSize + len("i16") + cbor.Int16Size + cbor.StringPrefixSize + len("i32") + cbor.Int32Size + cbor.StringPrefixSize + len("i64") + cbor.Int64Size + cbor.StringPrefixSize + len("u") + cbor.UintSize + cbor.StringPrefixSize + len("u8") + cbor.Uint8Size + cbor.StringPrefixSize + len("u16") + cbor.Uint16Size + cbor.StringPrefixSize + len("u32") + cbor.Uint32Size + cbor.StringPrefixSize + len("u64") + cbor.Uint64Size + cbor.StringPrefixSize + len("f32") + cbor.Float32Size + cbor.StringPrefixSize + len("f64") + cbor.Float64Size + cbor.StringPrefixSize + len("data") + cbor.BytesPrefixSize + len(x.Data) + cbor.StringPrefixSize + len("ints") + cbor.ArrayHeaderSize + len(x.Ints)*cbor.IntSize + cbor.StringPrefixSize + len("names") + cbor.ArrayHeaderSize + len(x.Names)*cbor.StringPrefixSize + cbor.StringPrefixSize + len("scores") + cbor.MapHeaderSize + len(x.Scores)*(cbor.StringPrefixSize+cbor.IntSize) + cbor.StringPrefixSize + len("t") + cbor.TimeSize + cbor.StringPrefixSize + len("d") + cbor.DurationSize
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("s")+cbor.StringPrefixSize+len(x.S))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("b")+cbor.BoolSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i")+cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i8")+cbor.Int8Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i16")+cbor.Int16Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i32")+cbor.Int32Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("i64")+cbor.Int64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u")+cbor.UintSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u8")+cbor.Uint8Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u16")+cbor.Uint16Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u32")+cbor.Uint32Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("u64")+cbor.Uint64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("f32")+cbor.Float32Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("f64")+cbor.Float64Size)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("data")+cbor.BytesPrefixSize+len(x.Data))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ints")+cbor.ArrayHeaderSize+len(x.Ints)*cbor.IntSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("names")+cbor.ArrayHeaderSize+len(x.Names)*cbor.StringPrefixSize+cbor.StringsLen(x.Names))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("scores")+cbor.MapHeaderSize+len(x.Scores)*(cbor.StringPrefixSize+cbor.IntSize)+cbor.MapKeysLen(x.Scores))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("t")+cbor.TimeSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("d")+cbor.DurationSize)
 	return
 }
 
@@ -555,7 +575,10 @@ func (x *Scalars) UnmarshalCBOR(b []byte) ([]byte, error) {
 }
 
 func (x Nested) Msgsize() (s int) {
-	s = cbor.MapHeaderSize + cbor.StringPrefixSize + len("id") + cbor.StringPrefixSize + len(x.ID)
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("id")+cbor.StringPrefixSize+len(x.ID))
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("base")+x.Base.Msgsize())
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ptr")+cbor.PtrMsgsize(x.Ptr))
 	return
 }
 
@@ -693,3 +716,197 @@ func (x *Nested) DecodeTrusted(b []byte) ([]byte, error) {
 func (x *Nested) UnmarshalCBOR(b []byte) ([]byte, error) {
 	return x.DecodeSafe(b)
 }
+
+func (x Timeouts) Msgsize() (s int) {
+	s = cbor.MapHeaderSize
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("idle")+cbor.DurationSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("ack")+cbor.DurationSize)
+	s = cbor.AddSize(s, cbor.StringPrefixSize+len("retry")+cbor.DurationSize)
+	return
+}
+
+func (x *Timeouts) MarshalCBOR(b []byte) ([]byte, error) {
+	if x == nil {
+		return cbor.AppendNil(b), nil
+	}
+
+	b = cbor.Require(b, x.Msgsize())
+
+	count := uint32(1)
+	if x.Ack != nil {
+		count++
+	}
+	if x.Retry != nil {
+		count++
+	}
+	b = cbor.AppendMapHeader(b, count)
+
+	b = cbor.AppendString(b, "idle")
+	if x.Idle == nil {
+		b = cbor.AppendNil(b)
+	} else {
+		b = cbor.AppendDuration(b, *x.Idle)
+	}
+	if x.Ack != nil {
+
+		b = cbor.AppendString(b, "ack")
+		if x.Ack == nil {
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendDuration(b, *x.Ack)
+		}
+	}
+	if x.Retry != nil {
+
+		b = cbor.AppendString(b, "retry")
+		if x.Retry == nil {
+			b = cbor.AppendNil(b)
+		} else {
+			b = cbor.AppendDuration(b, *x.Retry)
+		}
+	}
+
+	return b, nil
+}
+
+// DecodeSafe decodes using validated, allocating string handling.
+func (x *Timeouts) DecodeSafe(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		key, v, err := cbor.ReadStringBytes(rest)
+		if err != nil {
+			return b, err
+		}
+		switch key {
+		case "idle":
+
+			if cbor.IsNil(v) {
+				x.Idle = nil
+				v = v[1:]
+			} else {
+				if x.Idle == nil {
+					x.Idle = new(time.Duration)
+				}
+				*x.Idle, v, err = cbor.ReadDurationBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "ack":
+
+			if cbor.IsNil(v) {
+				x.Ack = nil
+				v = v[1:]
+			} else {
+				if x.Ack == nil {
+					x.Ack = new(time.Duration)
+				}
+				*x.Ack, v, err = cbor.ReadDurationBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "retry":
+
+			if cbor.IsNil(v) {
+				x.Retry = nil
+				v = v[1:]
+			} else {
+				if x.Retry == nil {
+					x.Retry = new(time.Duration)
+				}
+				*x.Retry, v, err = cbor.ReadDurationBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// DecodeTrusted decodes using zero-copy strings and no per-string UTF-8 validation.
+func (x *Timeouts) DecodeTrusted(b []byte) ([]byte, error) {
+	if x == nil {
+		return b, cbor.ErrNotNil
+	}
+	sz, rest, err := cbor.ReadMapHeaderBytes(b)
+	if err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		keyBytes, v, err := cbor.ReadStringZC(rest)
+		if err != nil {
+			return b, err
+		}
+		key := cbor.UnsafeString(keyBytes)
+		switch key {
+		case "idle":
+
+			if cbor.IsNil(v) {
+				x.Idle = nil
+				v = v[1:]
+			} else {
+				if x.Idle == nil {
+					x.Idle = new(time.Duration)
+				}
+				*x.Idle, v, err = cbor.ReadDurationBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "ack":
+
+			if cbor.IsNil(v) {
+				x.Ack = nil
+				v = v[1:]
+			} else {
+				if x.Ack == nil {
+					x.Ack = new(time.Duration)
+				}
+				*x.Ack, v, err = cbor.ReadDurationBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		case "retry":
+
+			if cbor.IsNil(v) {
+				x.Retry = nil
+				v = v[1:]
+			} else {
+				if x.Retry == nil {
+					x.Retry = new(time.Duration)
+				}
+				*x.Retry, v, err = cbor.ReadDurationBytes(v)
+				if err != nil {
+					return b, err
+				}
+			}
+		default:
+			v, err = cbor.Skip(v)
+			if err != nil {
+				return b, err
+			}
+		}
+		rest = v
+	}
+	return rest, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler using the Safe path.
+func (x *Timeouts) UnmarshalCBOR(b []byte) ([]byte, error) {
+	return x.DecodeSafe(b)
+}
@@ -1,8 +1,11 @@
 package structs
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	cbor "github.com/synadia-labs/cbor.go/runtime"
 )
 
 type scalarsDecoder struct {
@@ -188,3 +191,71 @@ func TestNestedRoundTripSafeAndTrusted(t *testing.T) {
 		})
 	}
 }
+
+func TestNestedMsgsizeIncludesStructFields(t *testing.T) {
+	base := Scalars{S: "base", Data: []byte{1, 2, 3}, Names: []string{"a"}}
+	withoutPtr := Nested{ID: "n", Base: base}
+	withPtr := Nested{ID: "n", Base: base, Ptr: &Scalars{S: "pointer"}}
+
+	// Nested struct and pointer fields contribute their own Msgsize.
+	if got, want := withoutPtr.Msgsize(), base.Msgsize(); got < want {
+		t.Fatalf("Msgsize = %d, want at least Base.Msgsize() = %d", got, want)
+	}
+	if got, want := withPtr.Msgsize()-withoutPtr.Msgsize(), withPtr.Ptr.Msgsize()-cbor.NilSize; got != want {
+		t.Fatalf("Ptr contributed %d bytes, want %d", got, want)
+	}
+
+	b, err := withPtr.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > withPtr.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize estimated %d", len(b), withPtr.Msgsize())
+	}
+}
+
+func TestScalarsMsgsizeCountsStringContents(t *testing.T) {
+	long := strings.Repeat("x", 1000)
+	in := Scalars{Names: []string{long, long}, Scores: map[string]int{long: 1}}
+	b, err := in.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	if len(b) > in.Msgsize() {
+		t.Fatalf("encoded %d bytes, Msgsize estimated %d", len(b), in.Msgsize())
+	}
+	if n := testing.AllocsPerRun(10, func() { _, _ = in.MarshalCBOR(nil) }); n != 1 {
+		t.Fatalf("MarshalCBOR made %v allocations, want 1", n)
+	}
+}
+
+func TestTimeoutsPtrDuration(t *testing.T) {
+	ack := 30 * time.Second
+	orig := &Timeouts{Ack: &ack}
+	b, err := orig.MarshalCBOR(nil)
+	if err != nil {
+		t.Fatalf("MarshalCBOR error: %v", err)
+	}
+	diag, _, err := cbor.DiagBytes(b)
+	if err != nil {
+		t.Fatalf("DiagBytes error: %v", err)
+	}
+	if want := `{"idle": null, "ack": 30000000000}`; diag != want {
+		t.Fatalf("diag %s, want %s", diag, want)
+	}
+
+	for _, decode := range []func(*Timeouts, []byte) ([]byte, error){(*Timeouts).DecodeSafe, (*Timeouts).DecodeTrusted} {
+		idle := time.Minute
+		dst := Timeouts{Idle: &idle}
+		rest, err := decode(&dst, b)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("leftover bytes: %d", len(rest))
+		}
+		if dst.Idle != nil || dst.Ack == nil || *dst.Ack != ack || dst.Retry != nil {
+			t.Fatalf("unexpected decode result %+v", dst)
+		}
+	}
+}